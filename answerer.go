@@ -0,0 +1,217 @@
+package gosepp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// Answerer accepts incoming calls on a signaling connection. This is the
+// callee role, as needed by media servers and gateways.
+//
+// The Answerer consumes the receive channel of the GoSepp, so it must
+// not be read by anyone else.
+type Answerer struct {
+	sepp       *GoSepp
+	incomingCh chan *IncomingCall
+	logger     Logger
+
+	mu       sync.Mutex
+	sessions map[CallID]*answeredCall
+}
+
+// answeredCall routes messages to the dispatcher of an accepted call.
+type answeredCall struct {
+	inbox chan MsgInterface
+	// done is closed when the dispatcher has stopped.
+	done chan struct{}
+}
+
+// IncomingCall is a call_start received by an Answerer. It must be
+// either accepted or rejected.
+type IncomingCall struct {
+	answerer *Answerer
+	msg      *MsgCallStart
+	handlers callHandlers
+}
+
+// NewAnswerer returns an Answerer dispatching the messages received on
+// sepp.
+func NewAnswerer(sepp *GoSepp, logger Logger) *Answerer {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	a := &Answerer{
+		sepp:       sepp,
+		incomingCh: make(chan *IncomingCall, 1),
+		logger:     logger,
+		sessions:   make(map[CallID]*answeredCall),
+	}
+	go a.route()
+	return a
+}
+
+// IncomingCh get the channel where incoming calls are delivered.
+// The channel is closed when the GoSepp is stopped.
+func (a *Answerer) IncomingCh() <-chan *IncomingCall {
+	return a.incomingCh
+}
+
+func (a *Answerer) route() {
+	defer func() {
+		close(a.incomingCh)
+		a.mu.Lock()
+		for callID, ac := range a.sessions {
+			close(ac.inbox)
+			delete(a.sessions, callID)
+		}
+		a.mu.Unlock()
+	}()
+
+	for msg := range a.sepp.RcvCh() {
+		if m, ok := msg.(*MsgCallStart); ok {
+			a.incomingCh <- &IncomingCall{answerer: a, msg: m}
+			continue
+		}
+		callID := CallIDOf(msg)
+		a.mu.Lock()
+		ac, ok := a.sessions[callID]
+		a.mu.Unlock()
+		if !ok {
+			a.logger.Debug("Dropping %s for unknown call %s.", msg.GetType(), callID)
+			continue
+		}
+		select {
+		case ac.inbox <- msg:
+		case <-ac.done:
+		}
+	}
+}
+
+// removeSession stops routing messages to the session with callID.
+func (a *Answerer) removeSession(callID CallID) {
+	a.mu.Lock()
+	delete(a.sessions, callID)
+	a.mu.Unlock()
+}
+
+// From returns the id of the caller.
+func (ic *IncomingCall) From() string {
+	return ic.msg.From
+}
+
+// To returns the id the caller addressed.
+func (ic *IncomingCall) To() string {
+	return ic.msg.To
+}
+
+// Data returns the data of the received call_start.
+func (ic *IncomingCall) Data() MsgCallStartData {
+	return ic.msg.Data
+}
+
+// SetTerminatedHandler sets the handler which is called when the call
+// is terminated. Must be set-up before accepting the call.
+func (ic *IncomingCall) SetTerminatedHandler(handler func()) {
+	ic.handlers.termination = handler
+}
+
+// SetSDPUpdateHandler sets the handler which is called if the caller
+// sends an updated sdp. Must be set-up before accepting the call.
+func (ic *IncomingCall) SetSDPUpdateHandler(handler func(Sdp)) {
+	ic.handlers.sdpUpdate = handler
+}
+
+// SetHoldHandler sets the handler which is called if the caller puts
+// the call on hold or resumes it. Must be set-up before accepting the
+// call.
+func (ic *IncomingCall) SetHoldHandler(handler func(onHold bool)) {
+	ic.handlers.hold = handler
+}
+
+// Accept the call with the given sdp answer. The returned session
+// dispatches the messages of this call.
+func (ic *IncomingCall) Accept(ctx context.Context, sdp Sdp) (*CallSession, error) {
+	callID, err := newCallID()
+	if err != nil {
+		return nil, err
+	}
+
+	a := ic.answerer
+	ac := &answeredCall{
+		inbox: make(chan MsgInterface, 1),
+		done:  make(chan struct{}),
+	}
+	session := newCallSession(a.sepp, ac.inbox, ic.msg.To, ic.msg.From,
+		callID, sdp, ic.msg.Data.Sdp, ic.handlers, a.logger)
+	session.onDone = func() {
+		close(ac.done)
+		a.removeSession(callID)
+	}
+
+	// register before accepting, so no message of the call is lost.
+	a.mu.Lock()
+	a.sessions[callID] = ac
+	a.mu.Unlock()
+
+	if err := a.sepp.SendMsg(MsgCallAccepted{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallAccepted,
+			From: ic.msg.To,
+			To:   ic.msg.From,
+		},
+		Data: MsgCallAcceptedData{
+			CallID: string(callID),
+			Sdp:    sdp},
+	}); err != nil {
+		a.removeSession(callID)
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	session.start(context.Background())
+	return session, nil
+}
+
+// Reject the call with the given reject code.
+func (ic *IncomingCall) Reject(ctx context.Context, code int) error {
+	if err := ic.answerer.sepp.SendMsg(MsgCallRejected{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallRejected,
+			From: ic.msg.To,
+			To:   ic.msg.From,
+		},
+		Data: MsgCallRejectedData{
+			RejectCode: code},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Redirect the caller to the conference target instead of
+// accepting the call.
+func (ic *IncomingCall) Redirect(ctx context.Context, target string) error {
+	if err := ic.answerer.sepp.SendMsg(MsgCallRedirect{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallRedirect,
+			From: ic.msg.To,
+			To:   ic.msg.From,
+		},
+		Data: MsgCallRedirectData{
+			Target: target},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// newCallID returns a random call-id.
+func newCallID() (CallID, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return CallID(hex.EncodeToString(b)), nil
+}
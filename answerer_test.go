@@ -0,0 +1,99 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnswererAcceptAndRemoteTerminate(t *testing.T) {
+	r := &relay{}
+	srv := newFakeServer(t, r.handle)
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	answerer := NewAnswerer(sepp, nil)
+
+	terminated := make(chan struct{})
+	go func() {
+		ic := <-answerer.IncomingCh()
+		if ic.From() != "client" || ic.Data().DisplayName != "bot" {
+			t.Errorf("unexpected call_start %+v", ic.Data())
+		}
+		ic.SetTerminatedHandler(func() { close(terminated) })
+		if _, err := ic.Accept(context.Background(),
+			Sdp{SdpType: "answer", Sdp: "answer-sdp"}); err != nil {
+			t.Errorf("accept failed: %s", err)
+		}
+	}()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if session.RemoteSdp().Sdp != "answer-sdp" {
+		t.Fatalf("unexpected sdp %q", session.RemoteSdp().Sdp)
+	}
+	if err := session.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	select {
+	case <-terminated:
+	case <-ctx.Done():
+		t.Fatalf("answerer did not observe termination")
+	}
+}
+
+func TestAnswererRedirect(t *testing.T) {
+	r := &relay{}
+	srv := newFakeServer(t, r.handle)
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	answerer := NewAnswerer(sepp, nil)
+	go func() {
+		ic := <-answerer.IncomingCh()
+		if err := ic.Redirect(context.Background(), "other-conf"); err != nil {
+			t.Errorf("redirect failed: %s", err)
+		}
+	}()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	var redirected *CallRedirectedError
+	if !errors.As(err, &redirected) || redirected.Target != "other-conf" {
+		t.Fatalf("expected redirect to other-conf, got %v", err)
+	}
+}
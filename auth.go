@@ -0,0 +1,88 @@
+package gosepp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// authPlacement selects where the auth token is passed on dial.
+type authPlacement int
+
+const (
+	authInHeader authPlacement = iota
+	authInQuery
+	authInCookie
+)
+
+// WithAuthQuery passes the auth token as query parameter param of the
+// dial url instead of the Authorization header, as browser clients must
+// do. Use it behind proxies which strip Authorization headers.
+func WithAuthQuery(param string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.authPlacement = authInQuery
+		rtm.authName = param
+	}
+}
+
+// WithAuthCookie passes the auth token as cookie name instead of the
+// Authorization header.
+func WithAuthCookie(name string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.authPlacement = authInCookie
+		rtm.authName = name
+	}
+}
+
+// WithRequestHeader adds header to the connect requests, e.g. for
+// routing by proxies. The auth headers take precedence.
+func WithRequestHeader(header http.Header) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.requestHeader = header.Clone()
+	}
+}
+
+// applyAuth adds token to the dial request and returns the url to dial.
+// u itself is not modified, so the token doesn't end up in logs.
+func (rtm *GoSepp) applyAuth(u *url.URL, header http.Header, token string) *url.URL {
+	switch rtm.authPlacement {
+	case authInQuery:
+		withToken := *u
+		query := withToken.Query()
+		query.Set(rtm.authName, token)
+		withToken.RawQuery = query.Encode()
+		return &withToken
+	case authInCookie:
+		header.Add("Cookie", (&http.Cookie{Name: rtm.authName, Value: token}).String())
+	default:
+		header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	return u
+}
+
+// UpdateAuthToken replaces the bearer token used for subsequent
+// reconnects, so rotating tokens doesn't require a new client. If the
+// server supports the auth message, the live connection is
+// re-authenticated as well.
+func (rtm *GoSepp) UpdateAuthToken(token string) error {
+	rtm.mu.Lock()
+	rtm.authToken = token
+	rtm.mu.Unlock()
+
+	if !rtm.isConnected() || !rtm.Supports(MsgTypeAuth) {
+		return nil
+	}
+	if err := rtm.SendMsg(MsgAuth{
+		MsgBase: MsgBase{Type: MsgTypeAuth},
+		Data:    MsgAuthData{Token: token},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+func (rtm *GoSepp) token() string {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.authToken
+}
@@ -0,0 +1,62 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateAuthToken(t *testing.T) {
+	tokens := make(chan string, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		tokens <- c.header.Get("Authorization")
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "old", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+	if err := sepp.UpdateAuthToken("new"); err != nil {
+		t.Fatalf("update failed: %s", err)
+	}
+	// drop the connection, so the client reconnects.
+	sepp.conn().Close()
+
+	for _, want := range []string{"Bearer old", "Bearer new"} {
+		select {
+		case token := <-tokens:
+			if token != want {
+				t.Fatalf("expected %q, got %q", want, token)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %q", want)
+		}
+	}
+}
+
+func TestAuthQuery(t *testing.T) {
+	tokens := make(chan string, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		tokens <- c.query.Get("token") + "|" + c.header.Get("Authorization")
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL()+"?foo=bar", "secret", nil, nil,
+		WithAuthQuery("token"))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	select {
+	case token := <-tokens:
+		if token != "secret|" {
+			t.Fatalf("unexpected auth %q", token)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,53 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+)
+
+// handleAuthExpired refreshes the token from the TokenProvider and
+// re-authenticates, or reconnects with the new token if the server
+// doesn't support re-authentication. Without provider, or if the
+// refresh fails, connecting is given up with ErrAuthExpired instead of
+// reconnecting with the expired token.
+func (rtm *GoSepp) handleAuthExpired(ctx context.Context, data MsgAuthExpiredData) {
+	rtm.logger.Warn("Auth token expired [%s].", data.Reason)
+	if rtm.tokenProvider == nil {
+		rtm.disconnect(fmt.Errorf("%w: %s", ErrAuthExpired, data.Reason))
+		return
+	}
+	if err := rtm.refreshToken(ctx); err != nil {
+		rtm.disconnect(fmt.Errorf("%w: failed to refresh auth token: %s",
+			ErrAuthExpired, err))
+		return
+	}
+	if rtm.Supports(MsgTypeAuth) {
+		if err := rtm.UpdateAuthToken(rtm.token()); err == nil {
+			return
+		}
+	}
+	// reconnect with the new token.
+	rtm.disconnect(nil)
+}
+
+// handleForceDisconnect closes the connection as requested by the
+// server, and gives up connecting unless asked to reconnect.
+func (rtm *GoSepp) handleForceDisconnect(data MsgForceDisconnectData) {
+	rtm.logger.Warn("Disconnected by server [%s].", data.Reason)
+	if data.Reconnect {
+		rtm.disconnect(nil)
+		return
+	}
+	rtm.disconnect(fmt.Errorf("%w: %s", ErrForceDisconnected, data.Reason))
+}
+
+// disconnect closes the current connection. If err is set, connecting
+// is given up with err, else the connection is re-established.
+func (rtm *GoSepp) disconnect(err error) {
+	if err != nil && !rtm.accepted {
+		rtm.giveUp(err)
+	}
+	if wsClient := rtm.conn(); wsClient != nil {
+		wsClient.Close()
+	}
+}
@@ -0,0 +1,67 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthExpiredRefreshesToken(t *testing.T) {
+	var conns int32
+	tokens := make(chan string, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		tokens <- c.header.Get("Authorization")
+		if atomic.AddInt32(&conns, 1) == 1 {
+			c.write(MsgAuthExpired{MsgBase: MsgBase{Type: MsgTypeAuthExpired}})
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "expired", nil, nil,
+		WithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+			return "fresh", nil
+		}), time.Minute))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	for _, expected := range []string{"Bearer expired", "Bearer fresh"} {
+		select {
+		case token := <-tokens:
+			if token != expected {
+				t.Fatalf("expected %q, got %q", expected, token)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %q", expected)
+		}
+	}
+}
+
+func TestAuthExpiredWithoutProvider(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.write(MsgAuthExpired{MsgBase: MsgBase{Type: MsgTypeAuthExpired},
+			Data: MsgAuthExpiredData{Reason: "exp"}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "expired", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	// the message is delivered, then connecting is given up.
+	if msg := <-sepp.RcvCh(); msg.GetType() != MsgTypeAuthExpired {
+		t.Fatalf("unexpected message %s", msg.GetType())
+	}
+	for range sepp.RcvCh() {
+	}
+	if !errors.Is(sepp.Err(), ErrAuthExpired) {
+		t.Fatalf("expected ErrAuthExpired, got %v", sepp.Err())
+	}
+}
@@ -0,0 +1,156 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// BatchCapability is advertised in the hello message by ends which accept
+// several messages in a single frame, encoded as json array.
+const BatchCapability = "batch"
+
+// maxBatchSize limits the number of messages coalesced into one frame.
+const maxBatchSize = 64
+
+// WithBatching coalesces messages queued within interval into a single
+// frame, reducing the frame overhead of bursty senders. Batches are only
+// sent if the remote end advertised BatchCapability, see WithHandshake.
+// Otherwise every message is sent in a frame of its own.
+func WithBatching(interval time.Duration) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.batchInterval = interval
+	}
+}
+
+// batching reports whether queued messages are coalesced.
+func (rtm *GoSepp) batching() bool {
+	return rtm.batchInterval > 0 && rtm.Supports(BatchCapability)
+}
+
+// collectBatch collects further frames queued within the batch interval.
+// A binary frame ends the batch. ok is false if the send channel was
+// closed.
+func (rtm *GoSepp) collectBatch(first frame) (batch []frame, ok bool) {
+	batch = []frame{first}
+	timer := time.NewTimer(rtm.batchInterval)
+	defer timer.Stop()
+	for len(batch) < maxBatchSize {
+		select {
+		case f, ok := <-rtm.sendCh:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, f)
+			if f.messageType != TextMessage {
+				return batch, true
+			}
+		case f, ok := <-rtm.bulkCh:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, f)
+			if f.messageType != TextMessage {
+				return batch, true
+			}
+		case <-timer.C:
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+// flush writes frames, coalescing consecutive text frames.
+func (rtm *GoSepp) flush(frames []frame, seq *seqCounter) {
+	defer rtm.queue.done(len(frames))
+	wsClient := rtm.conn()
+	if wsClient == nil {
+		confirmFrames(frames, ErrNotConnected)
+		return
+	}
+	var texts [][]byte
+	var textFrames []frame
+	for _, f := range frames {
+		if f.messageType != TextMessage {
+			confirmFrames(textFrames, rtm.writeTexts(wsClient, texts))
+			texts, textFrames = nil, nil
+			confirmFrames([]frame{f}, rtm.write(wsClient, f.messageType, f.data))
+			continue
+		}
+		texts = append(texts, rtm.prepare(f.data, seq.next(wsClient)))
+		textFrames = append(textFrames, f)
+	}
+	confirmFrames(textFrames, rtm.writeTexts(wsClient, texts))
+	written := time.Now()
+	for _, f := range frames {
+		rtm.qos.queued(written.Sub(f.enqueued))
+	}
+}
+
+// writeTexts writes texts in as few frames as the size limit allows.
+// It returns the first write error.
+func (rtm *GoSepp) writeTexts(wsClient Conn, texts [][]byte) error {
+	var err error
+	for len(texts) > 0 {
+		// the envelope adds brackets and a comma per message.
+		n, size := 1, len(texts[0])+2
+		for ; n < len(texts); n++ {
+			if rtm.maxMessageSize > 0 && size+len(texts[n])+1 > rtm.maxMessageSize {
+				break
+			}
+			size += len(texts[n]) + 1
+		}
+		var writeErr error
+		if n == 1 {
+			writeErr = rtm.write(wsClient, TextMessage, texts[0], texts[0])
+		} else {
+			envelope := append([]byte{'['}, bytes.Join(texts[:n], []byte{','})...)
+			writeErr = rtm.write(wsClient, TextMessage, append(envelope, ']'), texts[:n]...)
+		}
+		if err == nil {
+			err = writeErr
+		}
+		texts = texts[n:]
+	}
+	return err
+}
+
+// write sends a frame and records the contained messages in the journal.
+func (rtm *GoSepp) write(wsClient Conn, messageType int, data []byte,
+	msgs ...[]byte) error {
+	if err := wsClient.WriteMessage(messageType, data); err != nil {
+		rtm.logger.Warn("failed to send.")
+		if rtm.breaker != nil {
+			rtm.breaker.failure()
+		}
+		return err
+	}
+	if rtm.breaker != nil {
+		rtm.breaker.success()
+	}
+	rtm.stats.sent(len(data))
+	rtm.runRawHooks(&rtm.rawSendHooks, messageType, data)
+	if messageType == BinaryMessage {
+		rtm.stats.msg(DirectionOut, binaryFrameType)
+	}
+	for _, msg := range msgs {
+		rtm.record(DirectionOut, msg)
+	}
+	return nil
+}
+
+// isBatch reports whether a received text frame holds a json array of
+// messages.
+func isBatch(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
+
+// splitBatch returns the messages of a batch frame.
+func splitBatch(data []byte) ([]json.RawMessage, error) {
+	var msgs []json.RawMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
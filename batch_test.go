@@ -0,0 +1,69 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestBatching(t *testing.T) {
+	frames := make(chan []byte, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.read()
+		c.write(MsgHello{MsgBase: MsgBase{Type: MsgTypeHello},
+			Data: MsgHelloData{ProtocolVersion: 1,
+				MsgTypes: []string{MsgTypeChat, BatchCapability}}})
+		_, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		frames <- data
+		c.WriteMessage(websocket.TextMessage, []byte(
+			`[{"type":"chat","data":{"content":"a"}},`+
+				`{"type":"chat","data":{"content":"b"}}]`))
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithHandshake(),
+		WithBatching(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !sepp.Supports(BatchCapability) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for capabilities")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, content := range []string{"1", "2", "3"} {
+		if err := sepp.SendMsg(NewChatMsg("conf", content)); err != nil {
+			t.Fatalf("send failed: %s", err)
+		}
+	}
+
+	select {
+	case data := <-frames:
+		msgs, err := splitBatch(data)
+		if err != nil || len(msgs) != 3 {
+			t.Fatalf("expected a batch of 3, got %s", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for batch")
+	}
+
+	for _, want := range []string{"a", "b"} {
+		select {
+		case msg := <-sepp.RcvCh():
+			if m, ok := msg.(*MsgChat); !ok || m.Data.Content != want {
+				t.Fatalf("unexpected message %#v", msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package gosepp
+
+import (
+	"errors"
+)
+
+// binaryBufferSize is the capacity of the binary channel.
+const binaryBufferSize = 16
+
+// BinaryFrame is a bulk payload sent as binary frame, e.g. a thumbnail
+// or compressed stats, which would be wasteful as base64 json.
+//
+// On the wire the frame starts with a one byte length of Type followed
+// by Type, the rest is the payload.
+type BinaryFrame struct {
+	Type    string
+	Payload []byte
+}
+
+var errInvalidBinaryFrame = errors.New("invalid binary frame")
+
+func (f BinaryFrame) encode() ([]byte, error) {
+	if len(f.Type) > 255 {
+		return nil, errInvalidBinaryFrame
+	}
+	b := make([]byte, 0, 1+len(f.Type)+len(f.Payload))
+	b = append(b, byte(len(f.Type)))
+	b = append(b, f.Type...)
+	return append(b, f.Payload...), nil
+}
+
+func decodeBinaryFrame(data []byte) (BinaryFrame, error) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return BinaryFrame{}, errInvalidBinaryFrame
+	}
+	n := 1 + int(data[0])
+	// copy the payload, data is a reused read buffer.
+	payload := make([]byte, len(data)-n)
+	copy(payload, data[n:])
+	return BinaryFrame{Type: string(data[1:n]), Payload: payload}, nil
+}
+
+// BinaryCh returns the channel receiving binary frames. Frames are
+// dropped if the channel is not consumed.
+func (rtm *GoSepp) BinaryCh() chan BinaryFrame {
+	return rtm.binaryCh
+}
+
+// SendBinary sends payload as binary frame of the given type, which
+// must not be longer than 255 bytes.
+func (rtm *GoSepp) SendBinary(typ string, payload []byte) error {
+	b, err := BinaryFrame{Type: typ, Payload: payload}.encode()
+	if err != nil {
+		return err
+	}
+	return rtm.send(BinaryMessage, b, LaneBulk)
+}
+
+func (rtm *GoSepp) receiveBinary(data []byte) {
+	frame, err := decodeBinaryFrame(data)
+	if err != nil {
+		rtm.logger.Warn("Failed to decode binary frame [%s].", err)
+		return
+	}
+	select {
+	case rtm.binaryCh <- frame:
+	default:
+		rtm.logger.Debug("Binary frame not consumed. Dropping.")
+	}
+}
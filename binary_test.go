@@ -0,0 +1,42 @@
+package gosepp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBinaryFrames(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for {
+			mt, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if mt == BinaryMessage {
+				c.WriteMessage(mt, data)
+			}
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	payload := []byte{0, 1, 2, 255}
+	if err := sepp.SendBinary("thumbnail", payload); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case f := <-sepp.BinaryCh():
+		if f.Type != "thumbnail" || !bytes.Equal(f.Payload, payload) {
+			t.Fatalf("unexpected frame %+v", f)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for frame")
+	}
+}
@@ -0,0 +1,164 @@
+package gosepp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+// Circuit breaker states
+const (
+	// BreakerClosed lets all connects and writes pass.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen blocks connects and writes until the cooldown expired.
+	BreakerOpen
+	// BreakerHalfOpen lets a single connect pass as probe.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker opens after a number of consecutive connect or write
+// failures. While open, GoSepp doesn't dial the endpoint and SendMsg
+// fails with ErrCircuitOpen. After the cooldown a single connect or
+// write is let through as half-open probe, which closes the breaker on
+// success and re-opens it on failure.
+type CircuitBreaker struct {
+	threshold     int
+	cooldown      time.Duration
+	onStateChange func(BreakerState)
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// probeAt is when the pending half-open probe was let through, zero
+	// if none is pending.
+	probeAt time.Time
+}
+
+// NewCircuitBreaker returns a breaker opening after threshold
+// consecutive failures for cooldown. onStateChange is optional and
+// called on every state transition, including half-open probes.
+func NewCircuitBreaker(threshold int, cooldown time.Duration,
+	onStateChange func(BreakerState)) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:     threshold,
+		cooldown:      cooldown,
+		onStateChange: onStateChange,
+	}
+}
+
+// WithCircuitBreaker protects the endpoint with b.
+func WithCircuitBreaker(b *CircuitBreaker) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.breaker = b
+	}
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allowConnect reports whether a connect may be attempted.
+func (b *CircuitBreaker) allowConnect() bool {
+	return b.allow()
+}
+
+// allowSend reports whether messages may be sent. Write failures open
+// the breaker while the connection stays up, so sends probe as well.
+func (b *CircuitBreaker) allowSend() bool {
+	return b.allow()
+}
+
+// allow reports whether a connect or write may be attempted. An open
+// breaker whose cooldown expired turns half-open and lets a single probe
+// through, refusing others until the probe succeeded or failed. A probe
+// not resolved within the cooldown, e.g. a message that was never
+// written, is replaced by the next one.
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	switch b.state {
+	case BreakerClosed:
+		b.mu.Unlock()
+		return true
+	case BreakerHalfOpen:
+		if !b.probeAt.IsZero() && time.Since(b.probeAt) < b.cooldown {
+			b.mu.Unlock()
+			return false
+		}
+		b.probeAt = time.Now()
+		b.mu.Unlock()
+		return true
+	}
+	if time.Since(b.openedAt) < b.cooldown {
+		b.mu.Unlock()
+		return false
+	}
+	// turn half-open under the lock, so there's a single probe.
+	b.state = BreakerHalfOpen
+	b.probeAt = time.Now()
+	b.mu.Unlock()
+	b.notify(BreakerHalfOpen)
+	return true
+}
+
+func (b *CircuitBreaker) success() {
+	b.mu.Lock()
+	b.failures = 0
+	b.probeAt = time.Time{}
+	state := b.state
+	b.mu.Unlock()
+	if state != BreakerClosed {
+		b.transition(BreakerClosed)
+	}
+}
+
+func (b *CircuitBreaker) failure() {
+	b.mu.Lock()
+	b.failures++
+	b.probeAt = time.Time{}
+	open := b.state == BreakerHalfOpen ||
+		(b.state == BreakerClosed && b.failures >= b.threshold)
+	b.mu.Unlock()
+	if open {
+		b.transition(BreakerOpen)
+	}
+}
+
+func (b *CircuitBreaker) transition(state BreakerState) {
+	b.mu.Lock()
+	if b.state == state {
+		b.mu.Unlock()
+		return
+	}
+	b.state = state
+	if state == BreakerOpen {
+		b.openedAt = time.Now()
+	}
+	b.mu.Unlock()
+	b.notify(state)
+}
+
+func (b *CircuitBreaker) notify(state BreakerState) {
+	if b.onStateChange != nil {
+		b.onStateChange(state)
+	}
+}
@@ -0,0 +1,96 @@
+package gosepp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	var states []BreakerState
+	b := NewCircuitBreaker(2, 10*time.Millisecond, func(s BreakerState) {
+		states = append(states, s)
+	})
+
+	b.failure()
+	if !b.allowConnect() {
+		t.Fatalf("breaker opened before threshold")
+	}
+	b.failure()
+	if b.allowConnect() || b.allowSend() {
+		t.Fatalf("breaker not open after threshold")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.allowConnect() {
+		t.Fatalf("expected half-open probe after cooldown")
+	}
+	b.failure()
+	if b.State() != BreakerOpen {
+		t.Fatalf("failed probe must re-open, got %s", b.State())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	b.allowConnect()
+	b.success()
+
+	expected := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen,
+		BreakerHalfOpen, BreakerClosed}
+	if !reflect.DeepEqual(states, expected) {
+		t.Fatalf("got %v, want %v", states, expected)
+	}
+}
+
+func TestCircuitBreakerSendProbe(t *testing.T) {
+	b := NewCircuitBreaker(1, 10*time.Millisecond, nil)
+	b.failure()
+	if b.allowSend() {
+		t.Fatalf("breaker not open after threshold")
+	}
+
+	// without reconnects, a write probes after the cooldown.
+	time.Sleep(20 * time.Millisecond)
+	if !b.allowSend() || b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open send probe, got %s", b.State())
+	}
+	b.success()
+	if b.State() != BreakerClosed {
+		t.Fatalf("successful write must close, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	b := NewCircuitBreaker(1, 20*time.Millisecond, nil)
+	b.failure()
+	time.Sleep(30 * time.Millisecond)
+	if !b.allowConnect() {
+		t.Fatalf("expected half-open probe after cooldown")
+	}
+	if b.allowConnect() || b.allowSend() {
+		t.Fatalf("second call let through while the probe is pending")
+	}
+	b.success()
+	if !b.allowSend() {
+		t.Fatalf("successful probe must close")
+	}
+
+	// a failed probe re-opens, the next probe follows the cooldown.
+	b.failure()
+	time.Sleep(30 * time.Millisecond)
+	b.allowSend()
+	b.failure()
+	if b.allowSend() || b.State() != BreakerOpen {
+		t.Fatalf("failed probe must re-open, got %s", b.State())
+	}
+
+	// a probe never resolved is replaced after the cooldown.
+	time.Sleep(30 * time.Millisecond)
+	b.allowSend()
+	if b.allowSend() {
+		t.Fatalf("second call let through while the probe is pending")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !b.allowSend() {
+		t.Fatalf("expected a new probe after an unresolved one")
+	}
+}
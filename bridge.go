@@ -0,0 +1,101 @@
+package gosepp
+
+import "sync"
+
+// BridgeHook is called for every message relayed by a Bridge. It may
+// modify the message, e.g. rewrite its headers, and returns the
+// message to forward or nil to drop it.
+type BridgeHook func(msg MsgInterface) MsgInterface
+
+// Bridge relays messages between two signaling connections. It is the
+// building block for protocol-level proxies and debuggers.
+//
+// The Bridge consumes the receive channels of both GoSepps.
+type Bridge struct {
+	a        *GoSepp
+	b        *GoSepp
+	hookAtoB BridgeHook
+	hookBtoA BridgeHook
+	logger   Logger
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// BridgeOption defines the options interface of a Bridge.
+type BridgeOption func(*Bridge)
+
+// WithHookAtoB sets the hook applied to messages relayed from a to b.
+func WithHookAtoB(hook BridgeHook) BridgeOption {
+	return func(br *Bridge) {
+		br.hookAtoB = hook
+	}
+}
+
+// WithHookBtoA sets the hook applied to messages relayed from b to a.
+func WithHookBtoA(hook BridgeHook) BridgeOption {
+	return func(br *Bridge) {
+		br.hookBtoA = hook
+	}
+}
+
+// NewBridge starts relaying messages between a and b. If one side's
+// receive channel is closed, e.g. because an accepted client
+// disconnected, both sides are stopped.
+func NewBridge(a, b *GoSepp, logger Logger, options ...BridgeOption) *Bridge {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	br := &Bridge{a: a, b: b, logger: logger}
+	for _, opt := range options {
+		opt(br)
+	}
+
+	br.wg.Add(2)
+	go br.relay(a, b, br.hookAtoB)
+	go br.relay(b, a, br.hookBtoA)
+	return br
+}
+
+func (br *Bridge) relay(src, dst *GoSepp, hook BridgeHook) {
+	defer br.wg.Done()
+	for msg := range src.RcvCh() {
+		if hook != nil {
+			if msg = hook(msg); msg == nil {
+				continue
+			}
+		}
+		if err := dst.SendMsg(msg); err != nil {
+			br.logger.Warn("Failed to relay %s [%s].", msg.GetType(), err)
+		}
+	}
+	// Stop in a separate goroutine, as it waits for this relay.
+	go br.Stop()
+}
+
+// Wait blocks until the bridge is stopped.
+func (br *Bridge) Wait() {
+	br.wg.Wait()
+}
+
+// Stop both sides of the bridge.
+func (br *Bridge) Stop() {
+	br.stopOnce.Do(func() {
+		br.a.Stop()
+		br.b.Stop()
+		br.wg.Wait()
+	})
+}
+
+// RewriteAddress returns a hook replacing the address from with to in
+// the From and To headers of relayed messages.
+func RewriteAddress(from, to string) BridgeHook {
+	return func(msg MsgInterface) MsgInterface {
+		if msg.GetFrom() == from {
+			msg.SetFrom(to)
+		}
+		if msg.GetTo() == from {
+			msg.SetTo(to)
+		}
+		return msg
+	}
+}
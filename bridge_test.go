@@ -0,0 +1,50 @@
+package gosepp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBridgeRelaysCall(t *testing.T) {
+	upstream := newFakeServer(t, acceptCalls)
+	defer upstream.Close()
+
+	listener := NewListener(nil)
+	proxy := httptest.NewServer(listener)
+	defer proxy.Close()
+
+	go func() {
+		for downstream := range listener.AcceptCh() {
+			up, err := NewGoSepp(upstream.URL(), "", nil, nil)
+			if err != nil {
+				t.Errorf("failed: %s", err)
+				return
+			}
+			<-up.ConnectStatusCh()
+			NewBridge(downstream, up, nil,
+				WithHookAtoB(RewriteAddress("alias", "conf")),
+				WithHookBtoA(RewriteAddress("conf", "alias")))
+		}
+	}()
+
+	call, err := NewCall(&CallInfo{
+		SigEndpoint: "ws" + strings.TrimPrefix(proxy.URL, "http"),
+		ClientID:    "client",
+		ConfID:      "alias"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+}
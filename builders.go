@@ -0,0 +1,88 @@
+package gosepp
+
+// Builders return fully populated messages. Messages of an established
+// call carry no from and to headers, set them with SetFrom and SetTo, or
+// send them with Call.SendMsg and WithMsgStamping.
+
+// NewCallStart returns a call_start offering sdp.
+func NewCallStart(from ClientID, to ConfID, sdp Sdp, displayName string) *MsgCallStart {
+	return &MsgCallStart{
+		MsgBase: MsgBase{Type: MsgTypeCallStart, From: string(from), To: string(to)},
+		Data:    MsgCallStartData{Sdp: sdp, DisplayName: displayName},
+	}
+}
+
+// NewSdpUpdate returns an sdp_update of the call callID.
+func NewSdpUpdate(callID CallID, sdp Sdp) *MsgSdpUpdate {
+	return &MsgSdpUpdate{
+		MsgBase: MsgBase{Type: MsgTypeSdpUpdate},
+		Data:    MsgSdpUpdateData{CallID: string(callID), Sdp: sdp},
+	}
+}
+
+// NewCallTerminate returns a call_terminate of the call callID.
+func NewCallTerminate(callID CallID) *MsgCallTerminate {
+	return &MsgCallTerminate{
+		MsgBase: MsgBase{Type: MsgTypeCallTerminate},
+		Data:    MsgCallTerminateData{CallID: string(callID)},
+	}
+}
+
+// NewCallResume returns a call_resume of the call callID.
+func NewCallResume(callID CallID, sdp Sdp) *MsgCallResume {
+	return &MsgCallResume{
+		MsgBase: MsgBase{Type: MsgTypeCallResume},
+		Data:    MsgCallResumeData{CallID: string(callID), Sdp: sdp},
+	}
+}
+
+// NewChatMsg returns a chat message to the conference confID.
+func NewChatMsg(confID ConfID, content string) *MsgChat {
+	return &MsgChat{
+		MsgBase: MsgBase{Type: MsgTypeChat, To: string(confID)},
+		Data:    MsgChatData{Content: content},
+	}
+}
+
+// NewMuteVideo returns a mute_video of the call callID.
+func NewMuteVideo(callID CallID, on bool) *MsgMuteVideo {
+	return &MsgMuteVideo{
+		MsgBase: MsgBase{Type: MsgTypeMuteVideo},
+		Data:    MsgMuteVideoData{CallID: string(callID), On: on},
+	}
+}
+
+// NewSetPresenter returns a set_presenter for the client clientID.
+func NewSetPresenter(callID CallID, clientID ClientID, on bool) *MsgSetPresenter {
+	return &MsgSetPresenter{
+		MsgBase: MsgBase{Type: MsgTypeSetPresenter},
+		Data: MsgSetPresenterData{CallID: string(callID), On: on,
+			ClientID: string(clientID)},
+	}
+}
+
+// NewDesktopstreaming returns a desktopstreaming for the client clientID.
+func NewDesktopstreaming(callID CallID, clientID ClientID, on bool) *MsgDesktopstreaming {
+	return &MsgDesktopstreaming{
+		MsgBase: MsgBase{Type: MsgTypeDesktopstreaming},
+		Data: MsgDesktopstreamingData{CallID: string(callID), On: on,
+			ClientID: string(clientID)},
+	}
+}
+
+// NewCallHold returns a call_hold of the call callID.
+func NewCallHold(callID CallID, on bool) *MsgCallHold {
+	return &MsgCallHold{
+		MsgBase: MsgBase{Type: MsgTypeCallHold},
+		Data:    MsgCallHoldData{CallID: string(callID), On: on},
+	}
+}
+
+// NewCallTransfer returns a call_transfer of the call callID to the
+// conference target.
+func NewCallTransfer(callID CallID, target ConfID) *MsgCallTransfer {
+	return &MsgCallTransfer{
+		MsgBase: MsgBase{Type: MsgTypeCallTransfer},
+		Data:    MsgCallTransferData{CallID: string(callID), Target: string(target)},
+	}
+}
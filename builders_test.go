@@ -0,0 +1,25 @@
+package gosepp
+
+import (
+	"testing"
+)
+
+func TestBuildersAreValid(t *testing.T) {
+	sdp := Sdp{SdpType: "offer", Sdp: "v=0"}
+	for _, msg := range []MsgInterface{
+		NewCallStart("client", "conf", sdp, "bot"),
+		NewSdpUpdate("call", sdp),
+		NewCallTerminate("call"),
+		NewCallResume("call", sdp),
+		NewChatMsg("conf", "hi"),
+		NewMuteVideo("call", true),
+		NewSetPresenter("call", "client", true),
+		NewDesktopstreaming("call", "client", true),
+		NewCallHold("call", true),
+		NewCallTransfer("call", "other"),
+	} {
+		if err := ValidateMsg(msg); err != nil {
+			t.Fatalf("invalid %s: %s", msg.GetType(), err)
+		}
+	}
+}
@@ -2,32 +2,67 @@ package gosepp
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"io/ioutil"
+	"sync"
+	"time"
 )
 
 // CallID custom callID type
 type CallID string
 
 // Call is an abstraction of the gosepp messaging based interface.
+// It holds the configuration and the signaling connection, and can place
+// sequential calls, each represented by a CallSession.
 type Call struct {
-	sepp                *GoSepp
-	confID              string
-	clientID            string
-	callID              CallID
-	terminationHandler  func()
-	sdpUpdateHandler    func(Sdp)
-	memberlistHandler   func(MsgMemberlistData)
-	sourceUpdateHandler func(MsgSourceUpdateData)
-	cancel              context.CancelFunc
-	termCh              chan bool
-	logger              Logger
-	customCAFile        string
-	platform            string
+	// sepp and inbox are guarded by connMu, as they're replaced by
+	// migrations.
+	sepp   *GoSepp
+	inbox  <-chan MsgInterface
+	connMu sync.Mutex
+	// pooled is set for calls sharing the connection of a ConnPool.
+	pooled                   *pooledCall
+	confID                   ConfID
+	clientID                 ClientID
+	terminationHandler       func()
+	sdpUpdateHandler         func(Sdp)
+	memberlistHandler        func(MsgMemberlistData)
+	sourceUpdateHandler      func(MsgSourceUpdateData)
+	transferHandler          func(string)
+	holdHandler              func(bool)
+	errorHandler             func(error)
+	chatReceiptHandler       func(MsgChatReceiptData)
+	typingHandler            func(string, bool)
+	sourceDiffHandler        func(SourceUpdateDiff)
+	rosterHandlers           rosterHandlers
+	stamping                 bool
+	typingTimeout            time.Duration
+	idleTimeout              time.Duration
+	idleCondition            IdleCondition
+	maxDuration              time.Duration
+	watchdogTimeout          time.Duration
+	watchdogTeardown         bool
+	terminationReasonHandler func(TerminationReason)
+	session                  *CallSession
+	connected                bool
+	logger                   Logger
+	customCAFile             string
+	platform                 string
+	platformInfo             PlatformInfo
+	displayName              string
+	avatarURL                string
+	metadata                 map[string]string
+	seppOptions              []GoSeppOption
+	// dialSepp connects to another endpoint with the settings of the
+	// call, for migrations.
+	dialSepp      func(endpoint string) (*GoSepp, error)
+	autoResume    bool
+	tolerantStart bool
 }
 
+// earlyQueueSize limits the messages queued during call setup, which
+// are dispatched once the call is accepted.
+const earlyQueueSize = 64
+
 // CallOption defines the options interface
 type CallOption func(*Call)
 
@@ -47,47 +82,143 @@ func WithPlatformVersion(platform string) CallOption {
 	}
 }
 
-// NewCall initializes an instance of a call.
-func NewCall(callInfo CallInfoInterface, logger Logger, options ...CallOption) (*Call, error) {
+// WithDisplayName sets the name shown to the other participants, used
+// unless StartSession is passed a display name.
+func WithDisplayName(name string) CallOption {
+	return func(c *Call) {
+		c.displayName = name
+	}
+}
 
-	if logger == nil {
-		logger = &silentLogger{}
+// WithAvatarURL sets the url of the avatar shown to the other
+// participants.
+func WithAvatarURL(url string) CallOption {
+	return func(c *Call) {
+		c.avatarURL = url
 	}
+}
 
-	call := &Call{
-		confID:   callInfo.GetConfID(),
-		clientID: callInfo.GetClientID(),
-		termCh:   make(chan bool),
-		logger:   logger,
+// WithCallMetadata attaches metadata to call_start, e.g. correlation ids
+// or device information surfaced by the backend and webhooks.
+func WithCallMetadata(metadata map[string]string) CallOption {
+	return func(c *Call) {
+		c.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			c.metadata[k] = v
+		}
 	}
+}
 
-	for _, opt := range options {
-		opt(call)
+// WithAutoResume enables or disables replaying call_resume and the
+// video mute state after the signaling connection was re-established.
+// Enabled by default.
+func WithAutoResume(enabled bool) CallOption {
+	return func(c *Call) {
+		c.autoResume = enabled
 	}
+}
 
-	var tlsConfig *tls.Config
-	if len(call.customCAFile) > 0 {
-		// Load CA cert
-		caCert, err := ioutil.ReadFile(call.customCAFile)
-		if err != nil {
-			return nil, err
-		}
-		caCertPool := x509.NewCertPool()
-		if !caCertPool.AppendCertsFromPEM(caCert) {
-			return nil, fmt.Errorf("Failed to append CAcert")
+// WithTypingTimeout sets the inactivity after which Typing sends
+// typing-stop. Defaults to 3 seconds.
+func WithTypingTimeout(timeout time.Duration) CallOption {
+	return func(c *Call) {
+		c.typingTimeout = timeout
+	}
+}
+
+// WithMaxCallDuration terminates calls after d. The termination reason
+// is TerminatedMaxDuration.
+func WithMaxCallDuration(d time.Duration) CallOption {
+	return func(c *Call) {
+		c.maxDuration = d
+	}
+}
+
+// WithTolerantStart makes StartSession queue messages unrelated to the
+// call setup, e.g. chat or source updates, instead of failing with a
+// ProtocolError. They are dispatched once the call is accepted.
+func WithTolerantStart() CallOption {
+	return func(c *Call) {
+		c.tolerantStart = true
+	}
+}
+
+// WithSeppOptions passes options to the underlying GoSepp.
+func WithSeppOptions(options ...GoSeppOption) CallOption {
+	return func(c *Call) {
+		c.seppOptions = append(c.seppOptions, options...)
+	}
+}
+
+// NewCall initializes an instance of a call.
+func NewCall(callInfo CallInfoInterface, logger Logger, options ...CallOption) (*Call, error) {
+	call, err := newCall(ConfID(callInfo.GetConfID()),
+		ClientID(callInfo.GetClientID()), logger, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if i, ok := callInfo.(CallInfoIdentity); ok {
+		if len(call.displayName) == 0 {
+			call.displayName = i.GetDisplayName()
 		}
-		tlsConfig = &tls.Config{
-			RootCAs: caCertPool,
+		if len(call.avatarURL) == 0 {
+			call.avatarURL = i.GetAvatarURL()
 		}
 	}
 
+	tlsConfig, seppOptions, fallbacks, err := connConfig(callInfo, call.customCAFile)
+	if err != nil {
+		return nil, err
+	}
+	// migrations target a single endpoint, without fallbacks.
+	migrateOptions := append(append([]GoSeppOption{}, seppOptions...),
+		call.seppOptions...)
+	call.dialSepp = func(endpoint string) (*GoSepp, error) {
+		current, _ := call.conn()
+		return NewGoSepp(endpoint, current.token(), tlsConfig, call.logger,
+			migrateOptions...)
+	}
+	if len(fallbacks) > 0 {
+		seppOptions = append(seppOptions, WithEndpoints(fallbacks...))
+	}
 	sepp, err := NewGoSepp(callInfo.GetSigEndpoint(), callInfo.GetAuthToken(),
-		tlsConfig, logger)
+		tlsConfig, call.logger, append(seppOptions, call.seppOptions...)...)
 	if err != nil {
 		return nil, err
 	}
 
 	call.sepp = sepp
+	call.inbox = sepp.RcvCh()
+	return call, nil
+}
+
+// newCall returns a call without signaling connection.
+func newCall(confID ConfID, clientID ClientID, logger Logger,
+	options ...CallOption) (*Call, error) {
+	logger = WithLogField(WithLogField(logger, "conf_id", string(confID)),
+		"client_id", string(clientID))
+
+	call := &Call{
+		confID:     confID,
+		clientID:   clientID,
+		logger:     logger,
+		autoResume: true,
+
+		platformInfo: DefaultPlatformInfo(),
+
+		typingTimeout: defaultTypingTimeout,
+	}
+
+	for _, opt := range options {
+		opt(call)
+	}
+	if err := call.clientID.Validate(); err != nil {
+		return nil, fmt.Errorf("client-id: %w", err)
+	}
+	if err := call.confID.Validate(); err != nil {
+		return nil, fmt.Errorf("conf-id: %w", err)
+	}
 	return call, nil
 }
 
@@ -98,9 +229,16 @@ func (c *Call) SetTerminatedHandler(handler func()) {
 	c.terminationHandler = handler
 }
 
+// SetTerminationReasonHandler sets a handler which is called along
+// with the termination handler, telling why the call ended.
+func (c *Call) SetTerminationReasonHandler(handler func(TerminationReason)) {
+	c.terminationReasonHandler = handler
+}
+
 // SetSDPUpdateHandler sets the sdp-update handler which is
 // called if the remote end is sending an updated
-// sdp.
+// sdp. Provisional sdps sent during call setup, e.g. for early
+// media, are delivered as well.
 // Must be set-up before start.
 func (c *Call) SetSDPUpdateHandler(handler func(Sdp)) {
 	c.sdpUpdateHandler = handler
@@ -112,201 +250,434 @@ func (c *Call) SetMemberlistHandler(handler func(MsgMemberlistData)) {
 	c.memberlistHandler = handler
 }
 
+// SetMemberJoinedHandler set handler to be called for every member
+// joining the conference.
+func (c *Call) SetMemberJoinedHandler(handler func(Member)) {
+	c.rosterHandlers.memberJoined = handler
+}
+
+// SetMemberLeftHandler set handler to be called for every member
+// leaving the conference.
+func (c *Call) SetMemberLeftHandler(handler func(clientID string)) {
+	c.rosterHandlers.memberLeft = handler
+}
+
+// SetMediaAddedHandler set handler to be called for every media
+// added to the conference.
+func (c *Call) SetMediaAddedHandler(handler func(Media)) {
+	c.rosterHandlers.mediaAdded = handler
+}
+
+// SetMediaRemovedHandler set handler to be called for every media
+// removed from the conference.
+func (c *Call) SetMediaRemovedHandler(handler func(Media)) {
+	c.rosterHandlers.mediaRemoved = handler
+}
+
 // SetSourceUpdateHandler set handler to be called if the podium
 // layout changes.
 func (c *Call) SetSourceUpdateHandler(handler func(MsgSourceUpdateData)) {
 	c.sourceUpdateHandler = handler
 }
 
-func startDispatch(ctx context.Context, logger Logger, sepp *GoSepp,
-	termHandler func(), sdpUpdateHandler func(Sdp),
-	memberlistHandler func(MsgMemberlistData),
-	sourceUpdateHandler func(MsgSourceUpdateData), termCh chan<- bool) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case msg, ok := <-sepp.RcvCh():
-			if !ok {
-				logger.Info("Channel closed. Stopping dispatch")
-				return
-			}
-			// dispatch messages
-			switch m := msg.(type) {
-			case *MsgCallTerminated:
-				// try to signal on the term channel
-				select {
-				case termCh <- true:
-				default:
-					//log.Println("Timout when calling term channel")
-				}
-				if termHandler != nil {
-					termHandler()
-				}
-			case *MsgSdpUpdate:
-				if sdpUpdateHandler != nil {
-					sdpUpdateHandler(m.Data.Sdp)
-				}
-			case *MsgMemberlist:
-				if memberlistHandler != nil {
-					memberlistHandler(m.Data)
-				}
-			case *MsgSourceUpdate:
-				if sourceUpdateHandler != nil {
-					sourceUpdateHandler(m.Data)
-				}
-			default:
-			}
+// SetSourceUpdateDiffHandler set handler to be called with the changes
+// of the podium layout, instead of the full source update. The first
+// update reports all sources as added. Updates without changes are
+// skipped.
+func (c *Call) SetSourceUpdateDiffHandler(handler func(SourceUpdateDiff)) {
+	c.sourceDiffHandler = handler
+}
+
+// SetTransferHandler set handler to be called if the remote end
+// transfers the call to another conference.
+func (c *Call) SetTransferHandler(handler func(target string)) {
+	c.transferHandler = handler
+}
+
+// SetHoldHandler set handler to be called if the remote end puts
+// the call on hold or resumes it.
+func (c *Call) SetHoldHandler(handler func(onHold bool)) {
+	c.holdHandler = handler
+}
+
+// SetErrorHandler set handler to be called if the server reports an
+// error during the call. The error is a *ServerError.
+func (c *Call) SetErrorHandler(handler func(error)) {
+	c.errorHandler = handler
+}
+
+// SetChatReceiptHandler set handler to be called if a delivery or read
+// receipt of a chat message is received.
+func (c *Call) SetChatReceiptHandler(handler func(MsgChatReceiptData)) {
+	c.chatReceiptHandler = handler
+}
+
+// SetTypingHandler set handler to be called if a client starts or
+// stops typing.
+func (c *Call) SetTypingHandler(handler func(clientID string, on bool)) {
+	c.typingHandler = handler
+}
+
+// ClientID returns the id of the calling client.
+func (c *Call) ClientID() ClientID {
+	return c.clientID
+}
+
+// ConfID returns the id of the called conference.
+func (c *Call) ConfID() ConfID {
+	return c.confID
+}
+
+// Session returns the current call session, or nil if no call
+// was started yet.
+func (c *Call) Session() *CallSession {
+	return c.session
+}
+
+// activeSession returns the current session if it is still active.
+func (c *Call) activeSession() (*CallSession, error) {
+	if c.session == nil || !c.session.active() {
+		return nil, ErrNoActiveCall
+	}
+	return c.session, nil
+}
+
+// waitConnected blocks until the signaling connection is up.
+func (c *Call) waitConnected(ctx context.Context) error {
+	if c.connected {
+		return nil
+	}
+	if c.pooled != nil {
+		if err := c.pooled.conn.waitConnected(ctx); err != nil {
+			return err
 		}
+		c.connected = true
+		return nil
 	}
+	sepp, _ := c.conn()
+	select {
+	case connected, ok := <-sepp.ConnectStatusCh():
+		if !ok || !connected {
+			return ErrConnectFailed
+		}
+	case <-ctx.Done():
+		return ctxError(ctx, "wait for connect")
+	}
+	c.connected = true
+	return nil
 }
 
 // Start the call. On success the call-id and sdp is returned,
 // else an error.
 func (c *Call) Start(ctx context.Context, sdp Sdp, displayname string) (*CallID, *Sdp, error) {
-	if len(c.callID) > 0 {
-		return nil, nil, fmt.Errorf("call already in progress")
+	session, err := c.StartSession(ctx, sdp, displayname)
+	if err != nil {
+		return nil, nil, err
 	}
+	callID := session.ID()
+	remoteSdp := session.RemoteSdp()
+	return &callID, &remoteSdp, nil
+}
 
-	callCtx, cancel := context.WithCancel(ctx)
-	c.cancel = cancel
+// StartSession starts a new call and returns the established session.
+// A Call can place a new call as soon as the previous session is
+// terminated. An empty displayname uses the one set by WithDisplayName.
+func (c *Call) StartSession(ctx context.Context, sdp Sdp, displayname string) (*CallSession, error) {
+	if c.session != nil && c.session.active() {
+		return nil, ErrCallInProgress
+	}
+	if len(displayname) == 0 {
+		displayname = c.displayName
+	}
 
-	// wait for connected
-	select {
-	case connected, ok := <-c.sepp.ConnectStatusCh():
-		if !ok || !connected {
-			return nil, nil, fmt.Errorf("Failed to connect")
-		}
-	case <-callCtx.Done():
-		return nil, nil, fmt.Errorf("Timeout. Failed to connect")
+	if err := c.waitConnected(ctx); err != nil {
+		return nil, err
 	}
 
 	// send start call message
-	if err := c.sepp.SendMsg(MsgCallStart{
+	platformInfo := c.platformInfo
+	sepp, inbox := c.conn()
+	if err := sepp.SendMsg(MsgCallStart{
 		MsgBase: MsgBase{
 			Type: MsgTypeCallStart,
-			From: c.clientID,
-			To:   c.confID,
+			From: string(c.clientID),
+			To:   string(c.confID),
 		},
 		Data: MsgCallStartData{
-			Sdp:         sdp,
-			DisplayName: displayname,
-			Platform:    c.platform,
+			Sdp:          sdp,
+			DisplayName:  displayname,
+			Platform:     c.platform,
+			AvatarURL:    c.avatarURL,
+			Metadata:     c.metadata,
+			PlatformInfo: &platformInfo,
 		},
 	}); err != nil {
-		return nil, nil, fmt.Errorf("failed to send message: %s", err)
+		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
+	var early []MsgInterface
 	for {
 		// wait for call accepted or rejected
 		select {
-		case msg, ok := <-c.sepp.RcvCh():
+		case msg, ok := <-inbox:
 			if !ok {
-				return nil, nil, fmt.Errorf("Failed to receive")
+				return nil, ErrConnectionClosed
 			}
 			// dispatch messages
 			switch m := msg.(type) {
 			case *MsgMemberlist:
-				// Continue if a memberlist was received.
-				continue
+				// replayed once accepted, so handlers get the
+				// initial roster.
+				early = c.queueEarly(early, m)
+			case *MsgSdpUpdate:
+				// provisional sdp for early media, superseded by
+				// the sdp of call_accepted.
+				if c.sdpUpdateHandler != nil {
+					c.sdpUpdateHandler(m.Data.Sdp)
+				}
 			case *MsgCallAccepted:
-				callID := CallID(m.Data.CallID)
-				c.callID = callID
-				// start dispatcher as goroutine
-				go startDispatch(callCtx, c.logger, c.sepp, c.terminationHandler,
-					c.sdpUpdateHandler, c.memberlistHandler, c.sourceUpdateHandler,
-					c.termCh)
-
-				return &callID, &m.Data.Sdp, nil
+				session := newCallSession(sepp, inbox,
+					string(c.clientID), string(c.confID),
+					CallID(m.Data.CallID), sdp, m.Data.Sdp, callHandlers{
+						termination:       c.terminationHandler,
+						terminationReason: c.terminationReasonHandler,
+						sdpUpdate:         c.sdpUpdateHandler,
+						memberlist:        c.memberlistHandler,
+						sourceUpdate:      c.sourceUpdateHandler,
+						transfer:          c.transferHandler,
+						hold:              c.holdHandler,
+						err:               c.errorHandler,
+						chatReceipt:       c.chatReceiptHandler,
+						typing:            c.typingHandler,
+						sourceDiff:        c.sourceDiffHandler,
+						roster:            c.rosterHandlers,
+					}, WithLogField(c.logger, "call_id", m.Data.CallID))
+				session.autoResume = c.autoResume
+				session.typingTimeout = c.typingTimeout
+				session.idleTimeout = c.idleTimeout
+				session.idleCondition = c.idleCondition
+				session.maxDuration = c.maxDuration
+				if c.watchdogTimeout > 0 {
+					session.watchdog = newWatchdog(c.watchdogTimeout,
+						c.watchdogTeardown)
+				}
+				session.setEarly(early)
+				// The session outlives the start-context, which
+				// only limits the call setup.
+				session.start(context.Background())
+				c.session = session
+				return session, nil
 			case *MsgCallRejected:
-				return nil, nil, fmt.Errorf("Call rejected: %d", m.Data.RejectCode)
+				return nil, &CallRejectedError{RejectCode: m.Data.RejectCode}
+			case *MsgCallRedirect:
+				return nil, &CallRedirectedError{Target: m.Data.Target}
+			case *MsgError:
+				return nil, serverError(m)
 			default:
-				return nil, nil, fmt.Errorf("Protocol error. Msg-type: %s", m.GetType())
+				if !c.tolerantStart {
+					return nil, &ProtocolError{MsgType: m.GetType()}
+				}
+				early = c.queueEarly(early, m)
 			}
-		case <-callCtx.Done():
-			return nil, nil, fmt.Errorf("Timeout")
+		case <-ctx.Done():
+			return nil, ctxError(ctx, "wait for accept")
 		}
 	}
+}
 
+// queueEarly adds msg, received during call setup, to early unless
+// the queue is full.
+func (c *Call) queueEarly(early []MsgInterface, msg MsgInterface) []MsgInterface {
+	if len(early) == earlyQueueSize {
+		c.logger.Warn("Dropping %s message received during call setup.",
+			msg.GetType())
+		return early
+	}
+	return append(early, msg)
 }
 
-// Terminate the active call.
-func (c *Call) Terminate(ctx context.Context) error {
-	if len(c.callID) == 0 {
-		return fmt.Errorf("no active call")
+// Migrate moves the active call to the signaling server at endpoint,
+// e.g. ahead of server maintenance. The call is resumed on a new
+// connection, which takes over dispatching before the previous
+// connection is closed. On failure the call stays on the previous
+// connection.
+func (c *Call) Migrate(ctx context.Context, endpoint string) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
 	}
-	// send start call message
-	if err := c.sepp.SendMsg(MsgCallTerminate{
-		MsgBase: MsgBase{
-			Type: MsgTypeCallTerminate,
-			From: c.clientID,
-			To:   c.confID,
-		},
-		Data: MsgCallTerminateData{
-			CallID: string(c.callID)},
-	}); err != nil {
-		return fmt.Errorf("failed to send message: %s", err)
+	if c.pooled != nil {
+		return ErrNotMigratable
+	}
+	next, err := c.dialSepp(endpoint)
+	if err != nil {
+		return err
+	}
+	if err := c.resumeOn(ctx, next, session); err != nil {
+		next.Stop()
+		return err
 	}
+	c.connMu.Lock()
+	previous := c.sepp
+	c.sepp = next
+	c.inbox = next.RcvCh()
+	c.connMu.Unlock()
+	previous.Stop()
 
-	// wait for terminated
+	if session.Snapshot().VideoOff {
+		if err := session.TurnOffVideo(ctx, true); err != nil {
+			c.logger.Warn("Failed to restore video mute [%s].", err)
+		}
+	}
+	return nil
+}
+
+// conn returns the signaling connection and the inbox of the call.
+func (c *Call) conn() (*GoSepp, <-chan MsgInterface) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.sepp, c.inbox
+}
+
+// resumeOn resumes session on the connection next and switches the
+// session over.
+func (c *Call) resumeOn(ctx context.Context, next *GoSepp, session *CallSession) error {
 	select {
+	case connected, ok := <-next.ConnectStatusCh():
+		if !ok || !connected {
+			return ErrConnectFailed
+		}
 	case <-ctx.Done():
-		return fmt.Errorf("timeout")
-	case <-c.termCh:
+		return ctxError(ctx, "wait for connect")
+	}
+	// the reply is dispatched by the session once switched over, which
+	// updates the remote sdp.
+	if _, err := next.SendAndWait(ctx, MsgCallResume{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallResume,
+			From: string(c.clientID),
+			To:   string(c.confID),
+		},
+		Data: MsgCallResumeData{
+			CallID: string(session.ID()),
+			Sdp:    session.Snapshot().LocalSdp},
+	}, MsgTypeCallResumed); err != nil {
+		return fmt.Errorf("failed to resume call: %w", err)
 	}
+	return session.migrate(ctx, next, next.RcvCh())
+}
 
-	return nil
+// Terminate the active call.
+func (c *Call) Terminate(ctx context.Context) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.Terminate(ctx)
 }
 
 // UpdateSDP sends and sdp update to the remote end.
 func (c *Call) UpdateSDP(ctx context.Context, sdp Sdp) error {
-	if len(c.callID) == 0 {
-		return fmt.Errorf("no active call")
-	}
-	// send start call message
-	if err := c.sepp.SendMsg(MsgSdpUpdate{
-		MsgBase: MsgBase{
-			Type: MsgTypeSdpUpdate,
-			From: c.clientID,
-			To:   c.confID,
-		},
-		Data: MsgSdpUpdateData{
-			CallID: string(c.callID),
-			Sdp:    sdp},
-	}); err != nil {
-		return fmt.Errorf("failed to send message: %s", err)
+	session, err := c.activeSession()
+	if err != nil {
+		return err
 	}
-	return nil
+	return session.UpdateSDP(ctx, sdp)
 }
 
 // TurnOffVideo mutes or unmute video
 func (c *Call) TurnOffVideo(ctx context.Context, off bool) error {
-	if len(c.callID) == 0 {
-		return fmt.Errorf("no active call")
+	session, err := c.activeSession()
+	if err != nil {
+		return err
 	}
-	if err := c.sepp.SendMsg(MsgMuteVideo{
-		MsgBase: MsgBase{
-			Type: MsgTypeMuteVideo,
-			From: c.clientID,
-			To:   c.confID,
-		},
-		Data: MsgMuteVideoData{
-			CallID: string(c.callID),
-			On:     off},
-	}); err != nil {
-		return fmt.Errorf("failed to send message: %s", err)
+	return session.TurnOffVideo(ctx, off)
+}
+
+// UpdateDisplayName changes the identity of the active call, see
+// CallSession.UpdateDisplayName.
+func (c *Call) UpdateDisplayName(ctx context.Context, name, avatarURL string) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
 	}
-	return nil
+	return session.UpdateDisplayName(ctx, name, avatarURL)
+}
+
+// Hold puts the active call on hold.
+func (c *Call) Hold(ctx context.Context) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.Hold(ctx)
+}
+
+// Unhold resumes the active call.
+func (c *Call) Unhold(ctx context.Context) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.Unhold(ctx)
+}
+
+// Transfer the active call to the conference target.
+func (c *Call) Transfer(ctx context.Context, target string) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.Transfer(ctx, target)
+}
+
+// MarkChatDelivered sends a delivery receipt for the chat message chatID.
+func (c *Call) MarkChatDelivered(ctx context.Context, chatID string) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.MarkChatDelivered(ctx, chatID)
+}
+
+// MarkChatRead sends a read receipt for the chat message chatID.
+func (c *Call) MarkChatRead(ctx context.Context, chatID string) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.MarkChatRead(ctx, chatID)
+}
+
+// Typing signals that the user is typing, see CallSession.Typing.
+func (c *Call) Typing(ctx context.Context) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.Typing(ctx)
+}
+
+// StopTyping sends typing-stop.
+func (c *Call) StopTyping(ctx context.Context) error {
+	session, err := c.activeSession()
+	if err != nil {
+		return err
+	}
+	return session.StopTyping(ctx)
 }
 
 // Close this call.
 // Shuts down connection to the signaling service,
 // but does _not_ terminate the call.
 func (c *Call) Close() {
-	if c.cancel != nil {
-		c.cancel()
+	if c.session != nil {
+		c.session.close()
 	}
-	if c.sepp != nil {
-		c.sepp.Stop()
+	if c.pooled != nil {
+		// the connection is shared with other calls of the pool.
+		c.pooled.release()
+	} else if sepp, _ := c.conn(); sepp != nil {
+		sepp.Stop()
 	}
 }
@@ -0,0 +1,957 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallSequentialSessions(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); !errors.Is(err, ErrCallInProgress) {
+		t.Fatalf("expected ErrCallInProgress, got %v", err)
+	}
+	if err := first.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	if first.State() != CallStateTerminated {
+		t.Fatalf("unexpected state %s", first.State())
+	}
+
+	second, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	if err != nil {
+		t.Fatalf("second start failed: %s", err)
+	}
+	if second.ID() == first.ID() {
+		t.Fatalf("expected a new call-id")
+	}
+	if err := call.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	if err := call.Terminate(ctx); !errors.Is(err, ErrNoActiveCall) {
+		t.Fatalf("expected ErrNoActiveCall, got %v", err)
+	}
+}
+
+func TestCallResumesAfterReconnect(t *testing.T) {
+	var conns int32
+	resumed := make(chan MsgInterface, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		if atomic.AddInt32(&conns, 1) == 1 {
+			start := c.read().(*MsgCallStart)
+			c.write(MsgCallAccepted{
+				MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+				Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer", Sdp: "a1"}},
+			})
+			c.read() // mute_video
+			return   // drop the connection
+		}
+		resume := c.read()
+		resumed <- resume
+		c.write(MsgCallResumed{
+			MsgBase: MsgBase{Type: MsgTypeCallResumed},
+			Data:    MsgCallResumedData{CallID: "call", Sdp: Sdp{SdpType: "answer", Sdp: "a2"}},
+		})
+		resumed <- c.read()
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	sdpUpdates := make(chan Sdp, 1)
+	call.SetSDPUpdateHandler(func(sdp Sdp) { sdpUpdates <- sdp })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "o1"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.TurnOffVideo(ctx, true); err != nil {
+		t.Fatalf("mute failed: %s", err)
+	}
+
+	for _, typ := range []string{MsgTypeCallResume, MsgTypeMuteVideo} {
+		select {
+		case msg := <-resumed:
+			if msg.GetType() != typ {
+				t.Fatalf("expected %s, got %s", typ, msg.GetType())
+			}
+			if m, ok := msg.(*MsgCallResume); ok && m.Data.Sdp.Sdp != "o1" {
+				t.Fatalf("resumed with wrong sdp %q", m.Data.Sdp.Sdp)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for %s", typ)
+		}
+	}
+	select {
+	case sdp := <-sdpUpdates:
+		if sdp.Sdp != "a2" || session.RemoteSdp().Sdp != "a2" {
+			t.Fatalf("remote sdp not updated")
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for sdp update")
+	}
+}
+
+func TestCallTypingDebounce(t *testing.T) {
+	typing := make(chan bool, 4)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		for {
+			m, ok := c.read().(*MsgTyping)
+			if !ok {
+				return
+			}
+			typing <- m.Data.On
+		}
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, WithTypingTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := call.Typing(ctx); err != nil {
+			t.Fatalf("typing failed: %s", err)
+		}
+	}
+	for _, want := range []bool{true, false} {
+		select {
+		case on := <-typing:
+			if on != want {
+				t.Fatalf("expected typing %t", want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for typing %t", want)
+		}
+	}
+	select {
+	case <-typing:
+		t.Fatalf("unexpected typing message")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCallTypingSendFailure(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	sepp.Stop()
+	session := newCallSession(sepp, nil, "client", "conf", "call", Sdp{}, Sdp{},
+		callHandlers{}, &silentLogger{})
+
+	// a failed typing-start is sent again on the next call.
+	for i := 0; i < 2; i++ {
+		if err := session.Typing(context.Background()); err == nil {
+			t.Fatalf("expected typing to fail")
+		}
+		session.mu.Lock()
+		timer := session.typingTimer
+		session.mu.Unlock()
+		if timer != nil {
+			t.Fatalf("typing timer armed after failure")
+		}
+	}
+
+	session.setState(CallStateTerminated)
+	if err := session.Typing(context.Background()); !errors.Is(err, ErrNoActiveCall) {
+		t.Fatalf("expected ErrNoActiveCall, got %v", err)
+	}
+}
+
+func TestCallFetchChatHistory(t *testing.T) {
+	// the server holds the chat messages 1..5
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		for {
+			req, ok := c.read().(*MsgChatHistoryRequest)
+			if !ok {
+				return
+			}
+			last := 5
+			if req.Data.Before != "" {
+				last = int(req.Data.Before[0]-'0') - 1
+			}
+			reply := MsgChatHistoryData{RefMsgID: req.MsgID, HasMore: true}
+			for i := last - req.Data.Limit + 1; i <= last; i++ {
+				if i < 1 {
+					reply.HasMore = false
+					continue
+				}
+				reply.Messages = append(reply.Messages,
+					MsgChatData{ID: string(rune('0' + i))})
+			}
+			c.write(MsgChatHistory{MsgBase: MsgBase{Type: MsgTypeChatHistory},
+				Data: reply})
+		}
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	history, err := call.FetchChatHistory(ctx, ChatHistoryOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("fetch failed: %s", err)
+	}
+	var ids string
+	for _, m := range history {
+		ids += m.ID
+	}
+	if ids != "12345" {
+		t.Fatalf("unexpected history %q", ids)
+	}
+}
+
+func TestCallFetchChatHistoryIDGenerator(t *testing.T) {
+	msgIDs := make(chan string, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		req, ok := c.read().(*MsgChatHistoryRequest)
+		if !ok {
+			return
+		}
+		msgIDs <- req.MsgID
+		c.write(MsgChatHistory{MsgBase: MsgBase{Type: MsgTypeChatHistory},
+			Data: MsgChatHistoryData{RefMsgID: req.MsgID}})
+		c.read()
+	})
+	defer srv.Close()
+
+	gen := IDGeneratorFunc(func(msg MsgInterface) string {
+		return "gen-" + msg.GetType()
+	})
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, WithSeppOptions(WithIDGenerator(gen)))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if _, err := call.FetchChatHistory(ctx, ChatHistoryOptions{}); err != nil {
+		t.Fatalf("fetch failed: %s", err)
+	}
+	if msgID := <-msgIDs; msgID != "gen-"+MsgTypeChatHistoryReq {
+		t.Fatalf("msg_id not set by the id generator: %q", msgID)
+	}
+}
+
+func TestNewCallValidatesIDs(t *testing.T) {
+	_, err := NewCall(&CallInfo{SigEndpoint: "ws://localhost", ClientID: "client",
+		ConfID: "conf id"}, nil)
+	if !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestCallSendMsgStamping(t *testing.T) {
+	received := make(chan MsgInterface, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		received <- c.read()
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, WithMsgStamping())
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := call.waitConnected(ctx); err != nil {
+		t.Fatalf("connect failed: %s", err)
+	}
+
+	if err := call.SendMsg(MsgChat{Data: MsgChatData{Content: "hi"}}); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case msg := <-received:
+		chat, ok := msg.(*MsgChat)
+		if !ok || chat.From != "client" || chat.To != "conf" ||
+			len(chat.MsgID) == 0 || chat.Data.Content != "hi" {
+			t.Fatalf("message not stamped: %+v", msg)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for message")
+	}
+}
+
+func TestMaxCallDuration(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "kiosk",
+		ConfID: "conf"}, nil, WithMaxCallDuration(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	reasons := make(chan TerminationReason, 1)
+	call.SetTerminationReasonHandler(func(reason TerminationReason) {
+		reasons <- reason
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := call.Start(ctx, Sdp{SdpType: "offer", Sdp: "sdp"}, "kiosk"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	select {
+	case reason := <-reasons:
+		if reason != TerminatedMaxDuration {
+			t.Fatalf("unexpected reason %s", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("call was not terminated")
+	}
+}
+
+func TestNewCallConfig(t *testing.T) {
+	routes := make(chan string, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		routes <- c.header.Get("X-Route")
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallConfig{
+		CallInfo: CallInfo{SigEndpoint: "ws://127.0.0.1:1", ClientID: "client",
+			ConfID: "conf"},
+		Headers:           http.Header{"X-Route": {"blue"}},
+		FallbackEndpoints: []string{srv.URL()},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	select {
+	case route := <-routes:
+		if route != "blue" {
+			t.Fatalf("expected route blue, got %q", route)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the fallback endpoint")
+	}
+}
+
+func TestCallTolerantStart(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgSourceUpdate{MsgBase: MsgBase{Type: MsgTypeSourceUpdate},
+			Data: MsgSourceUpdateData{Sources: []string{"early"}}})
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer"}},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	for _, tolerant := range []bool{false, true} {
+		var options []CallOption
+		if tolerant {
+			options = append(options, WithTolerantStart())
+		}
+		call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+			ConfID: "conf"}, nil, options...)
+		if err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+		defer call.Close()
+		updates := make(chan MsgSourceUpdateData, 1)
+		call.SetSourceUpdateHandler(func(data MsgSourceUpdateData) { updates <- data })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = call.StartSession(ctx, Sdp{SdpType: "offer"}, "bot")
+		var protocolErr *ProtocolError
+		if !tolerant {
+			if !errors.As(err, &protocolErr) {
+				t.Fatalf("expected ProtocolError, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("start failed: %s", err)
+		}
+		select {
+		case data := <-updates:
+			if len(data.Sources) != 1 || data.Sources[0] != "early" {
+				t.Fatalf("unexpected update %+v", data)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for queued source update")
+		}
+	}
+}
+
+func TestCallInitialMemberlist(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgMemberlist{MsgBase: MsgBase{Type: MsgTypeMemberlist},
+			Data: MsgMemberlistData{Add: []Member{{ClientID: "other"}}}})
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer"}},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	joined := make(chan Member, 1)
+	call.SetMemberJoinedHandler(func(m Member) { joined <- m })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if members := session.InitialMembers(); len(members) != 1 ||
+		members[0].ClientID != "other" {
+		t.Fatalf("unexpected initial members %+v", members)
+	}
+	select {
+	case m := <-joined:
+		if m.ClientID != "other" {
+			t.Fatalf("unexpected member %+v", m)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for initial memberlist")
+	}
+}
+
+func TestCallIdentity(t *testing.T) {
+	starts := make(chan *MsgCallStart, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		starts <- start
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer"}},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallConfig{
+		CallInfo: CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+			ConfID: "conf"},
+		DisplayName: "Config",
+		AvatarURL:   "https://example.com/bot.png",
+	}, nil, WithDisplayName("Bot"))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, ""); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	start := <-starts
+	if start.Data.DisplayName != "Bot" ||
+		start.Data.AvatarURL != "https://example.com/bot.png" {
+		t.Fatalf("unexpected identity %+v", start.Data)
+	}
+	// without hello, the server is assumed to be a legacy server.
+	if err := call.UpdateDisplayName(ctx, "Renamed", ""); !errors.Is(err, ErrUnsupportedMsgType) {
+		t.Fatalf("expected ErrUnsupportedMsgType, got %v", err)
+	}
+}
+
+// sentCallStart starts a call with opts and returns its call_start.
+func sentCallStart(t *testing.T, opts ...CallOption) *MsgCallStart {
+	starts := make(chan *MsgCallStart, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		starts <- start
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer"}},
+		})
+		c.read()
+	})
+	t.Cleanup(srv.Close)
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, opts...)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	t.Cleanup(call.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, ""); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	return <-starts
+}
+
+func TestCallMetadata(t *testing.T) {
+	if start := sentCallStart(t); start.Data.Metadata != nil {
+		t.Fatalf("unexpected metadata %+v", start.Data.Metadata)
+	}
+
+	start := sentCallStart(t, WithCallMetadata(map[string]string{"correlation_id": "42"}))
+	if len(start.Data.Metadata) != 1 || start.Data.Metadata["correlation_id"] != "42" {
+		t.Fatalf("unexpected metadata %+v", start.Data.Metadata)
+	}
+}
+
+func TestCallPlatformInfo(t *testing.T) {
+	start := sentCallStart(t)
+	if start.Data.PlatformInfo == nil || *start.Data.PlatformInfo != DefaultPlatformInfo() {
+		t.Fatalf("unexpected platform info %+v", start.Data.PlatformInfo)
+	}
+	if start.Data.PlatformInfo.OS != runtime.GOOS ||
+		start.Data.PlatformInfo.Arch != runtime.GOARCH {
+		t.Fatalf("unexpected platform %+v", start.Data.PlatformInfo)
+	}
+
+	info := PlatformInfo{Name: "kiosk", Version: "v1.2.0", OS: "android", Arch: "arm64"}
+	start = sentCallStart(t, WithPlatformInfo(info))
+	if start.Data.PlatformInfo == nil || *start.Data.PlatformInfo != info {
+		t.Fatalf("unexpected platform info %+v", start.Data.PlatformInfo)
+	}
+}
+
+func TestCallMigrate(t *testing.T) {
+	oldClosed := make(chan struct{})
+	old := newFakeServer(t, func(c *fakeConn) {
+		acceptCalls(c)
+		close(oldClosed)
+	})
+	defer old.Close()
+	next := newFakeServer(t, func(c *fakeConn) {
+		resume, ok := c.read().(*MsgCallResume)
+		if !ok || resume.Data.Sdp.Sdp != "offer-sdp" {
+			t.Errorf("expected call_resume, got %+v", resume)
+			return
+		}
+		c.write(MsgCallResumed{
+			MsgBase: MsgBase{Type: MsgTypeCallResumed},
+			Data: MsgCallResumedData{CallID: resume.Data.CallID,
+				Sdp: Sdp{SdpType: "answer", Sdp: "migrated"}},
+		})
+		acceptCalls(c)
+	})
+	defer next.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: old.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	sdpUpdates := make(chan Sdp, 1)
+	call.SetSDPUpdateHandler(func(sdp Sdp) { sdpUpdates <- sdp })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.Migrate(ctx, next.URL()); err != nil {
+		t.Fatalf("migrate failed: %s", err)
+	}
+	select {
+	case <-oldClosed:
+	case <-ctx.Done():
+		t.Fatalf("previous connection not closed")
+	}
+	select {
+	case sdp := <-sdpUpdates:
+		if sdp.Sdp != "migrated" {
+			t.Fatalf("unexpected sdp %q", sdp.Sdp)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for sdp update")
+	}
+	// the call continues on the new connection.
+	if err := session.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+}
+
+func TestCallTerminateBroadcast(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	var terminations int32
+	call.SetTerminatedHandler(func() { atomic.AddInt32(&terminations, 1) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	// both see the single call_terminated reply.
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- session.Terminate(ctx) }()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil && !errors.Is(err, ErrNoActiveCall) {
+			t.Fatalf("terminate failed: %s", err)
+		}
+	}
+	select {
+	case <-session.Done():
+	default:
+		t.Fatalf("done not closed")
+	}
+	session.terminated()
+	if n := atomic.LoadInt32(&terminations); n != 1 {
+		t.Fatalf("expected one termination, got %d", n)
+	}
+}
+
+func TestCallEarlySdpUpdate(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgSdpUpdate{MsgBase: MsgBase{Type: MsgTypeSdpUpdate},
+			Data: MsgSdpUpdateData{Sdp: Sdp{SdpType: "pranswer", Sdp: "early-sdp"}}})
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer", Sdp: "answer-sdp"}},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	updates := make(chan Sdp, 1)
+	call.SetSDPUpdateHandler(func(sdp Sdp) { updates <- sdp })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	select {
+	case sdp := <-updates:
+		if sdp.Sdp != "early-sdp" {
+			t.Fatalf("unexpected sdp %+v", sdp)
+		}
+	default:
+		t.Fatalf("early sdp not delivered")
+	}
+	if sdp := session.RemoteSdp(); sdp.Sdp != "answer-sdp" {
+		t.Fatalf("unexpected remote sdp %+v", sdp)
+	}
+}
+
+func TestSessionMigrateAfterDispatchStopped(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	inbox := make(chan MsgInterface)
+	session := newCallSession(sepp, inbox, "client", "conf", "call", Sdp{}, Sdp{},
+		callHandlers{}, &silentLogger{})
+	session.start(context.Background())
+	close(inbox)
+	<-session.dispatchDone
+
+	done := make(chan error, 1)
+	go func() { done <- session.migrate(context.Background(), sepp, nil) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrNoActiveCall) {
+			t.Fatalf("expected ErrNoActiveCall, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("migrate blocked on stopped dispatcher")
+	}
+}
+
+func TestCallHold(t *testing.T) {
+	held := make(chan bool, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		for i := 0; i < 2; i++ {
+			m, ok := c.read().(*MsgCallHold)
+			if !ok {
+				return
+			}
+			held <- m.Data.On
+		}
+		// the remote end puts the call on hold.
+		c.write(MsgCallHold{
+			MsgBase: MsgBase{Type: MsgTypeCallHold, From: start.To, To: start.From},
+			Data:    MsgCallHoldData{CallID: "call", On: true},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	remote := make(chan bool, 1)
+	call.SetHoldHandler(func(onHold bool) { remote <- onHold })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := call.Hold(ctx); !errors.Is(err, ErrNoActiveCall) {
+		t.Fatalf("expected ErrNoActiveCall before start, got %v", err)
+	}
+	session, err := call.StartSession(ctx, Sdp{}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.Hold(ctx); err != nil {
+		t.Fatalf("hold failed: %s", err)
+	}
+	if !session.OnHold() {
+		t.Fatalf("expected call on hold")
+	}
+	if err := call.Unhold(ctx); err != nil {
+		t.Fatalf("unhold failed: %s", err)
+	}
+	if session.OnHold() {
+		t.Fatalf("expected call resumed")
+	}
+	for _, want := range []bool{true, false} {
+		select {
+		case on := <-held:
+			if on != want {
+				t.Fatalf("expected hold %t", want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for hold %t", want)
+		}
+	}
+	select {
+	case on := <-remote:
+		if !on || !session.OnHold() {
+			t.Fatalf("remote hold not applied")
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for remote hold")
+	}
+}
+
+func TestCallTransfer(t *testing.T) {
+	transfers := make(chan MsgCallTransferData, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		m, ok := c.read().(*MsgCallTransfer)
+		if !ok {
+			return
+		}
+		transfers <- m.Data
+		// the remote end transfers the call back.
+		c.write(MsgCallTransfer{
+			MsgBase: MsgBase{Type: MsgTypeCallTransfer, From: start.To, To: start.From},
+			Data:    MsgCallTransferData{CallID: "call", Target: "lobby"},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	targets := make(chan string, 1)
+	call.SetTransferHandler(func(target string) { targets <- target })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := call.Transfer(ctx, "other"); !errors.Is(err, ErrNoActiveCall) {
+		t.Fatalf("expected ErrNoActiveCall before start, got %v", err)
+	}
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.Transfer(ctx, "other"); err != nil {
+		t.Fatalf("transfer failed: %s", err)
+	}
+	select {
+	case data := <-transfers:
+		if data.CallID != "call" || data.Target != "other" {
+			t.Fatalf("unexpected transfer %+v", data)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for transfer")
+	}
+	select {
+	case target := <-targets:
+		if target != "lobby" {
+			t.Fatalf("unexpected transfer target %q", target)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for remote transfer")
+	}
+}
+
+func TestCallChatReceipts(t *testing.T) {
+	sent := make(chan MsgChatReceiptData, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call"},
+		})
+		for i := 0; i < 2; i++ {
+			m, ok := c.read().(*MsgChatReceipt)
+			if !ok {
+				return
+			}
+			sent <- m.Data
+		}
+		c.write(MsgChatReceipt{
+			MsgBase: MsgBase{Type: MsgTypeChatReceipt, From: start.To, To: start.From},
+			Data: MsgChatReceiptData{CallID: "call", ID: "7", ClientID: "bot",
+				Status: ChatReceiptRead},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	received := make(chan MsgChatReceiptData, 1)
+	call.SetChatReceiptHandler(func(data MsgChatReceiptData) { received <- data })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := call.MarkChatRead(ctx, "1"); !errors.Is(err, ErrNoActiveCall) {
+		t.Fatalf("expected ErrNoActiveCall before start, got %v", err)
+	}
+	if _, err := call.StartSession(ctx, Sdp{}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := call.MarkChatDelivered(ctx, "1"); err != nil {
+		t.Fatalf("mark delivered failed: %s", err)
+	}
+	if err := call.MarkChatRead(ctx, "1"); err != nil {
+		t.Fatalf("mark read failed: %s", err)
+	}
+	for _, want := range []string{ChatReceiptDelivered, ChatReceiptRead} {
+		select {
+		case data := <-sent:
+			if data.ID != "1" || data.ClientID != "client" || data.Status != want {
+				t.Fatalf("unexpected receipt %+v, expected %s", data, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for %s receipt", want)
+		}
+	}
+	select {
+	case data := <-received:
+		if data.ID != "7" || data.ClientID != "bot" || data.Status != ChatReceiptRead {
+			t.Fatalf("unexpected receipt %+v", data)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for remote receipt")
+	}
+}
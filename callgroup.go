@@ -0,0 +1,183 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// groupEventBufferSize is the capacity of the event channel of a
+// CallGroup.
+const groupEventBufferSize = 64
+
+// GroupEvent is an event of one of the calls of a CallGroup, labeled with
+// its conference. Exactly one of the event fields is set.
+type GroupEvent struct {
+	ConfID       ConfID
+	Terminated   bool
+	Memberlist   *MsgMemberlistData
+	SourceUpdate *MsgSourceUpdateData
+	Err          error
+}
+
+// GroupError is returned by CallGroup operations which failed for some of
+// the conferences.
+type GroupError struct {
+	Errs map[ConfID]error
+}
+
+func (e *GroupError) Error() string {
+	confIDs := make([]string, 0, len(e.Errs))
+	for confID := range e.Errs {
+		confIDs = append(confIDs, string(confID))
+	}
+	sort.Strings(confIDs)
+	msgs := make([]string, len(confIDs))
+	for i, confID := range confIDs {
+		msgs[i] = fmt.Sprintf("%s: %s", confID, e.Errs[ConfID(confID)])
+	}
+	return "group: " + strings.Join(msgs, ", ")
+}
+
+// CallGroup joins several conferences at once, e.g. for bots
+// broadcasting to many rooms. Whether the calls share connections is up
+// to how they are created: with NewCall every call has a connection of
+// its own, with ConnPool.NewCall they share the connections of the pool.
+type CallGroup struct {
+	logger Logger
+	events chan GroupEvent
+	// done is closed on Close, so blocked event deliveries return.
+	done chan struct{}
+	once sync.Once
+
+	mu    sync.Mutex
+	calls map[ConfID]*Call
+}
+
+// NewCallGroup returns an empty group.
+func NewCallGroup(logger Logger) *CallGroup {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	return &CallGroup{
+		logger: logger,
+		events: make(chan GroupEvent, groupEventBufferSize),
+		done:   make(chan struct{}),
+		calls:  make(map[ConfID]*Call),
+	}
+}
+
+// Add adds call to the group. The group takes over the terminated,
+// memberlist, source update and error handlers of the call, whose events
+// are delivered on Events instead.
+func (g *CallGroup) Add(call *Call) error {
+	confID := call.ConfID()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.calls[confID]; ok {
+		return fmt.Errorf("conference %s already in group", confID)
+	}
+	call.SetTerminatedHandler(func() {
+		g.emit(GroupEvent{ConfID: confID, Terminated: true})
+	})
+	call.SetMemberlistHandler(func(data MsgMemberlistData) {
+		g.emit(GroupEvent{ConfID: confID, Memberlist: &data})
+	})
+	call.SetSourceUpdateHandler(func(data MsgSourceUpdateData) {
+		g.emit(GroupEvent{ConfID: confID, SourceUpdate: &data})
+	})
+	call.SetErrorHandler(func(err error) {
+		g.emit(GroupEvent{ConfID: confID, Err: err})
+	})
+	g.calls[confID] = call
+	return nil
+}
+
+// Events returns the channel receiving the events of all calls. It must
+// be consumed, as a full channel delays the calls.
+func (g *CallGroup) Events() <-chan GroupEvent {
+	return g.events
+}
+
+func (g *CallGroup) emit(ev GroupEvent) {
+	select {
+	case g.events <- ev:
+	case <-g.done:
+	}
+}
+
+// Start starts the calls concurrently, each with the sdp returned by
+// sdpFor. Returns a *GroupError listing the calls which failed to start.
+func (g *CallGroup) Start(ctx context.Context, sdpFor func(ConfID) Sdp,
+	displayName string) error {
+	return g.each(func(confID ConfID, call *Call) error {
+		_, err := call.StartSession(ctx, sdpFor(confID), displayName)
+		return err
+	})
+}
+
+// Terminate terminates all active calls concurrently.
+func (g *CallGroup) Terminate(ctx context.Context) error {
+	return g.each(func(confID ConfID, call *Call) error {
+		if session := call.Session(); session == nil || !session.active() {
+			return nil
+		}
+		return call.Terminate(ctx)
+	})
+}
+
+// each runs fn for every call concurrently and collects the errors.
+func (g *CallGroup) each(fn func(ConfID, *Call) error) error {
+	g.mu.Lock()
+	calls := make(map[ConfID]*Call, len(g.calls))
+	for confID, call := range g.calls {
+		calls[confID] = call
+	}
+	g.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make(map[ConfID]error)
+	for confID, call := range calls {
+		wg.Add(1)
+		go func(confID ConfID, call *Call) {
+			defer wg.Done()
+			if err := fn(confID, call); err != nil {
+				mu.Lock()
+				errs[confID] = err
+				mu.Unlock()
+			}
+		}(confID, call)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return &GroupError{Errs: errs}
+	}
+	return nil
+}
+
+// Sessions returns the active sessions by conference.
+func (g *CallGroup) Sessions() map[ConfID]*CallSession {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sessions := make(map[ConfID]*CallSession, len(g.calls))
+	for confID, call := range g.calls {
+		if session := call.Session(); session != nil && session.active() {
+			sessions[confID] = session
+		}
+	}
+	return sessions
+}
+
+// Close closes all calls, without terminating them.
+func (g *CallGroup) Close() {
+	g.once.Do(func() { close(g.done) })
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for confID, call := range g.calls {
+		call.Close()
+		delete(g.calls, confID)
+	}
+}
@@ -0,0 +1,48 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCallGroup(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	pool, err := NewConnPool(srv.URL(), "", nil, nil, 1)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer pool.Close()
+
+	group := NewCallGroup(nil)
+	defer group.Close()
+	for i := 0; i < 3; i++ {
+		call, err := pool.NewCall(ConfID(fmt.Sprintf("conf-%d", i)),
+			ClientID(fmt.Sprintf("bot-%d", i)))
+		if err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+		if err := group.Add(call); err != nil {
+			t.Fatalf("add failed: %s", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sdpFor := func(ConfID) Sdp { return Sdp{SdpType: "offer", Sdp: "sdp"} }
+	if err := group.Start(ctx, sdpFor, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if n := len(group.Sessions()); n != 3 {
+		t.Fatalf("expected 3 sessions, got %d", n)
+	}
+	if err := group.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	if n := len(group.Sessions()); n != 0 {
+		t.Fatalf("expected no active sessions, got %d", n)
+	}
+}
@@ -1,5 +1,10 @@
 package gosepp
 
+import (
+	"crypto/tls"
+	"net/http"
+)
+
 // CallInfoInterface defines a configuration interface,
 // to which the init struct of NewCall must comply.
 type CallInfoInterface interface {
@@ -9,13 +14,75 @@ type CallInfoInterface interface {
 	GetConfID() string
 }
 
+// CallInfoTLS may be implemented by a CallInfoInterface to set the TLS
+// configuration of the call. WithCustomCAFile replaces its root CAs.
+type CallInfoTLS interface {
+	GetTLSConfig() *tls.Config
+}
+
+// CallInfoHeaders may be implemented by a CallInfoInterface to add
+// headers to the connect requests of the call.
+type CallInfoHeaders interface {
+	GetHeaders() http.Header
+}
+
+// CallInfoEndpoints may be implemented by a CallInfoInterface to list
+// fallback endpoints, tried in order if the sig endpoint is unreachable.
+type CallInfoEndpoints interface {
+	GetFallbackEndpoints() []string
+}
+
+// CallInfoIdentity may be implemented by a CallInfoInterface to set the
+// identity shown to the other participants. WithDisplayName and
+// WithAvatarURL take precedence.
+type CallInfoIdentity interface {
+	GetDisplayName() string
+	GetAvatarURL() string
+}
+
 // CallInfo is the default implementation of the
 // CallInfoInterface.
 type CallInfo struct {
-	SigEndpoint string
-	AuthToken   string
-	ClientID    string
-	ConfID      string
+	SigEndpoint string   `json:"sig_endpoint"`
+	AuthToken   string   `json:"auth_token"`
+	ClientID    ClientID `json:"client_id"`
+	ConfID      ConfID   `json:"conf_id"`
+}
+
+// CallConfig extends CallInfo with per-call TLS settings, headers,
+// fallback endpoints and the identity of the participant.
+type CallConfig struct {
+	CallInfo
+	TLSConfig         *tls.Config
+	Headers           http.Header
+	FallbackEndpoints []string
+	DisplayName       string
+	AvatarURL         string
+}
+
+// GetTLSConfig returns the TLS configuration of the call.
+func (c *CallConfig) GetTLSConfig() *tls.Config {
+	return c.TLSConfig
+}
+
+// GetHeaders returns the extra headers of the connect requests.
+func (c *CallConfig) GetHeaders() http.Header {
+	return c.Headers
+}
+
+// GetFallbackEndpoints returns the fallback endpoints.
+func (c *CallConfig) GetFallbackEndpoints() []string {
+	return c.FallbackEndpoints
+}
+
+// GetDisplayName returns the display name of the participant.
+func (c *CallConfig) GetDisplayName() string {
+	return c.DisplayName
+}
+
+// GetAvatarURL returns the avatar url of the participant.
+func (c *CallConfig) GetAvatarURL() string {
+	return c.AvatarURL
 }
 
 // GetSigEndpoint returns the sip-sepp endpoint.
@@ -32,11 +99,44 @@ func (i *CallInfo) GetAuthToken() string {
 // GetClientID returns the clientID which
 // is the initiator of this call.
 func (i *CallInfo) GetClientID() string {
-	return i.ClientID
+	return string(i.ClientID)
 }
 
 // GetConfID returns the confID which
 // is the destination of this call.
 func (i *CallInfo) GetConfID() string {
-	return i.ConfID
+	return string(i.ConfID)
+}
+
+// connConfig returns the TLS configuration and the GoSepp options set by
+// the optional interfaces of callInfo, and its fallback endpoints
+// separately, as migrations don't use them. caFile, if set, replaces the
+// root CAs.
+func connConfig(callInfo CallInfoInterface, caFile string) (*tls.Config,
+	[]GoSeppOption, []string, error) {
+	var tlsConfig *tls.Config
+	if i, ok := callInfo.(CallInfoTLS); ok && i.GetTLSConfig() != nil {
+		tlsConfig = i.GetTLSConfig().Clone()
+	}
+	if len(caFile) > 0 {
+		caConfig, err := NewTLSConfig(TLSRootCAFile(caFile))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = caConfig
+		} else {
+			tlsConfig.RootCAs = caConfig.RootCAs
+		}
+	}
+
+	options := []GoSeppOption{WithPprofLabels("conf_id", callInfo.GetConfID())}
+	if i, ok := callInfo.(CallInfoHeaders); ok && len(i.GetHeaders()) > 0 {
+		options = append(options, WithRequestHeader(i.GetHeaders()))
+	}
+	var endpoints []string
+	if i, ok := callInfo.(CallInfoEndpoints); ok {
+		endpoints = i.GetFallbackEndpoints()
+	}
+	return tlsConfig, options, endpoints, nil
 }
@@ -0,0 +1,100 @@
+package gosepp
+
+import (
+	"sort"
+)
+
+// ProtocolVersion is the sepp protocol version implemented by this library.
+const ProtocolVersion = 1
+
+// legacyMsgTypes are the message types every sepp server supports,
+// including those which don't take part in the hello exchange.
+var legacyMsgTypes = []string{
+	MsgTypeCallStart, MsgTypeCallRejected, MsgTypeCallAccepted,
+	MsgTypeSdpUpdate, MsgTypeCallTerminate, MsgTypeCallTerminated,
+	MsgTypeCallResume, MsgTypeCallResumed, MsgTypeChat,
+	MsgTypeSetPresenter, MsgTypeDesktopstreaming, MsgTypeMuteVideo,
+	MsgTypeSourceUpdate, MsgTypeMemberlist, MsgTypeRecording,
+}
+
+// Capabilities are advertised by both ends in the hello message.
+type Capabilities struct {
+	ProtocolVersion int
+	MsgTypes        []string
+}
+
+// Supports reports whether msgType was advertised.
+func (c Capabilities) Supports(msgType string) bool {
+	for _, t := range c.MsgTypes {
+		if t == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+// localCapabilities returns the capabilities of this library.
+func localCapabilities(version int) Capabilities {
+	types := make([]string, 0, len(SeppMsgTypes)+1)
+	for t := range SeppMsgTypes {
+		types = append(types, t)
+	}
+	// batches are always accepted.
+	types = append(types, BatchCapability)
+	sort.Strings(types)
+	return Capabilities{ProtocolVersion: version, MsgTypes: types}
+}
+
+// WithHandshake sends a hello message advertising the local capabilities
+// after every connect. The capabilities of the remote end are available
+// via Capabilities once its hello was received.
+func WithHandshake() GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.handshake = true
+	}
+}
+
+// Capabilities returns the capabilities advertised by the remote end on
+// the current connection. ok is false if no hello was received, e.g.
+// because the server predates the hello exchange.
+func (rtm *GoSepp) Capabilities() (caps Capabilities, ok bool) {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	if rtm.remoteCaps == nil {
+		return Capabilities{}, false
+	}
+	return *rtm.remoteCaps, true
+}
+
+// Supports reports whether the remote end supports msgType. Without a
+// hello from the remote end only the legacy message types are assumed.
+func (rtm *GoSepp) Supports(msgType string) bool {
+	if caps, ok := rtm.Capabilities(); ok {
+		return caps.Supports(msgType)
+	}
+	for _, t := range legacyMsgTypes {
+		if t == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+func (rtm *GoSepp) setRemoteCapabilities(caps *Capabilities) {
+	rtm.mu.Lock()
+	rtm.remoteCaps = caps
+	rtm.mu.Unlock()
+}
+
+// sendHello advertises the local capabilities on a new connection.
+func (rtm *GoSepp) sendHello() {
+	caps := localCapabilities(rtm.protocolVersion)
+	if err := rtm.SendMsg(MsgHello{
+		MsgBase: MsgBase{Type: MsgTypeHello},
+		Data: MsgHelloData{
+			ProtocolVersion: caps.ProtocolVersion,
+			MsgTypes:        caps.MsgTypes},
+	}); err != nil {
+		rtm.logger.Warn("Failed to send hello [%s].", err)
+	}
+}
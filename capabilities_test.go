@@ -0,0 +1,83 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandshake(t *testing.T) {
+	received := make(chan *MsgHello, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		if hello, ok := c.read().(*MsgHello); ok {
+			received <- hello
+		}
+		c.write(MsgHello{MsgBase: MsgBase{Type: MsgTypeHello},
+			Data: MsgHelloData{ProtocolVersion: 1,
+				MsgTypes: []string{MsgTypeCallStart, MsgTypeCallHold}}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithHandshake())
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	if sepp.Supports(MsgTypeCallHold) {
+		t.Fatalf("expected legacy types only before the hello")
+	}
+	select {
+	case hello := <-received:
+		if hello.Data.ProtocolVersion != ProtocolVersion {
+			t.Fatalf("unexpected version %d", hello.Data.ProtocolVersion)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for hello")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := sepp.Capabilities(); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for capabilities")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !sepp.Supports(MsgTypeCallHold) || sepp.Supports(MsgTypeChat) {
+		t.Fatalf("capabilities not applied")
+	}
+}
+
+type msgRecordingV2 struct {
+	MsgBase
+	Data struct {
+		CallID string `json:"call_id"`
+		State  string `json:"state"`
+	} `json:"data"`
+}
+
+func init() {
+	// the registry must not change while connections decode messages,
+	// so register once for all tests.
+	RegisterMsgVersion(2, MsgTypeRecording,
+		func() MsgInterface { return &msgRecordingV2{} })
+}
+
+func TestDecodeMsgVersion(t *testing.T) {
+	data := []byte(`{"type":"recording","data":{"state":"on"}}`)
+	if msg, err := DecodeMsgVersion(data, 1); err != nil {
+		t.Fatalf("decode failed: %s", err)
+	} else if _, ok := msg.(*MsgRecording); !ok {
+		t.Fatalf("expected v1 schema, got %T", msg)
+	}
+	msg, err := DecodeMsgVersion(data, 3)
+	if err != nil {
+		t.Fatalf("decode failed: %s", err)
+	}
+	if m, ok := msg.(*msgRecordingV2); !ok || m.Data.State != "on" {
+		t.Fatalf("expected v2 schema, got %T", msg)
+	}
+}
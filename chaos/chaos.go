@@ -0,0 +1,185 @@
+// Package chaos wraps gosepp transports to inject network faults:
+// disconnects, delays, reordered and corrupted frames. Faults are drawn
+// from a seeded random source per connection, so a seed reproduces the
+// same schedule. It is meant for resilience tests only.
+//
+//	sepp, err := gosepp.NewGoSepp(endpoint, token, nil, logger,
+//		gosepp.WithTransport(chaos.NewTransport(nil, chaos.Config{
+//			Seed: 1, DisconnectRate: 0.01, CorruptRate: 0.05})))
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+	"github.com/gorilla/websocket"
+)
+
+// ErrInjectedDisconnect is returned by reads of connections closed by
+// an injected disconnect.
+var ErrInjectedDisconnect = errors.New("injected disconnect")
+
+// Fault is a kind of injected fault.
+type Fault int
+
+// Faults injected into received frames.
+const (
+	Disconnect Fault = iota
+	Delay
+	Reorder
+	Corrupt
+)
+
+func (f Fault) String() string {
+	switch f {
+	case Disconnect:
+		return "disconnect"
+	case Delay:
+		return "delay"
+	case Reorder:
+		return "reorder"
+	case Corrupt:
+		return "corrupt"
+	}
+	return "unknown"
+}
+
+// Config sets the probabilities of faults per received frame.
+type Config struct {
+	// Seed of the random source. Connection n of a transport uses
+	// Seed+n, so reconnects get their own, reproducible schedule.
+	Seed int64
+	// DisconnectRate is the probability to close the connection
+	// instead of returning a frame.
+	DisconnectRate float64
+	// DelayRate is the probability to delay a frame by up to MaxDelay.
+	DelayRate float64
+	MaxDelay  time.Duration
+	// ReorderRate is the probability to swap a frame with the next.
+	ReorderRate float64
+	// CorruptRate is the probability to flip a byte of a frame.
+	CorruptRate float64
+	// OnFault is called for every injected fault, if set.
+	OnFault func(conn int, fault Fault)
+}
+
+// NewTransport returns a transport dialing with inner and injecting
+// faults into the received frames. If inner is nil, websockets are
+// dialed with the default dialer of gorilla/websocket.
+func NewTransport(inner gosepp.Transport, config Config) gosepp.Transport {
+	if inner == nil {
+		inner = websocketTransport{}
+	}
+	return &transport{inner: inner, config: config}
+}
+
+type transport struct {
+	inner  gosepp.Transport
+	config Config
+
+	mu    sync.Mutex
+	conns int
+}
+
+func (t *transport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (gosepp.Conn, error) {
+	c, err := t.inner.Dial(ctx, u, header)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	n := t.conns
+	t.conns++
+	t.mu.Unlock()
+	return Wrap(c, n, t.config), nil
+}
+
+// Wrap returns c injecting faults into its received frames, as
+// connection n of a transport with config.
+func Wrap(c gosepp.Conn, n int, config Config) gosepp.Conn {
+	return &conn{
+		Conn:   c,
+		n:      n,
+		config: config,
+		rnd:    rand.New(rand.NewSource(config.Seed + int64(n))),
+	}
+}
+
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+type conn struct {
+	gosepp.Conn
+	n      int
+	config Config
+	// rnd is only used by ReadMessage, which isn't called concurrently.
+	rnd  *rand.Rand
+	held *frame
+}
+
+func (c *conn) ReadMessage() (int, []byte, error) {
+	if c.held != nil {
+		f := c.held
+		c.held = nil
+		return f.messageType, f.data, nil
+	}
+	messageType, data, err := c.Conn.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+	if c.roll(c.config.DisconnectRate) {
+		c.fault(Disconnect)
+		c.Conn.Close()
+		return 0, nil, ErrInjectedDisconnect
+	}
+	if c.roll(c.config.DelayRate) && c.config.MaxDelay > 0 {
+		c.fault(Delay)
+		time.Sleep(time.Duration(c.rnd.Int63n(int64(c.config.MaxDelay))))
+	}
+	if c.roll(c.config.CorruptRate) && len(data) > 0 {
+		c.fault(Corrupt)
+		corrupted := make([]byte, len(data))
+		copy(corrupted, data)
+		corrupted[c.rnd.Intn(len(corrupted))] ^= byte(1 + c.rnd.Intn(255))
+		data = corrupted
+	}
+	if c.roll(c.config.ReorderRate) {
+		nextType, next, err := c.Conn.ReadMessage()
+		if err == nil {
+			c.fault(Reorder)
+			c.held = &frame{messageType, data}
+			return nextType, next, nil
+		}
+	}
+	return messageType, data, nil
+}
+
+func (c *conn) roll(rate float64) bool {
+	return rate > 0 && c.rnd.Float64() < rate
+}
+
+func (c *conn) fault(f Fault) {
+	if c.config.OnFault != nil {
+		c.config.OnFault(c.n, f)
+	}
+}
+
+// websocketTransport dials websockets with the default dialer.
+type websocketTransport struct{}
+
+func (websocketTransport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (gosepp.Conn, error) {
+	c, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
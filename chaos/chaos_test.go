@@ -0,0 +1,79 @@
+package chaos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+// memConn returns numbered frames.
+type memConn struct {
+	n int
+}
+
+func (c *memConn) ReadMessage() (int, []byte, error) {
+	c.n++
+	return gosepp.TextMessage, []byte(fmt.Sprintf("frame-%03d", c.n)), nil
+}
+
+func (c *memConn) WriteMessage(messageType int, data []byte) error {
+	return nil
+}
+
+func (c *memConn) Close() error {
+	return nil
+}
+
+func schedule(seed int64, disconnectRate float64) ([]string, []Fault) {
+	var faults []Fault
+	c := Wrap(&memConn{}, 0, Config{
+		Seed:           seed,
+		DisconnectRate: disconnectRate,
+		DelayRate:      0.1,
+		MaxDelay:       time.Millisecond,
+		ReorderRate:    0.1,
+		CorruptRate:    0.1,
+		OnFault:        func(conn int, f Fault) { faults = append(faults, f) },
+	})
+	var frames []string
+	for i := 0; i < 200; i++ {
+		_, data, err := c.ReadMessage()
+		if err != nil {
+			frames = append(frames, err.Error())
+			break
+		}
+		frames = append(frames, string(data))
+	}
+	return frames, faults
+}
+
+func TestScheduleIsReproducible(t *testing.T) {
+	frames, faults := schedule(42, 0)
+	again, againFaults := schedule(42, 0)
+	if fmt.Sprint(frames) != fmt.Sprint(again) || fmt.Sprint(faults) != fmt.Sprint(againFaults) {
+		t.Fatal("same seed produced different schedules")
+	}
+	kinds := map[Fault]bool{}
+	for _, f := range faults {
+		kinds[f] = true
+	}
+	for _, f := range []Fault{Delay, Reorder, Corrupt} {
+		if !kinds[f] {
+			t.Errorf("no %s fault injected", f)
+		}
+	}
+	other, _ := schedule(7, 0)
+	if fmt.Sprint(frames) == fmt.Sprint(other) {
+		t.Fatal("different seeds produced the same schedule")
+	}
+}
+
+func TestDisconnect(t *testing.T) {
+	frames, faults := schedule(1, 1)
+	if len(frames) != 1 || frames[0] != ErrInjectedDisconnect.Error() ||
+		len(faults) != 1 || faults[0] != Disconnect {
+		t.Fatalf("unexpected frames %q and faults %v", frames, faults)
+	}
+}
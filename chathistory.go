@@ -0,0 +1,100 @@
+package gosepp
+
+import (
+	"context"
+	"time"
+)
+
+// ChatHistoryOptions control FetchChatHistory.
+type ChatHistoryOptions struct {
+	// Before fetches messages older than the chat message with this id.
+	Before string
+	// BeforeTimestamp fetches messages older than this time.
+	BeforeTimestamp time.Time
+	// PageSize is the number of messages requested at once.
+	// Defaults to 50.
+	PageSize int
+	// MaxMessages stops fetching once this many messages were received.
+	// Zero fetches the whole history.
+	MaxMessages int
+}
+
+// FetchChatHistory requests the chat history page by page and returns
+// the messages in chronological order.
+func (s *CallSession) FetchChatHistory(ctx context.Context,
+	opts ChatHistoryOptions) ([]MsgChatData, error) {
+	if !s.active() {
+		return nil, ErrNoActiveCall
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 50
+	}
+
+	var history []MsgChatData
+	before, beforeTs := opts.Before, opts.BeforeTimestamp
+	for {
+		page, err := s.fetchChatPage(ctx, before, beforeTs, opts.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		history = append(page.Messages, history...)
+		if opts.MaxMessages > 0 && len(history) >= opts.MaxMessages {
+			return history[len(history)-opts.MaxMessages:], nil
+		}
+		if !page.HasMore || len(page.Messages) == 0 {
+			return history, nil
+		}
+		before, beforeTs = page.Messages[0].ID, time.Time{}
+	}
+}
+
+func (s *CallSession) fetchChatPage(ctx context.Context, before string,
+	beforeTs time.Time, limit int) (*MsgChatHistoryData, error) {
+	var beforeTimestamp *Timestamp
+	if !beforeTs.IsZero() {
+		beforeTimestamp = &Timestamp{beforeTs}
+	}
+	sepp := s.conn()
+	req := &MsgChatHistoryRequest{
+		MsgBase: MsgBase{
+			Type: MsgTypeChatHistoryReq,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgChatHistoryRequestData{
+			CallID:          string(s.callID),
+			Before:          before,
+			BeforeTimestamp: beforeTimestamp,
+			Limit:           limit},
+	}
+	// the msg_id is assigned upfront, as the reply references it.
+	req = sepp.assignMsgID(req).(*MsgChatHistoryRequest)
+	if len(req.MsgID) == 0 {
+		// without id generator.
+		id, err := newCallID()
+		if err != nil {
+			return nil, err
+		}
+		req.MsgID = string(id)
+	}
+	msgID := req.MsgID
+	reply, err := sepp.sendAndWaitFor(ctx, req, func(msg MsgInterface) bool {
+		m, ok := msg.(*MsgChatHistory)
+		return ok && m.Data.RefMsgID == msgID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reply.(*MsgChatHistory).Data, nil
+}
+
+// FetchChatHistory requests the chat history of the active call, so late
+// joining clients can backfill the conversation.
+func (c *Call) FetchChatHistory(ctx context.Context,
+	opts ChatHistoryOptions) ([]MsgChatData, error) {
+	session, err := c.activeSession()
+	if err != nil {
+		return nil, err
+	}
+	return session.FetchChatHistory(ctx, opts)
+}
@@ -0,0 +1,595 @@
+// Code generated by genclone. DO NOT EDIT.
+
+package gosepp
+
+func (v Member) clone() Member {
+	c := v
+	if v.Platform != nil {
+		p := *v.Platform
+		c.Platform = &p
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgAuth) Clone() *MsgAuth {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgAuthExpired) Clone() *MsgAuthExpired {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallAccepted) Clone() *MsgCallAccepted {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallHold) Clone() *MsgCallHold {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallRedirect) Clone() *MsgCallRedirect {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallRejected) Clone() *MsgCallRejected {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallResume) Clone() *MsgCallResume {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallResumed) Clone() *MsgCallResumed {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func (v MsgCallStart) clone() MsgCallStart {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallStart) Clone() *MsgCallStart {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgCallStartData) clone() MsgCallStartData {
+	c := v
+	if v.Metadata != nil {
+		c.Metadata = make(map[string]string, len(v.Metadata))
+		for k, e := range v.Metadata {
+			c.Metadata[k] = e
+		}
+	}
+	if v.PlatformInfo != nil {
+		p := *v.PlatformInfo
+		c.PlatformInfo = &p
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallTerminate) Clone() *MsgCallTerminate {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallTerminated) Clone() *MsgCallTerminated {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgCallTransfer) Clone() *MsgCallTransfer {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgChat) Clone() *MsgChat {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func (v MsgChatHistory) clone() MsgChatHistory {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgChatHistory) Clone() *MsgChatHistory {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgChatHistoryData) clone() MsgChatHistoryData {
+	c := v
+	if v.Messages != nil {
+		c.Messages = make([]MsgChatData, len(v.Messages))
+		copy(c.Messages, v.Messages)
+	}
+	return c
+}
+
+func (v MsgChatHistoryRequest) clone() MsgChatHistoryRequest {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgChatHistoryRequest) Clone() *MsgChatHistoryRequest {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgChatHistoryRequestData) clone() MsgChatHistoryRequestData {
+	c := v
+	if v.BeforeTimestamp != nil {
+		p := *v.BeforeTimestamp
+		c.BeforeTimestamp = &p
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgChatReceipt) Clone() *MsgChatReceipt {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgDesktopstreaming) Clone() *MsgDesktopstreaming {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgDisplayNameUpdate) Clone() *MsgDisplayNameUpdate {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgEcho) Clone() *MsgEcho {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgError) Clone() *MsgError {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgFileAccept) Clone() *MsgFileAccept {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func (v MsgFileChunk) clone() MsgFileChunk {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgFileChunk) Clone() *MsgFileChunk {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgFileChunkData) clone() MsgFileChunkData {
+	c := v
+	if v.Data != nil {
+		c.Data = make([]byte, len(v.Data))
+		copy(c.Data, v.Data)
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgFileComplete) Clone() *MsgFileComplete {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgFileOffer) Clone() *MsgFileOffer {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgForceDisconnect) Clone() *MsgForceDisconnect {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func (v MsgFragment) clone() MsgFragment {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgFragment) Clone() *MsgFragment {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgFragmentData) clone() MsgFragmentData {
+	c := v
+	if v.Payload != nil {
+		c.Payload = make([]byte, len(v.Payload))
+		copy(c.Payload, v.Payload)
+	}
+	return c
+}
+
+func (v MsgHello) clone() MsgHello {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgHello) Clone() *MsgHello {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgHelloData) clone() MsgHelloData {
+	c := v
+	if v.MsgTypes != nil {
+		c.MsgTypes = make([]string, len(v.MsgTypes))
+		copy(c.MsgTypes, v.MsgTypes)
+	}
+	return c
+}
+
+func (v MsgMemberlist) clone() MsgMemberlist {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgMemberlist) Clone() *MsgMemberlist {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgMemberlistData) clone() MsgMemberlistData {
+	c := v
+	if v.Add != nil {
+		c.Add = make([]Member, len(v.Add))
+		for i := range v.Add {
+			c.Add[i] = v.Add[i].clone()
+		}
+	}
+	if v.Del != nil {
+		c.Del = make([]string, len(v.Del))
+		copy(c.Del, v.Del)
+	}
+	if v.Media != nil {
+		c.Media = make([]Media, len(v.Media))
+		copy(c.Media, v.Media)
+	}
+	return c
+}
+
+func (v MsgMonitor) clone() MsgMonitor {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgMonitor) Clone() *MsgMonitor {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgMonitorData) clone() MsgMonitorData {
+	c := v
+	if v.Events != nil {
+		c.Events = make([]string, len(v.Events))
+		copy(c.Events, v.Events)
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgMuteVideo) Clone() *MsgMuteVideo {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgRecording) Clone() *MsgRecording {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgResumeToken) Clone() *MsgResumeToken {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgSdpUpdate) Clone() *MsgSdpUpdate {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgSetPresenter) Clone() *MsgSetPresenter {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func (v MsgSourceUpdate) clone() MsgSourceUpdate {
+	c := v
+	c.Data = v.Data.clone()
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgSourceUpdate) Clone() *MsgSourceUpdate {
+	if msg == nil {
+		return nil
+	}
+	c := msg.clone()
+	return &c
+}
+
+func (v MsgSourceUpdateData) clone() MsgSourceUpdateData {
+	c := v
+	if v.AudioSources != nil {
+		c.AudioSources = make([]int, len(v.AudioSources))
+		copy(c.AudioSources, v.AudioSources)
+	}
+	if v.VideoSources != nil {
+		c.VideoSources = make([]int, len(v.VideoSources))
+		copy(c.VideoSources, v.VideoSources)
+	}
+	if v.Broadcast != nil {
+		p := *v.Broadcast
+		c.Broadcast = &p
+	}
+	if v.Dimensions != nil {
+		c.Dimensions = make([]Dimension, len(v.Dimensions))
+		copy(c.Dimensions, v.Dimensions)
+	}
+	if v.Sources != nil {
+		c.Sources = make([]string, len(v.Sources))
+		copy(c.Sources, v.Sources)
+	}
+	if v.TextOverlay != nil {
+		p := *v.TextOverlay
+		c.TextOverlay = &p
+	}
+	if v.PresenterSrc != nil {
+		p := *v.PresenterSrc
+		c.PresenterSrc = &p
+	}
+	if v.DesktopstreamerSrc != nil {
+		p := *v.DesktopstreamerSrc
+		c.DesktopstreamerSrc = &p
+	}
+	return c
+}
+
+// Clone returns a deep copy of msg.
+func (msg *MsgTyping) Clone() *MsgTyping {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+// CloneMsg returns a deep copy of msg. Messages of types not
+// declared by this package are returned unchanged.
+func CloneMsg(msg MsgInterface) MsgInterface {
+	switch m := msg.(type) {
+	case *MsgAuth:
+		return m.Clone()
+	case *MsgAuthExpired:
+		return m.Clone()
+	case *MsgCallAccepted:
+		return m.Clone()
+	case *MsgCallHold:
+		return m.Clone()
+	case *MsgCallRedirect:
+		return m.Clone()
+	case *MsgCallRejected:
+		return m.Clone()
+	case *MsgCallResume:
+		return m.Clone()
+	case *MsgCallResumed:
+		return m.Clone()
+	case *MsgCallStart:
+		return m.Clone()
+	case *MsgCallTerminate:
+		return m.Clone()
+	case *MsgCallTerminated:
+		return m.Clone()
+	case *MsgCallTransfer:
+		return m.Clone()
+	case *MsgChat:
+		return m.Clone()
+	case *MsgChatHistory:
+		return m.Clone()
+	case *MsgChatHistoryRequest:
+		return m.Clone()
+	case *MsgChatReceipt:
+		return m.Clone()
+	case *MsgDesktopstreaming:
+		return m.Clone()
+	case *MsgDisplayNameUpdate:
+		return m.Clone()
+	case *MsgEcho:
+		return m.Clone()
+	case *MsgError:
+		return m.Clone()
+	case *MsgFileAccept:
+		return m.Clone()
+	case *MsgFileChunk:
+		return m.Clone()
+	case *MsgFileComplete:
+		return m.Clone()
+	case *MsgFileOffer:
+		return m.Clone()
+	case *MsgForceDisconnect:
+		return m.Clone()
+	case *MsgFragment:
+		return m.Clone()
+	case *MsgHello:
+		return m.Clone()
+	case *MsgMemberlist:
+		return m.Clone()
+	case *MsgMonitor:
+		return m.Clone()
+	case *MsgMuteVideo:
+		return m.Clone()
+	case *MsgRecording:
+		return m.Clone()
+	case *MsgResumeToken:
+		return m.Clone()
+	case *MsgSdpUpdate:
+		return m.Clone()
+	case *MsgSetPresenter:
+		return m.Clone()
+	case *MsgSourceUpdate:
+		return m.Clone()
+	case *MsgTyping:
+		return m.Clone()
+	}
+	return msg
+}
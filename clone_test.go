@@ -0,0 +1,30 @@
+package gosepp
+
+import (
+	"testing"
+)
+
+func TestCloneDoesNotAlias(t *testing.T) {
+	psrc := 1
+	platform := "web"
+	orig := &MsgSourceUpdate{Data: MsgSourceUpdateData{
+		Sources:      []string{"a", "b"},
+		Dimensions:   []Dimension{{Width: 1}},
+		PresenterSrc: &psrc,
+	}}
+	c := CloneMsg(orig).(*MsgSourceUpdate)
+	c.Data.Sources[0] = "x"
+	c.Data.Dimensions[0].Width = 2
+	*c.Data.PresenterSrc = 2
+	if orig.Data.Sources[0] != "a" || orig.Data.Dimensions[0].Width != 1 ||
+		psrc != 1 {
+		t.Fatalf("clone aliases the original: %+v", orig.Data)
+	}
+
+	ml := &MsgMemberlist{Data: MsgMemberlistData{
+		Add: []Member{{ClientID: "a", Platform: &platform}}}}
+	*ml.Clone().Data.Add[0].Platform = "ios"
+	if platform != "web" {
+		t.Fatalf("clone aliases nested pointers")
+	}
+}
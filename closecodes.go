@@ -0,0 +1,106 @@
+package gosepp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+)
+
+// Well-known close codes sent by the signaling server.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	ClosePolicyViolation = 1008
+	CloseInternalError   = 1011
+	CloseServiceRestart  = 1012
+	CloseTryAgainLater   = 1013
+)
+
+// CloseError is the close code and reason sent by the server when it
+// closed the connection. Well-known codes unwrap to ErrServerGoingAway,
+// ErrPolicyViolation or ErrTryAgainLater, others to ErrConnectionClosed.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if len(e.Reason) == 0 {
+		return fmt.Sprintf("closed by server: %d", e.Code)
+	}
+	return fmt.Sprintf("closed by server: %d %s", e.Code, e.Reason)
+}
+
+func (e *CloseError) Unwrap() error {
+	switch e.Code {
+	case CloseGoingAway, CloseServiceRestart:
+		return ErrServerGoingAway
+	case ClosePolicyViolation:
+		return ErrPolicyViolation
+	case CloseTryAgainLater:
+		return ErrTryAgainLater
+	default:
+		return ErrConnectionClosed
+	}
+}
+
+// ClosePolicy decides whether to reconnect after the server closed the
+// connection.
+type ClosePolicy func(*CloseError) bool
+
+// DefaultClosePolicy reconnects unless the server closed the connection
+// for a policy violation, which a reconnect would repeat.
+func DefaultClosePolicy(e *CloseError) bool {
+	return e.Code != ClosePolicyViolation
+}
+
+// WithClosePolicy sets the policy deciding whether to reconnect after
+// the server closed the connection. If not, the state changes to
+// ConnClosed and Err returns the CloseError. Defaults to
+// DefaultClosePolicy.
+func WithClosePolicy(policy ClosePolicy) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.closePolicy = policy
+	}
+}
+
+// SetCloseHandler sets a handler called whenever the server closed the
+// connection. It is called from the receiving goroutine and must not
+// block.
+func (rtm *GoSepp) SetCloseHandler(handler func(*CloseError)) {
+	rtm.mu.Lock()
+	rtm.closeHandler = handler
+	rtm.mu.Unlock()
+}
+
+// handleClose notifies about a close by the server and reports whether
+// to reconnect.
+func (rtm *GoSepp) handleClose(e *CloseError) bool {
+	rtm.logger.Warn("Connection %s.", e)
+	rtm.mu.Lock()
+	handler := rtm.closeHandler
+	rtm.mu.Unlock()
+	if handler != nil {
+		handler(e)
+	}
+	policy := rtm.closePolicy
+	if policy == nil {
+		policy = DefaultClosePolicy
+	}
+	return policy(e)
+}
+
+// asCloseError returns the CloseError of a failed read, or nil if the
+// connection wasn't closed by the server.
+func asCloseError(err error) *CloseError {
+	var closeErr *CloseError
+	if errors.As(err, &closeErr) {
+		return closeErr
+	}
+	var wsErr *websocket.CloseError
+	if errors.As(err, &wsErr) && wsErr.Code != websocket.CloseAbnormalClosure {
+		return &CloseError{Code: wsErr.Code, Reason: wsErr.Text}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package gosepp
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestServerCloseCodes(t *testing.T) {
+	var conns int32
+	srv := newFakeServer(t, func(c *fakeConn) {
+		code := ClosePolicyViolation
+		if atomic.AddInt32(&conns, 1) == 1 {
+			// wait until the close handler is set.
+			c.read()
+			code = CloseGoingAway
+		}
+		c.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(code, "bye"))
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	closes := make(chan *CloseError, 2)
+	sepp.SetCloseHandler(func(e *CloseError) { closes <- e })
+	if ok := <-sepp.ConnectStatusCh(); !ok {
+		t.Fatalf("failed to connect")
+	}
+	if err := sepp.SendMsg(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat}}); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+
+	// going away is reconnected, a policy violation is not.
+	for _, expected := range []error{ErrServerGoingAway, ErrPolicyViolation} {
+		select {
+		case e := <-closes:
+			if !errors.Is(e, expected) || e.Reason != "bye" {
+				t.Fatalf("expected %s, got %s", expected, e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", expected)
+		}
+	}
+	for range sepp.RcvCh() {
+	}
+	if !errors.Is(sepp.Err(), ErrPolicyViolation) {
+		t.Fatalf("expected ErrPolicyViolation, got %v", sepp.Err())
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// errInterrupted is returned by readLine on ctrl-c.
+var errInterrupted = errors.New("interrupted")
+
+// lineReader reads the commands of the repl.
+type lineReader interface {
+	readLine() (string, error)
+	// print writes s without disturbing the line being edited.
+	print(s string)
+	close()
+}
+
+// newLineReader returns a line editor if stdin is a terminal, a plain
+// reader otherwise.
+func newLineReader(prompt string, complete completer) lineReader {
+	restore, err := makeRaw(os.Stdin.Fd())
+	if err != nil {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		return &scanReader{scanner: scanner, out: os.Stdout, prompt: prompt}
+	}
+	e := newLineEditor(os.Stdin, os.Stdout, prompt, complete)
+	e.restore = restore
+	return e
+}
+
+// scanReader reads lines without editing, e.g. from a pipe.
+type scanReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+	prompt  string
+	mu      sync.Mutex
+}
+
+func (r *scanReader) readLine() (string, error) {
+	r.print(r.prompt)
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+func (r *scanReader) print(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprint(r.out, s)
+}
+
+func (r *scanReader) close() {}
+
+// completer returns the candidates for the word ending the line.
+type completer func(line string) []string
+
+// lineEditor reads lines from a terminal in raw mode. It supports
+// moving the cursor, emacs style editing keys, a history navigated with
+// the arrow keys and tab completion.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	prompt   string
+	complete completer
+	restore  func()
+
+	history []string
+
+	// mu guards the line and the output, which is also written by print.
+	mu   sync.Mutex
+	line []rune
+	pos  int
+}
+
+func newLineEditor(in io.Reader, out io.Writer, prompt string,
+	complete completer) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out, prompt: prompt,
+		complete: complete}
+}
+
+func (e *lineEditor) close() {
+	if e.restore != nil {
+		e.restore()
+	}
+}
+
+// print writes s above the line being edited.
+func (e *lineEditor) print(s string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fmt.Fprint(e.out, "\r\x1b[K", s)
+	e.refresh()
+}
+
+// refresh redraws the line and places the cursor. Must be called with
+// mu held.
+func (e *lineEditor) refresh() {
+	fmt.Fprintf(e.out, "\r\x1b[K%s%s", e.prompt, string(e.line))
+	if back := len(e.line) - e.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// set replaces the line, placing the cursor at its end.
+func (e *lineEditor) set(line []rune) {
+	e.line, e.pos = line, len(line)
+}
+
+func (e *lineEditor) insert(r ...rune) {
+	e.line = append(e.line[:e.pos], append(r, e.line[e.pos:]...)...)
+	e.pos += len(r)
+}
+
+func (e *lineEditor) delete(from, to int) {
+	e.line = append(e.line[:from], e.line[to:]...)
+	e.pos = from
+}
+
+// readLine reads the next line. It returns io.EOF on ctrl-d on an empty
+// line and errInterrupted on ctrl-c.
+func (e *lineEditor) readLine() (string, error) {
+	e.mu.Lock()
+	e.set(nil)
+	e.refresh()
+	e.mu.Unlock()
+
+	// the entry being edited is kept while browsing the history.
+	entry, draft := len(e.history), ""
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		e.mu.Lock()
+		switch r {
+		case '\r', '\n':
+			line := string(e.line)
+			fmt.Fprint(e.out, "\r\n")
+			e.mu.Unlock()
+			if len(strings.TrimSpace(line)) > 0 &&
+				(len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+				e.history = append(e.history, line)
+			}
+			return line, nil
+		case 3: // ctrl-c
+			fmt.Fprint(e.out, "^C\r\n")
+			e.mu.Unlock()
+			return "", errInterrupted
+		case 4: // ctrl-d
+			if len(e.line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				e.mu.Unlock()
+				return "", io.EOF
+			}
+			if e.pos < len(e.line) {
+				e.delete(e.pos, e.pos+1)
+			}
+		case 1: // ctrl-a
+			e.pos = 0
+		case 5: // ctrl-e
+			e.pos = len(e.line)
+		case 2: // ctrl-b
+			e.left()
+		case 6: // ctrl-f
+			e.right()
+		case 8, 127: // backspace
+			if e.pos > 0 {
+				e.delete(e.pos-1, e.pos)
+			}
+		case 11: // ctrl-k
+			e.line = e.line[:e.pos]
+		case 21: // ctrl-u
+			e.delete(0, e.pos)
+		case 23: // ctrl-w
+			from := e.pos
+			for from > 0 && e.line[from-1] == ' ' {
+				from--
+			}
+			for from > 0 && e.line[from-1] != ' ' {
+				from--
+			}
+			e.delete(from, e.pos)
+		case '\t':
+			e.tab()
+		case 27: // escape sequence
+			switch e.escape() {
+			case 'A':
+				if entry > 0 {
+					if entry == len(e.history) {
+						draft = string(e.line)
+					}
+					entry--
+					e.set([]rune(e.history[entry]))
+				}
+			case 'B':
+				if entry < len(e.history) {
+					entry++
+					if entry == len(e.history) {
+						e.set([]rune(draft))
+					} else {
+						e.set([]rune(e.history[entry]))
+					}
+				}
+			case 'C':
+				e.right()
+			case 'D':
+				e.left()
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.line)
+			case '3':
+				if e.pos < len(e.line) {
+					e.delete(e.pos, e.pos+1)
+				}
+			}
+		default:
+			if r >= ' ' {
+				e.insert(r)
+			}
+		}
+		e.refresh()
+		e.mu.Unlock()
+	}
+}
+
+func (e *lineEditor) left() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+func (e *lineEditor) right() {
+	if e.pos < len(e.line) {
+		e.pos++
+	}
+}
+
+// escape reads the rest of an escape sequence and returns its final
+// byte, '3' for delete.
+func (e *lineEditor) escape() byte {
+	b, err := e.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return 0
+	}
+	b, err = e.in.ReadByte()
+	if err != nil {
+		return 0
+	}
+	if b >= '0' && b <= '9' {
+		// e.g. 3~ for delete, 1~ and 4~ for home and end.
+		code := b
+		for b != '~' {
+			if b, err = e.in.ReadByte(); err != nil {
+				return 0
+			}
+		}
+		switch code {
+		case '1', '7':
+			return 'H'
+		case '4', '8':
+			return 'F'
+		}
+		return code
+	}
+	return b
+}
+
+// tab completes the word before the cursor. A unique candidate is
+// inserted, otherwise their common prefix, or they're listed.
+func (e *lineEditor) tab() {
+	if e.complete == nil {
+		return
+	}
+	before := string(e.line[:e.pos])
+	word := before[strings.LastIndexAny(before, " \t")+1:]
+	candidates := e.complete(before)
+	if len(candidates) == 0 {
+		return
+	}
+	prefix := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(word) {
+		e.insert([]rune(prefix[len(word):])...)
+		if len(candidates) == 1 {
+			e.insert(' ')
+		}
+		return
+	}
+	if len(candidates) > 1 {
+		sort.Strings(candidates)
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, " "))
+	}
+}
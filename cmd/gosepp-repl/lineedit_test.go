@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineEditor(t *testing.T) {
+	input := strings.Join([]string{
+		"ac\x1b[Db\r",                // cursor left, insert
+		"\x1b[A\x01x\r",              // history, ctrl-a
+		"tem\tcal\tr\x7f\r",          // completion, backspace
+		"one two\x17\x15x\r",         // ctrl-w, ctrl-u
+		"\x1b[A\x1b[A\x1b[A\x1b[B\r", // history up and down
+		"abc\x03",                    // ctrl-c
+		"\x04",                       // ctrl-d
+	}, "")
+	e := newLineEditor(strings.NewReader(input), io.Discard, "> ", completions)
+
+	for _, want := range []string{"abc", "xabc", "template call_", "x", "template call_"} {
+		line, err := e.readLine()
+		if err != nil {
+			t.Fatalf("read failed: %s", err)
+		}
+		if line != want {
+			t.Fatalf("expected %q, got %q", want, line)
+		}
+	}
+	if _, err := e.readLine(); err != errInterrupted {
+		t.Fatalf("expected errInterrupted, got %v", err)
+	}
+	if _, err := e.readLine(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestLineEditorListsCandidates(t *testing.T) {
+	var out bytes.Buffer
+	e := newLineEditor(strings.NewReader("t\t\r"), &out, "> ", completions)
+	if _, err := e.readLine(); err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	for _, c := range []string{"template", "types", "typing"} {
+		if !strings.Contains(out.String(), c) {
+			t.Fatalf("candidate %s not listed in %q", c, out.String())
+		}
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"ty", []string{"types", "typing"}},
+		{"template chat_r", []string{"chat_receipt"}},
+		{"template ", msgTypes()},
+		{"chat ", nil},
+		{"raw {", nil},
+	}
+	for _, tt := range tests {
+		got := completions(tt.line)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q: expected %v, got %v", tt.line, tt.want, got)
+		}
+	}
+}
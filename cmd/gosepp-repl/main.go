@@ -0,0 +1,216 @@
+// Command gosepp-repl connects to a sepp endpoint and sends messages
+// typed on stdin. Received messages are printed decoded as they arrive.
+//
+//	gosepp-repl -endpoint wss://sig.eyeson.com/call -token $TOKEN -from me -to conf
+//
+// Commands:
+//
+//	types                list the message types
+//	template <type>      print a message of type to fill in
+//	<type> [data]        send a message of type with the json data
+//	raw <message>        send a complete json message
+//	quit                 disconnect
+//
+// Message types may be abbreviated by a unique prefix; ambiguous
+// prefixes list the candidates. The from and to headers default to the
+// -from and -to flags. raw sends the message as typed, so it may have
+// a type unknown to gosepp.
+//
+// On a terminal, lines are edited with the arrow and emacs keys, the up
+// and down arrows browse the history and tab completes commands and
+// message types. ctrl-c clears the line, ctrl-d on an empty line quits.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", os.Getenv(gosepp.EnvSigEndpoint), "signaling endpoint")
+	token := flag.String("token", os.Getenv(gosepp.EnvAuthToken), "auth token")
+	from := flag.String("from", os.Getenv(gosepp.EnvClientID), "default from header")
+	to := flag.String("to", os.Getenv(gosepp.EnvConfID), "default to header")
+	flag.Parse()
+	if len(*endpoint) == 0 {
+		log.Fatal("missing -endpoint")
+	}
+
+	sepp, err := gosepp.NewGoSepp(*endpoint, *token, nil, nil)
+	if err != nil {
+		log.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	select {
+	case connected, ok := <-sepp.ConnectStatusCh():
+		if !ok || !connected {
+			log.Fatal("failed to connect")
+		}
+	case <-time.After(10 * time.Second):
+		log.Fatal("failed to connect")
+	}
+	fmt.Printf("connected to %s. type help for commands.\n", *endpoint)
+
+	in := newLineReader("> ", completions)
+	defer in.close()
+	go func() {
+		for msg := range sepp.RcvCh() {
+			b, _ := json.MarshalIndent(msg, "< ", "  ")
+			in.print(fmt.Sprintf("< %s\n", b))
+		}
+		in.print("connection closed\n")
+		in.close()
+		os.Exit(0)
+	}()
+
+	r := &repl{sepp: sepp, from: *from, to: *to}
+	for {
+		line, err := in.readLine()
+		if err == errInterrupted {
+			continue
+		}
+		if err != nil || !r.exec(strings.TrimSpace(line)) {
+			return
+		}
+	}
+}
+
+type repl struct {
+	sepp     *gosepp.GoSepp
+	from, to string
+}
+
+// exec runs line and reports whether to continue.
+func (r *repl) exec(line string) bool {
+	cmd, arg := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		cmd, arg = line[:i], strings.TrimSpace(line[i:])
+	}
+	switch cmd {
+	case "":
+	case "quit", "exit":
+		return false
+	case "help":
+		fmt.Println("types | template <type> | <type> [data] | raw <message> | quit")
+	case "types":
+		fmt.Println(strings.Join(msgTypes(), "\n"))
+	case "template":
+		if msgType, ok := complete(arg); ok {
+			b, _ := json.MarshalIndent(r.newMsg(msgType), "", "  ")
+			fmt.Println(string(b))
+		}
+	case "raw":
+		// sent as typed, as the type may not be known to gosepp.
+		if !json.Valid([]byte(arg)) {
+			fmt.Println("invalid message: not json")
+			return true
+		}
+		if err := r.sepp.SendMsg(json.RawMessage(arg)); err != nil {
+			fmt.Printf("failed to send: %s\n", err)
+		}
+	default:
+		msgType, ok := complete(cmd)
+		if !ok {
+			return true
+		}
+		msg := r.newMsg(msgType)
+		if len(arg) > 0 {
+			data := reflect.ValueOf(msg).Elem().FieldByName("Data")
+			if err := json.Unmarshal([]byte(arg), data.Addr().Interface()); err != nil {
+				fmt.Printf("invalid data: %s\n", err)
+				return true
+			}
+		}
+		r.send(msg)
+	}
+	return true
+}
+
+func (r *repl) newMsg(msgType string) gosepp.MsgInterface {
+	msg := gosepp.SeppMsgTypes[msgType]()
+	reflect.ValueOf(msg).Elem().FieldByName("Type").SetString(msgType)
+	msg.SetFrom(r.from)
+	msg.SetTo(r.to)
+	return msg
+}
+
+func (r *repl) send(msg gosepp.MsgInterface) {
+	if len(msg.GetFrom()) == 0 {
+		msg.SetFrom(r.from)
+	}
+	if len(msg.GetTo()) == 0 {
+		msg.SetTo(r.to)
+	}
+	if err := r.sepp.SendMsg(msg); err != nil {
+		fmt.Printf("failed to send: %s\n", err)
+	}
+}
+
+// commands are the commands besides the message types.
+var commands = []string{"help", "quit", "raw", "template", "types"}
+
+// completions returns the commands and message types completing the
+// last word of line. Only the first word and the type of template are
+// completed.
+func completions(line string) []string {
+	words := strings.Fields(line)
+	if strings.HasSuffix(line, " ") || len(words) == 0 {
+		words = append(words, "")
+	}
+	var candidates []string
+	switch {
+	case len(words) == 1:
+		candidates = append(append([]string{}, commands...), msgTypes()...)
+	case len(words) == 2 && words[0] == "template":
+		candidates = msgTypes()
+	}
+	word := words[len(words)-1]
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func msgTypes() []string {
+	types := make([]string, 0, len(gosepp.SeppMsgTypes))
+	for msgType := range gosepp.SeppMsgTypes {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// complete returns the message type prefix abbreviates, printing the
+// candidates if there is no unique one.
+func complete(prefix string) (string, bool) {
+	if _, ok := gosepp.SeppMsgTypes[prefix]; ok {
+		return prefix, true
+	}
+	var candidates []string
+	for _, msgType := range msgTypes() {
+		if strings.HasPrefix(msgType, prefix) {
+			candidates = append(candidates, msgType)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		fmt.Printf("unknown message type %q\n", prefix)
+	case 1:
+		return candidates[0], true
+	default:
+		fmt.Println(strings.Join(candidates, " "))
+	}
+	return "", false
+}
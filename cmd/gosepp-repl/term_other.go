@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package main
+
+import "errors"
+
+// makeRaw isn't supported, lines are read without editing.
+func makeRaw(fd uintptr) (restore func(), err error) {
+	return nil, errors.New("line editing not supported")
+}
@@ -0,0 +1,173 @@
+// Command gosepp-sniff attaches to a conference as monitor and writes
+// every event as JSON line, for triaging calls.
+//
+//	gosepp-sniff -endpoint wss://sig.eyeson.com/call -token $TOKEN \
+//		-client support -conf $CONF -types chat,memberlist -o events.jsonl
+//
+// Every line holds the receive time, the message type and the message.
+// -types and -clients restrict the output to the listed message types,
+// and to messages from or about the listed clients, including
+// memberlists adding or removing them. gosepp-sniff exits on interrupt
+// or once the connection is given up. -ca, -header and
+// -fallback configure the connection like the CallConfig of a call.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+// event is a line of the output.
+type event struct {
+	Time time.Time           `json:"ts"`
+	Type string              `json:"type"`
+	Msg  gosepp.MsgInterface `json:"msg"`
+}
+
+func main() {
+	endpoint := flag.String("endpoint", os.Getenv(gosepp.EnvSigEndpoint), "signaling endpoint")
+	token := flag.String("token", os.Getenv(gosepp.EnvAuthToken), "auth token")
+	clientID := flag.String("client", os.Getenv(gosepp.EnvClientID), "client-id of the monitor")
+	confID := flag.String("conf", os.Getenv(gosepp.EnvConfID), "conference to monitor")
+	types := flag.String("types", "", "comma separated message types to write, all if empty")
+	clients := flag.String("clients", "", "comma separated client-ids to write, all if empty")
+	output := flag.String("o", "-", "output file, - for stdout")
+	caFile := flag.String("ca", "", "root CA file, the system roots if empty")
+	fallbacks := flag.String("fallback", "", "comma separated fallback endpoints")
+	headers := make(http.Header)
+	flag.Func("header", "header of the connect request as \"Name: value\", repeatable",
+		func(header string) error {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok {
+				return errors.New("expected \"Name: value\"")
+			}
+			headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			return nil
+		})
+	flag.Parse()
+
+	info := &gosepp.CallConfig{
+		CallInfo: gosepp.CallInfo{SigEndpoint: *endpoint, AuthToken: *token,
+			ClientID: gosepp.ClientID(*clientID), ConfID: gosepp.ConfID(*confID)},
+		Headers: headers,
+	}
+	if err := info.Validate(); err != nil {
+		log.Fatalf("invalid arguments: %s", err)
+	}
+	// fallbacks are tried in order.
+	for _, fallback := range strings.Split(*fallbacks, ",") {
+		if fallback = strings.TrimSpace(fallback); len(fallback) > 0 {
+			info.FallbackEndpoints = append(info.FallbackEndpoints, fallback)
+		}
+	}
+	if len(*caFile) > 0 {
+		tlsConfig, err := gosepp.NewTLSConfig(gosepp.TLSRootCAFile(*caFile))
+		if err != nil {
+			log.Fatalf("failed: %s", err)
+		}
+		info.TLSConfig = tlsConfig
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("failed: %s", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	typeFilter := set(*types)
+	clientFilter := set(*clients)
+
+	monitor, err := gosepp.NewMonitor(info, nil)
+	if err != nil {
+		log.Fatalf("failed: %s", err)
+	}
+	defer monitor.Close()
+	monitor.SetEvents(nil)
+	monitor.SetEventHandler(func(msg gosepp.MsgInterface) {
+		if len(typeFilter) > 0 && !typeFilter[msg.GetType()] {
+			return
+		}
+		if len(clientFilter) > 0 && !about(msg, clientFilter) {
+			return
+		}
+		at := time.Now()
+		if r, ok := msg.(interface{ ReceivedAt() time.Time }); ok && !r.ReceivedAt().IsZero() {
+			at = r.ReceivedAt()
+		}
+		if err := enc.Encode(event{Time: at, Type: msg.GetType(), Msg: msg}); err != nil {
+			log.Fatalf("failed to write: %s", err)
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	startCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := monitor.Start(startCtx); err != nil {
+		log.Fatalf("failed to start monitor: %s", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-monitor.Done():
+		log.Print("connection closed")
+	}
+}
+
+func set(list string) map[string]bool {
+	s := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			s[item] = true
+		}
+	}
+	return s
+}
+
+// about reports whether msg is from or about one of clients.
+func about(msg gosepp.MsgInterface, clients map[string]bool) bool {
+	if clients[msg.GetFrom()] || clients[dataClientID(msg)] {
+		return true
+	}
+	if m, ok := msg.(*gosepp.MsgMemberlist); ok {
+		for _, member := range m.Data.Add {
+			if clients[member.ClientID] {
+				return true
+			}
+		}
+		for _, clientID := range m.Data.Del {
+			if clients[clientID] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dataClientID returns the client-id in the data of msg, if any.
+func dataClientID(msg gosepp.MsgInterface) string {
+	data := reflect.Indirect(reflect.ValueOf(msg)).FieldByName("Data")
+	if !data.IsValid() || data.Kind() != reflect.Struct {
+		return ""
+	}
+	clientID := data.FieldByName("ClientID")
+	if !clientID.IsValid() || clientID.Kind() != reflect.String {
+		return ""
+	}
+	return clientID.String()
+}
@@ -0,0 +1,140 @@
+package gosepp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by CallInfoFromEnv.
+const (
+	EnvSigEndpoint = "GOSEPP_SIG_ENDPOINT"
+	EnvAuthToken   = "GOSEPP_AUTH_TOKEN"
+	EnvClientID    = "GOSEPP_CLIENT_ID"
+	EnvConfID      = "GOSEPP_CONF_ID"
+)
+
+// CallInfoFromEnv returns a validated CallInfo read from the GOSEPP_*
+// environment variables.
+func CallInfoFromEnv() (*CallInfo, error) {
+	info := &CallInfo{
+		SigEndpoint: os.Getenv(EnvSigEndpoint),
+		AuthToken:   os.Getenv(EnvAuthToken),
+		ClientID:    ClientID(os.Getenv(EnvClientID)),
+		ConfID:      ConfID(os.Getenv(EnvConfID)),
+	}
+	if err := info.Validate(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// CallInfoFromFile returns a validated CallInfo read from the JSON or
+// YAML file at path, selected by its extension. The keys are
+// sig_endpoint, auth_token, client_id and conf_id. Only flat YAML
+// mappings of scalars are supported.
+func CallInfoFromFile(path string) (*CallInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info := &CallInfo{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, info)
+	case ".yaml", ".yml":
+		err = unmarshalFlatYAML(data, info)
+	default:
+		return nil, fmt.Errorf("unsupported config file %q", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := info.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return info, nil
+}
+
+// Validate checks that the endpoint is an absolute url and the ids are
+// well-formed.
+func (i *CallInfo) Validate() error {
+	if len(i.SigEndpoint) == 0 {
+		return errors.New("sig_endpoint: missing")
+	}
+	u, err := url.Parse(i.SigEndpoint)
+	if err != nil {
+		return fmt.Errorf("sig_endpoint: %w", err)
+	}
+	if len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("sig_endpoint: %q is not an absolute url", i.SigEndpoint)
+	}
+	if err := i.ClientID.Validate(); err != nil {
+		return fmt.Errorf("client_id: %w", err)
+	}
+	if err := i.ConfID.Validate(); err != nil {
+		return fmt.Errorf("conf_id: %w", err)
+	}
+	return nil
+}
+
+// unmarshalFlatYAML decodes a YAML mapping of scalars into v by
+// converting it to JSON.
+func unmarshalFlatYAML(data []byte, v interface{}) error {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") || text == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return fmt.Errorf("line %d: expected key: value", line)
+		}
+		value, err := flatYAMLScalar(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// flatYAMLScalar returns the scalar value, unquoted and without a
+// trailing comment. A # only starts a comment outside quotes.
+func flatYAMLScalar(value string) (string, error) {
+	if len(value) == 0 || (value[0] != '"' && value[0] != '\'') {
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		return value, nil
+	}
+	quote := value[0]
+	end := 1
+	for ; end < len(value) && value[end] != quote; end++ {
+		if quote == '"' && value[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(value) {
+		return "", errors.New("unterminated quoted value")
+	}
+	if rest := strings.TrimSpace(value[end+1:]); len(rest) > 0 && rest[0] != '#' {
+		return "", errors.New("unexpected text after quoted value")
+	}
+	return value[1:end], nil
+}
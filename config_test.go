@@ -0,0 +1,90 @@
+package gosepp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCallInfoFromFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"info.json": `{"sig_endpoint": "wss://sepp.example.com", "auth_token": "token",
+			"client_id": "client", "conf_id": "conf"}`,
+		"info.yaml": "# call\nsig_endpoint: wss://sepp.example.com\n" +
+			"auth_token: \"token\"\nclient_id: client # bot\nconf_id: 'conf'\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+		info, err := CallInfoFromFile(path)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		want := CallInfo{"wss://sepp.example.com", "token", "client", "conf"}
+		if *info != want {
+			t.Fatalf("%s: expected %+v, got %+v", name, want, *info)
+		}
+	}
+}
+
+func TestUnmarshalFlatYAMLQuotes(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+		err  string
+	}{
+		{`auth_token: "abc #def"`, "abc #def", ""},
+		{`auth_token: 'abc #def' # comment`, "abc #def", ""},
+		{`auth_token: "a\"b #c"#d`, `a\"b #c`, ""},
+		{`auth_token: abc #def`, "abc", ""},
+		{`auth_token: abc#def`, "abc#def", ""},
+		{`auth_token: "abc #def`, "", "line 1: unterminated quoted value"},
+		{`auth_token: "abc" def`, "", "line 1: unexpected text after quoted value"},
+	}
+	for _, tt := range tests {
+		var info CallInfo
+		err := unmarshalFlatYAML([]byte(tt.yaml), &info)
+		if len(tt.err) > 0 {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: expected error %q, got %v", tt.yaml, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || info.AuthToken != tt.want {
+			t.Errorf("%s: expected %q, got %q (%v)", tt.yaml, tt.want, info.AuthToken, err)
+		}
+	}
+}
+
+func TestCallInfoValidate(t *testing.T) {
+	tests := []struct {
+		info CallInfo
+		err  string
+	}{
+		{CallInfo{"", "t", "client", "conf"}, "sig_endpoint: missing"},
+		{CallInfo{"sepp.example.com", "t", "client", "conf"}, "not an absolute url"},
+		{CallInfo{"wss://sepp.example.com", "t", "my client", "conf"}, "client_id: invalid id"},
+		{CallInfo{"wss://sepp.example.com", "t", "client", ""}, "conf_id: invalid id"},
+	}
+	for _, test := range tests {
+		err := test.info.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%+v: expected %q, got %v", test.info, test.err, err)
+		}
+	}
+}
+
+func TestCallInfoFromEnv(t *testing.T) {
+	t.Setenv(EnvSigEndpoint, "wss://sepp.example.com")
+	t.Setenv(EnvAuthToken, "token")
+	t.Setenv(EnvClientID, "client")
+	t.Setenv(EnvConfID, "conf")
+	info, err := CallInfoFromEnv()
+	if err != nil || info.GetConfID() != "conf" {
+		t.Fatalf("unexpected %+v, %v", info, err)
+	}
+}
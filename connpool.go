@@ -0,0 +1,249 @@
+package gosepp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// callInboxSize is the capacity of the inbox of a pooled call.
+const callInboxSize = 16
+
+// callControlTypes are never dropped for a full inbox, as the call
+// state would go out of sync with the remote end.
+var callControlTypes = map[string]bool{
+	MsgTypeCallAccepted:   true,
+	MsgTypeCallRejected:   true,
+	MsgTypeCallTerminate:  true,
+	MsgTypeCallTerminated: true,
+	MsgTypeSdpUpdate:      true,
+	MsgTypeCallResumed:    true,
+	MsgTypeCallHold:       true,
+	MsgTypeCallTransfer:   true,
+	MsgTypeCallRedirect:   true,
+}
+
+// ConnPool manages several signaling connections to the same endpoint and
+// assigns calls to them round-robin, so a gateway with many calls isn't
+// limited by the head-of-line blocking of a single websocket. Received
+// messages are routed to the call by the client-id they are addressed to,
+// which therefore must be unique within the pool.
+type ConnPool struct {
+	conns  []*poolConn
+	next   uint32
+	logger Logger
+	// mu serializes adding calls, so client-ids stay unique.
+	mu sync.Mutex
+}
+
+// poolConn is a connection of the pool along with its calls.
+type poolConn struct {
+	sepp   *GoSepp
+	logger Logger
+	// ready is closed once the connection was established.
+	ready     chan struct{}
+	readyOnce sync.Once
+	// stopped is closed once the connection was stopped.
+	stopped chan struct{}
+
+	mu     sync.Mutex
+	calls  map[ClientID]*pooledCall
+	closed bool
+}
+
+// pooledCall routes the messages of a connection to a call.
+type pooledCall struct {
+	conn     *poolConn
+	clientID ClientID
+	inbox    chan MsgInterface
+	// done is closed on release, so a blocked delivery returns.
+	done chan struct{}
+}
+
+// NewConnPool opens size connections to endpoint.
+func NewConnPool(endpoint, authToken string, tlsConfig *tls.Config,
+	logger Logger, size int, options ...GoSeppOption) (*ConnPool, error) {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	if size < 1 {
+		size = 1
+	}
+	p := &ConnPool{logger: logger}
+	for i := 0; i < size; i++ {
+		sepp, err := NewGoSepp(endpoint, authToken, tlsConfig, logger, options...)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		pc := &poolConn{
+			sepp:    sepp,
+			logger:  logger,
+			ready:   make(chan struct{}),
+			stopped: make(chan struct{}),
+			calls:   make(map[ClientID]*pooledCall),
+		}
+		p.conns = append(p.conns, pc)
+		go pc.route()
+	}
+	return p, nil
+}
+
+// NewCall returns a call using the next connection of the pool. Closing
+// the call doesn't close the connection.
+func (p *ConnPool) NewCall(confID ConfID, clientID ClientID,
+	options ...CallOption) (*Call, error) {
+	call, err := newCall(confID, clientID, p.logger, options...)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pc := range p.conns {
+		if pc.has(clientID) {
+			return nil, fmt.Errorf("client-id %s already in use", clientID)
+		}
+	}
+	pc := p.conns[int(atomic.AddUint32(&p.next, 1)-1)%len(p.conns)]
+	pooled, err := pc.add(clientID)
+	if err != nil {
+		return nil, err
+	}
+	call.sepp = pc.sepp
+	call.inbox = pooled.inbox
+	call.pooled = pooled
+	return call, nil
+}
+
+// Size returns the number of connections.
+func (p *ConnPool) Size() int {
+	return len(p.conns)
+}
+
+// Close closes all connections of the pool.
+func (p *ConnPool) Close() {
+	for _, pc := range p.conns {
+		pc.sepp.Stop()
+	}
+}
+
+func (pc *poolConn) add(clientID ClientID) (*pooledCall, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.closed {
+		return nil, ErrNotRunning
+	}
+	c := &pooledCall{
+		conn:     pc,
+		clientID: clientID,
+		inbox:    make(chan MsgInterface, callInboxSize),
+		done:     make(chan struct{}),
+	}
+	pc.calls[clientID] = c
+	return c, nil
+}
+
+func (pc *poolConn) has(clientID ClientID) bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	_, ok := pc.calls[clientID]
+	return ok
+}
+
+// release removes the call from its connection.
+func (c *pooledCall) release() {
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+	if c.conn.calls[c.clientID] == c {
+		delete(c.conn.calls, c.clientID)
+		close(c.done)
+	}
+}
+
+// route delivers received messages to the addressed call until the
+// connection is stopped.
+func (pc *poolConn) route() {
+	status := pc.sepp.ConnectStatusCh()
+	for {
+		select {
+		case connected, ok := <-status:
+			if !ok {
+				status = nil
+				continue
+			}
+			if connected {
+				pc.readyOnce.Do(func() { close(pc.ready) })
+			}
+		case msg, ok := <-pc.sepp.RcvCh():
+			if !ok {
+				pc.close()
+				return
+			}
+			pc.deliver(msg)
+		}
+	}
+}
+
+// recipient returns the client the message is addressed to.
+func (msg *MsgBase) recipient() ClientID {
+	return ClientID(msg.To)
+}
+
+// deliver passes msg to the addressed call. Messages for calls not
+// keeping up are dropped, so they can't block the other calls, except
+// call control messages, which wait for the call to catch up.
+func (pc *poolConn) deliver(msg MsgInterface) {
+	var to ClientID
+	if m, ok := msg.(interface{ recipient() ClientID }); ok {
+		to = m.recipient()
+	}
+	pc.mu.Lock()
+	c, ok := pc.calls[to]
+	pc.mu.Unlock()
+	if !ok {
+		pc.logger.Debug("Dropping %s message to unknown client %s.",
+			msg.GetType(), to)
+		return
+	}
+	// the inbox is only closed by route, which calls deliver.
+	if callControlTypes[msg.GetType()] {
+		select {
+		case c.inbox <- msg:
+		case <-c.done:
+		case <-pc.sepp.receiverCtx.Done():
+		}
+		return
+	}
+	select {
+	case c.inbox <- msg:
+	default:
+		pc.logger.Warn("Dropping %s message, inbox of %s is full.",
+			msg.GetType(), to)
+	}
+}
+
+// close closes the inboxes of all calls, once the connection stopped.
+func (pc *poolConn) close() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.closed = true
+	close(pc.stopped)
+	for clientID, c := range pc.calls {
+		delete(pc.calls, clientID)
+		close(c.inbox)
+	}
+}
+
+// waitConnected blocks until the connection was established once.
+func (pc *poolConn) waitConnected(ctx context.Context) error {
+	select {
+	case <-pc.ready:
+		return nil
+	case <-pc.stopped:
+		return ErrConnectFailed
+	case <-ctx.Done():
+		return ctxError(ctx, "wait for connect")
+	}
+}
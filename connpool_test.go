@@ -0,0 +1,97 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestConnPool(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	pool, err := NewConnPool(srv.URL(), "", nil, nil, 2)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	seen := make(map[*GoSepp]bool)
+	for i := 0; i < 4; i++ {
+		call, err := pool.NewCall("conf", ClientID(fmt.Sprintf("client-%d", i)))
+		if err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+		defer call.Close()
+		seen[call.sepp] = true
+		if _, _, err := call.Start(ctx, Sdp{SdpType: "offer", Sdp: "sdp"}, "test"); err != nil {
+			t.Fatalf("start %d failed: %s", i, err)
+		}
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected calls on 2 connections, got %d", len(seen))
+	}
+	if _, err := pool.NewCall("conf", "client-1"); err == nil {
+		t.Fatalf("expected duplicate client-id to fail")
+	}
+}
+
+func TestConnPoolFullInbox(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pc := &poolConn{
+		sepp:   &GoSepp{receiverCtx: ctx},
+		logger: &silentLogger{},
+		calls:  make(map[ClientID]*pooledCall),
+	}
+	c, err := pc.add("client")
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	for i := 0; i <= callInboxSize; i++ {
+		pc.deliver(&MsgChat{MsgBase: MsgBase{Type: MsgTypeChat, To: "client"}})
+	}
+	if n := len(c.inbox); n != callInboxSize {
+		t.Fatalf("expected %d messages in the inbox, got %d", callInboxSize, n)
+	}
+
+	// call control messages wait for the call instead of being dropped.
+	deliverAsync := func(msg MsgInterface) <-chan struct{} {
+		delivered := make(chan struct{})
+		go func() {
+			pc.deliver(msg)
+			close(delivered)
+		}()
+		return delivered
+	}
+	delivered := deliverAsync(&MsgCallTerminated{
+		MsgBase: MsgBase{Type: MsgTypeCallTerminated, To: "client"}})
+	select {
+	case <-delivered:
+		t.Fatalf("call_terminated not waiting for the call")
+	case <-time.After(50 * time.Millisecond):
+	}
+	for i := 0; i < callInboxSize; i++ {
+		<-c.inbox
+	}
+	<-delivered
+	if msg := <-c.inbox; msg.GetType() != MsgTypeCallTerminated {
+		t.Fatalf("expected call_terminated, got %s", msg.GetType())
+	}
+
+	// releasing the call ends a pending delivery.
+	for i := 0; i < callInboxSize; i++ {
+		c.inbox <- &MsgChat{}
+	}
+	delivered = deliverAsync(&MsgSdpUpdate{
+		MsgBase: MsgBase{Type: MsgTypeSdpUpdate, To: "client"}})
+	c.release()
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("delivery blocked after release")
+	}
+}
@@ -0,0 +1,58 @@
+package gosepp
+
+// ConnState is the state of the signaling connection.
+type ConnState int
+
+const (
+	// ConnConnecting until the first connection is established.
+	ConnConnecting ConnState = iota
+	// ConnConnected while connected.
+	ConnConnected
+	// ConnReconnecting after the connection was lost, until it is
+	// re-established.
+	ConnReconnecting
+	// ConnClosed once stopped, or an accepted connection is lost. It is
+	// final.
+	ConnClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case ConnConnecting:
+		return "connecting"
+	case ConnConnected:
+		return "connected"
+	case ConnReconnecting:
+		return "reconnecting"
+	case ConnClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// SetConnStateHandler sets a handler called on every change of the
+// connection state. It is called from the receiving goroutine and must
+// not block.
+func (rtm *GoSepp) SetConnStateHandler(handler func(ConnState)) {
+	rtm.mu.Lock()
+	rtm.connStateHandler = handler
+	rtm.mu.Unlock()
+}
+
+// State returns the current connection state.
+func (rtm *GoSepp) State() ConnState {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.state
+}
+
+func (rtm *GoSepp) setState(state ConnState) {
+	rtm.mu.Lock()
+	changed := rtm.state != state
+	rtm.state = state
+	handler := rtm.connStateHandler
+	rtm.mu.Unlock()
+	if changed && handler != nil {
+		handler(state)
+	}
+}
@@ -0,0 +1,93 @@
+package gosepp
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConnState(t *testing.T) {
+	drop := make(chan struct{})
+	var conns int32
+	srv := newFakeServer(t, func(c *fakeConn) {
+		if atomic.AddInt32(&conns, 1) == 1 {
+			<-drop
+			return
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	states := make(chan ConnState, 8)
+	sepp.SetConnStateHandler(func(state ConnState) { states <- state })
+	<-sepp.ConnectStatusCh()
+	if state := sepp.State(); state != ConnConnected {
+		t.Fatalf("expected connected, got %s", state)
+	}
+	// the handler may have seen the first connect.
+	select {
+	case <-states:
+	default:
+	}
+	close(drop)
+
+	for _, want := range []ConnState{ConnReconnecting, ConnConnected, ConnClosed} {
+		if want == ConnClosed {
+			sepp.Stop()
+		}
+		select {
+		case state := <-states:
+			if state != want {
+				t.Fatalf("expected %s, got %s", want, state)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
+
+func TestMaxReconnects(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil, WithMaxReconnects(1, 0))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	timeout := time.After(5 * time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-sepp.ConnectStatusCh():
+		case <-timeout:
+			t.Fatal("timeout waiting for giving up")
+		}
+	}
+	if err := sepp.Err(); !errors.Is(err, ErrReconnectFailed) {
+		t.Fatalf("expected ErrReconnectFailed, got %v", err)
+	}
+	if state := sepp.State(); state != ConnClosed {
+		t.Fatalf("expected closed, got %s", state)
+	}
+}
+
+func TestReconnectHandler(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil, WithMaxReconnects(3, 0))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	// the first attempt may fail before the handler is set.
+	attempts := make(chan ReconnectAttempt, 2)
+	sepp.SetReconnectHandler(func(a ReconnectAttempt) { attempts <- a })
+	select {
+	case a := <-attempts:
+		if a.Attempt < 2 || a.Delay != reconnectDelay || a.Err == nil {
+			t.Fatalf("unexpected attempt %+v", a)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for reconnect attempt")
+	}
+}
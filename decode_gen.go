@@ -0,0 +1,2310 @@
+// Code generated by gendecode. DO NOT EDIT.
+
+package gosepp
+
+import "encoding/json"
+
+var dimensionFields = []string{"w", "h", "x", "y"}
+
+func (v *Dimension) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "w":
+			l.readInt(&v.Width)
+		case "h":
+			l.readInt(&v.Height)
+		case "x":
+			l.readInt(&v.X)
+		case "y":
+			l.readInt(&v.Y)
+		default:
+			l.unknown(k, dimensionFields)
+		}
+	}
+}
+
+var mediaFields = []string{"mid", "playid"}
+
+func (v *Media) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "mid":
+			l.readString(&v.MediaID)
+		case "playid":
+			l.readString(&v.PlayID)
+		default:
+			l.unknown(k, mediaFields)
+		}
+	}
+}
+
+var memberFields = []string{"cid", "p"}
+
+func (v *Member) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "cid":
+			l.readString(&v.ClientID)
+		case "p":
+			if l.null() {
+				v.Platform = nil
+			} else {
+				if v.Platform == nil {
+					v.Platform = new(string)
+				}
+				l.readString(v.Platform)
+			}
+		default:
+			l.unknown(k, memberFields)
+		}
+	}
+}
+
+var msgAuthFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgAuth) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgAuthFields)
+		}
+	}
+}
+
+func (msg *MsgAuth) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgAuthDataFields = []string{"token"}
+
+func (v *MsgAuthData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "token":
+			l.readString(&v.Token)
+		default:
+			l.unknown(k, msgAuthDataFields)
+		}
+	}
+}
+
+var msgAuthExpiredFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgAuthExpired) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgAuthExpiredFields)
+		}
+	}
+}
+
+func (msg *MsgAuthExpired) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgAuthExpiredDataFields = []string{"reason"}
+
+func (v *MsgAuthExpiredData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "reason":
+			l.readString(&v.Reason)
+		default:
+			l.unknown(k, msgAuthExpiredDataFields)
+		}
+	}
+}
+
+var msgBaseFields = []string{"type", "msg_id", "from", "to"}
+
+func (v *MsgBase) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.Type)
+		case "msg_id":
+			l.readString(&v.MsgID)
+		case "from":
+			l.readString(&v.From)
+		case "to":
+			l.readString(&v.To)
+		default:
+			l.unknown(k, msgBaseFields)
+		}
+	}
+}
+
+var msgCallAcceptedFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallAccepted) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallAcceptedFields)
+		}
+	}
+}
+
+func (msg *MsgCallAccepted) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallAcceptedDataFields = []string{"call_id", "sdp"}
+
+func (v *MsgCallAcceptedData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "sdp":
+			v.Sdp.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallAcceptedDataFields)
+		}
+	}
+}
+
+var msgCallHoldFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallHold) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallHoldFields)
+		}
+	}
+}
+
+func (msg *MsgCallHold) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallHoldDataFields = []string{"call_id", "on"}
+
+func (v *MsgCallHoldData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "on":
+			l.readBool(&v.On)
+		default:
+			l.unknown(k, msgCallHoldDataFields)
+		}
+	}
+}
+
+var msgCallRedirectFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallRedirect) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallRedirectFields)
+		}
+	}
+}
+
+func (msg *MsgCallRedirect) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallRedirectDataFields = []string{"target"}
+
+func (v *MsgCallRedirectData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "target":
+			l.readString(&v.Target)
+		default:
+			l.unknown(k, msgCallRedirectDataFields)
+		}
+	}
+}
+
+var msgCallRejectedFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallRejected) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallRejectedFields)
+		}
+	}
+}
+
+func (msg *MsgCallRejected) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallRejectedDataFields = []string{"reject_code"}
+
+func (v *MsgCallRejectedData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "reject_code":
+			l.readInt(&v.RejectCode)
+		default:
+			l.unknown(k, msgCallRejectedDataFields)
+		}
+	}
+}
+
+var msgCallResumeFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallResume) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallResumeFields)
+		}
+	}
+}
+
+func (msg *MsgCallResume) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallResumeDataFields = []string{"sdp", "call_id"}
+
+func (v *MsgCallResumeData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "sdp":
+			v.Sdp.decodeJSON(l)
+		case "call_id":
+			l.readString(&v.CallID)
+		default:
+			l.unknown(k, msgCallResumeDataFields)
+		}
+	}
+}
+
+var msgCallResumedFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallResumed) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallResumedFields)
+		}
+	}
+}
+
+func (msg *MsgCallResumed) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallResumedDataFields = []string{"call_id", "sdp"}
+
+func (v *MsgCallResumedData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "sdp":
+			v.Sdp.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallResumedDataFields)
+		}
+	}
+}
+
+var msgCallStartFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallStart) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallStartFields)
+		}
+	}
+}
+
+func (msg *MsgCallStart) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallStartDataFields = []string{"sdp", "display_name", "mute_video", "platform", "avatar_url", "metadata", "platform_info"}
+
+func (v *MsgCallStartData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "sdp":
+			v.Sdp.decodeJSON(l)
+		case "display_name":
+			l.readString(&v.DisplayName)
+		case "mute_video":
+			l.readBool(&v.MuteVideo)
+		case "platform":
+			l.readString(&v.Platform)
+		case "avatar_url":
+			l.readString(&v.AvatarURL)
+		case "metadata":
+			if l.null() {
+				v.Metadata = nil
+			} else if l.beginObject() {
+				if v.Metadata == nil {
+					v.Metadata = make(map[string]string)
+				}
+				for first := true; l.next('}', &first); {
+					k0 := string(l.key())
+					var e0 string
+					l.readString(&e0)
+					if l.err == nil {
+						v.Metadata[k0] = e0
+					}
+				}
+			}
+		case "platform_info":
+			if l.null() {
+				v.PlatformInfo = nil
+			} else {
+				if v.PlatformInfo == nil {
+					v.PlatformInfo = new(PlatformInfo)
+				}
+				v.PlatformInfo.decodeJSON(l)
+			}
+		default:
+			l.unknown(k, msgCallStartDataFields)
+		}
+	}
+}
+
+var msgCallTerminateFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallTerminate) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallTerminateFields)
+		}
+	}
+}
+
+func (msg *MsgCallTerminate) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallTerminateDataFields = []string{"call_id", "term_code"}
+
+func (v *MsgCallTerminateData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "term_code":
+			l.readInt(&v.TermCode)
+		default:
+			l.unknown(k, msgCallTerminateDataFields)
+		}
+	}
+}
+
+var msgCallTerminatedFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallTerminated) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallTerminatedFields)
+		}
+	}
+}
+
+func (msg *MsgCallTerminated) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallTerminatedDataFields = []string{"call_id", "term_code"}
+
+func (v *MsgCallTerminatedData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "term_code":
+			l.readInt(&v.TermCode)
+		default:
+			l.unknown(k, msgCallTerminatedDataFields)
+		}
+	}
+}
+
+var msgCallTransferFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgCallTransfer) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgCallTransferFields)
+		}
+	}
+}
+
+func (msg *MsgCallTransfer) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgCallTransferDataFields = []string{"call_id", "target"}
+
+func (v *MsgCallTransferData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "target":
+			l.readString(&v.Target)
+		default:
+			l.unknown(k, msgCallTransferDataFields)
+		}
+	}
+}
+
+var msgChatFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgChat) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgChatFields)
+		}
+	}
+}
+
+func (msg *MsgChat) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgChatDataFields = []string{"call_id", "cid", "content", "id", "ts"}
+
+func (v *MsgChatData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "cid":
+			l.readString(&v.ClientID)
+		case "content":
+			l.readString(&v.Content)
+		case "id":
+			l.readString(&v.ID)
+		case "ts":
+			l.unmarshal(&v.Timestamp)
+		default:
+			l.unknown(k, msgChatDataFields)
+		}
+	}
+}
+
+var msgChatHistoryFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgChatHistory) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgChatHistoryFields)
+		}
+	}
+}
+
+func (msg *MsgChatHistory) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgChatHistoryDataFields = []string{"call_id", "ref_msg_id", "messages", "has_more"}
+
+func (v *MsgChatHistoryData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "ref_msg_id":
+			l.readString(&v.RefMsgID)
+		case "messages":
+			if l.null() {
+				v.Messages = nil
+			} else if l.beginArray() {
+				s0 := v.Messages[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]MsgChatData, 0, 4)
+					}
+					s0 = append(s0, MsgChatData{})
+					s0[len(s0)-1].decodeJSON(l)
+				}
+				if s0 == nil {
+					s0 = []MsgChatData{}
+				}
+				v.Messages = s0
+			}
+		case "has_more":
+			l.readBool(&v.HasMore)
+		default:
+			l.unknown(k, msgChatHistoryDataFields)
+		}
+	}
+}
+
+var msgChatHistoryRequestFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgChatHistoryRequest) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgChatHistoryRequestFields)
+		}
+	}
+}
+
+func (msg *MsgChatHistoryRequest) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgChatHistoryRequestDataFields = []string{"call_id", "before", "before_ts", "limit"}
+
+func (v *MsgChatHistoryRequestData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "before":
+			l.readString(&v.Before)
+		case "before_ts":
+			if l.null() {
+				v.BeforeTimestamp = nil
+			} else {
+				if v.BeforeTimestamp == nil {
+					v.BeforeTimestamp = new(Timestamp)
+				}
+				l.unmarshal(v.BeforeTimestamp)
+			}
+		case "limit":
+			l.readInt(&v.Limit)
+		default:
+			l.unknown(k, msgChatHistoryRequestDataFields)
+		}
+	}
+}
+
+var msgChatReceiptFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgChatReceipt) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgChatReceiptFields)
+		}
+	}
+}
+
+func (msg *MsgChatReceipt) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgChatReceiptDataFields = []string{"call_id", "id", "cid", "status"}
+
+func (v *MsgChatReceiptData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "id":
+			l.readString(&v.ID)
+		case "cid":
+			l.readString(&v.ClientID)
+		case "status":
+			l.readString(&v.Status)
+		default:
+			l.unknown(k, msgChatReceiptDataFields)
+		}
+	}
+}
+
+var msgDesktopstreamingFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgDesktopstreaming) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgDesktopstreamingFields)
+		}
+	}
+}
+
+func (msg *MsgDesktopstreaming) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgDesktopstreamingDataFields = []string{"call_id", "on", "cid"}
+
+func (v *MsgDesktopstreamingData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "on":
+			l.readBool(&v.On)
+		case "cid":
+			l.readString(&v.ClientID)
+		default:
+			l.unknown(k, msgDesktopstreamingDataFields)
+		}
+	}
+}
+
+var msgDisplayNameUpdateFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgDisplayNameUpdate) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgDisplayNameUpdateFields)
+		}
+	}
+}
+
+func (msg *MsgDisplayNameUpdate) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgDisplayNameUpdateDataFields = []string{"call_id", "display_name", "avatar_url"}
+
+func (v *MsgDisplayNameUpdateData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "display_name":
+			l.readString(&v.DisplayName)
+		case "avatar_url":
+			l.readString(&v.AvatarURL)
+		default:
+			l.unknown(k, msgDisplayNameUpdateDataFields)
+		}
+	}
+}
+
+var msgEchoFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgEcho) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgEchoFields)
+		}
+	}
+}
+
+func (msg *MsgEcho) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgEchoDataFields = []string{"id", "ts"}
+
+func (v *MsgEchoData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "id":
+			l.readString(&v.ID)
+		case "ts":
+			l.readInt64(&v.Timestamp)
+		default:
+			l.unknown(k, msgEchoDataFields)
+		}
+	}
+}
+
+var msgErrorFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgError) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgErrorFields)
+		}
+	}
+}
+
+func (msg *MsgError) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgErrorDataFields = []string{"code", "reason", "ref_msg_id"}
+
+func (v *MsgErrorData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "code":
+			l.readInt(&v.Code)
+		case "reason":
+			l.readString(&v.Reason)
+		case "ref_msg_id":
+			l.readString(&v.RefMsgID)
+		default:
+			l.unknown(k, msgErrorDataFields)
+		}
+	}
+}
+
+var msgFileAcceptFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgFileAccept) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgFileAcceptFields)
+		}
+	}
+}
+
+func (msg *MsgFileAccept) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgFileAcceptDataFields = []string{"file_id", "offset"}
+
+func (v *MsgFileAcceptData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "file_id":
+			l.readString(&v.FileID)
+		case "offset":
+			l.readInt64(&v.Offset)
+		default:
+			l.unknown(k, msgFileAcceptDataFields)
+		}
+	}
+}
+
+var msgFileChunkFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgFileChunk) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgFileChunkFields)
+		}
+	}
+}
+
+func (msg *MsgFileChunk) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgFileChunkDataFields = []string{"file_id", "offset", "data"}
+
+func (v *MsgFileChunkData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "file_id":
+			l.readString(&v.FileID)
+		case "offset":
+			l.readInt64(&v.Offset)
+		case "data":
+			l.readBytes(&v.Data)
+		default:
+			l.unknown(k, msgFileChunkDataFields)
+		}
+	}
+}
+
+var msgFileCompleteFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgFileComplete) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgFileCompleteFields)
+		}
+	}
+}
+
+func (msg *MsgFileComplete) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgFileCompleteDataFields = []string{"file_id", "sha256"}
+
+func (v *MsgFileCompleteData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "file_id":
+			l.readString(&v.FileID)
+		case "sha256":
+			l.readString(&v.SHA256)
+		default:
+			l.unknown(k, msgFileCompleteDataFields)
+		}
+	}
+}
+
+var msgFileOfferFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgFileOffer) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgFileOfferFields)
+		}
+	}
+}
+
+func (msg *MsgFileOffer) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgFileOfferDataFields = []string{"file_id", "name", "mime_type", "size", "sha256"}
+
+func (v *MsgFileOfferData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "file_id":
+			l.readString(&v.FileID)
+		case "name":
+			l.readString(&v.Name)
+		case "mime_type":
+			l.readString(&v.MimeType)
+		case "size":
+			l.readInt64(&v.Size)
+		case "sha256":
+			l.readString(&v.SHA256)
+		default:
+			l.unknown(k, msgFileOfferDataFields)
+		}
+	}
+}
+
+var msgForceDisconnectFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgForceDisconnect) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgForceDisconnectFields)
+		}
+	}
+}
+
+func (msg *MsgForceDisconnect) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgForceDisconnectDataFields = []string{"reason", "reconnect"}
+
+func (v *MsgForceDisconnectData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "reason":
+			l.readString(&v.Reason)
+		case "reconnect":
+			l.readBool(&v.Reconnect)
+		default:
+			l.unknown(k, msgForceDisconnectDataFields)
+		}
+	}
+}
+
+var msgFragmentFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgFragment) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgFragmentFields)
+		}
+	}
+}
+
+func (msg *MsgFragment) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgFragmentDataFields = []string{"id", "index", "count", "payload"}
+
+func (v *MsgFragmentData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "id":
+			l.readString(&v.ID)
+		case "index":
+			l.readInt(&v.Index)
+		case "count":
+			l.readInt(&v.Count)
+		case "payload":
+			l.readBytes(&v.Payload)
+		default:
+			l.unknown(k, msgFragmentDataFields)
+		}
+	}
+}
+
+var msgHelloFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgHello) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgHelloFields)
+		}
+	}
+}
+
+func (msg *MsgHello) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgHelloDataFields = []string{"protocol_version", "msg_types"}
+
+func (v *MsgHelloData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "protocol_version":
+			l.readInt(&v.ProtocolVersion)
+		case "msg_types":
+			if l.null() {
+				v.MsgTypes = nil
+			} else if l.beginArray() {
+				s0 := v.MsgTypes[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]string, 0, 4)
+					}
+					s0 = append(s0, "")
+					l.readString(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []string{}
+				}
+				v.MsgTypes = s0
+			}
+		default:
+			l.unknown(k, msgHelloDataFields)
+		}
+	}
+}
+
+var msgMemberlistFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgMemberlist) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgMemberlistFields)
+		}
+	}
+}
+
+func (msg *MsgMemberlist) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgMemberlistDataFields = []string{"call_id", "count", "add", "del", "media"}
+
+func (v *MsgMemberlistData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "count":
+			l.readInt(&v.Count)
+		case "add":
+			if l.null() {
+				v.Add = nil
+			} else if l.beginArray() {
+				s0 := v.Add[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]Member, 0, 4)
+					}
+					s0 = append(s0, Member{})
+					s0[len(s0)-1].decodeJSON(l)
+				}
+				if s0 == nil {
+					s0 = []Member{}
+				}
+				v.Add = s0
+			}
+		case "del":
+			if l.null() {
+				v.Del = nil
+			} else if l.beginArray() {
+				s0 := v.Del[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]string, 0, 4)
+					}
+					s0 = append(s0, "")
+					l.readString(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []string{}
+				}
+				v.Del = s0
+			}
+		case "media":
+			if l.null() {
+				v.Media = nil
+			} else if l.beginArray() {
+				s0 := v.Media[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]Media, 0, 4)
+					}
+					s0 = append(s0, Media{})
+					s0[len(s0)-1].decodeJSON(l)
+				}
+				if s0 == nil {
+					s0 = []Media{}
+				}
+				v.Media = s0
+			}
+		default:
+			l.unknown(k, msgMemberlistDataFields)
+		}
+	}
+}
+
+var msgMonitorFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgMonitor) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgMonitorFields)
+		}
+	}
+}
+
+func (msg *MsgMonitor) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgMonitorDataFields = []string{"events"}
+
+func (v *MsgMonitorData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "events":
+			if l.null() {
+				v.Events = nil
+			} else if l.beginArray() {
+				s0 := v.Events[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]string, 0, 4)
+					}
+					s0 = append(s0, "")
+					l.readString(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []string{}
+				}
+				v.Events = s0
+			}
+		default:
+			l.unknown(k, msgMonitorDataFields)
+		}
+	}
+}
+
+var msgMuteVideoFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgMuteVideo) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgMuteVideoFields)
+		}
+	}
+}
+
+func (msg *MsgMuteVideo) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgMuteVideoDataFields = []string{"call_id", "on", "cid"}
+
+func (v *MsgMuteVideoData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "on":
+			l.readBool(&v.On)
+		case "cid":
+			l.readString(&v.ClientID)
+		default:
+			l.unknown(k, msgMuteVideoDataFields)
+		}
+	}
+}
+
+var msgRecordingFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgRecording) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgRecordingFields)
+		}
+	}
+}
+
+func (msg *MsgRecording) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgRecordingDataFields = []string{"call_id", "active", "enabled"}
+
+func (v *MsgRecordingData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "active":
+			l.readBool(&v.Active)
+		case "enabled":
+			l.readBool(&v.Enabled)
+		default:
+			l.unknown(k, msgRecordingDataFields)
+		}
+	}
+}
+
+var msgResumeTokenFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgResumeToken) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgResumeTokenFields)
+		}
+	}
+}
+
+func (msg *MsgResumeToken) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgResumeTokenDataFields = []string{"token"}
+
+func (v *MsgResumeTokenData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "token":
+			l.readString(&v.Token)
+		default:
+			l.unknown(k, msgResumeTokenDataFields)
+		}
+	}
+}
+
+var msgSdpUpdateFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgSdpUpdate) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgSdpUpdateFields)
+		}
+	}
+}
+
+func (msg *MsgSdpUpdate) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgSdpUpdateDataFields = []string{"call_id", "sdp"}
+
+func (v *MsgSdpUpdateData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "sdp":
+			v.Sdp.decodeJSON(l)
+		default:
+			l.unknown(k, msgSdpUpdateDataFields)
+		}
+	}
+}
+
+var msgSetPresenterFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgSetPresenter) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgSetPresenterFields)
+		}
+	}
+}
+
+func (msg *MsgSetPresenter) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgSetPresenterDataFields = []string{"call_id", "on", "cid"}
+
+func (v *MsgSetPresenterData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "on":
+			l.readBool(&v.On)
+		case "cid":
+			l.readString(&v.ClientID)
+		default:
+			l.unknown(k, msgSetPresenterDataFields)
+		}
+	}
+}
+
+var msgSourceUpdateFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgSourceUpdate) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgSourceUpdateFields)
+		}
+	}
+}
+
+func (msg *MsgSourceUpdate) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgSourceUpdateDataFields = []string{"call_id", "asrc", "vsrc", "bcast", "dims", "l", "src", "tovl", "psrc", "dsrc"}
+
+func (v *MsgSourceUpdateData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "asrc":
+			if l.null() {
+				v.AudioSources = nil
+			} else if l.beginArray() {
+				s0 := v.AudioSources[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]int, 0, 4)
+					}
+					s0 = append(s0, 0)
+					l.readInt(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []int{}
+				}
+				v.AudioSources = s0
+			}
+		case "vsrc":
+			if l.null() {
+				v.VideoSources = nil
+			} else if l.beginArray() {
+				s0 := v.VideoSources[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]int, 0, 4)
+					}
+					s0 = append(s0, 0)
+					l.readInt(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []int{}
+				}
+				v.VideoSources = s0
+			}
+		case "bcast":
+			if l.null() {
+				v.Broadcast = nil
+			} else {
+				if v.Broadcast == nil {
+					v.Broadcast = new(bool)
+				}
+				l.readBool(v.Broadcast)
+			}
+		case "dims":
+			if l.null() {
+				v.Dimensions = nil
+			} else if l.beginArray() {
+				s0 := v.Dimensions[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]Dimension, 0, 4)
+					}
+					s0 = append(s0, Dimension{})
+					s0[len(s0)-1].decodeJSON(l)
+				}
+				if s0 == nil {
+					s0 = []Dimension{}
+				}
+				v.Dimensions = s0
+			}
+		case "l":
+			l.readInt(&v.Layout)
+		case "src":
+			if l.null() {
+				v.Sources = nil
+			} else if l.beginArray() {
+				s0 := v.Sources[:0]
+				for first := true; l.next(']', &first); {
+					if s0 == nil {
+						s0 = make([]string, 0, 4)
+					}
+					s0 = append(s0, "")
+					l.readString(&s0[len(s0)-1])
+				}
+				if s0 == nil {
+					s0 = []string{}
+				}
+				v.Sources = s0
+			}
+		case "tovl":
+			if l.null() {
+				v.TextOverlay = nil
+			} else {
+				if v.TextOverlay == nil {
+					v.TextOverlay = new(bool)
+				}
+				l.readBool(v.TextOverlay)
+			}
+		case "psrc":
+			if l.null() {
+				v.PresenterSrc = nil
+			} else {
+				if v.PresenterSrc == nil {
+					v.PresenterSrc = new(int)
+				}
+				l.readInt(v.PresenterSrc)
+			}
+		case "dsrc":
+			if l.null() {
+				v.DesktopstreamerSrc = nil
+			} else {
+				if v.DesktopstreamerSrc == nil {
+					v.DesktopstreamerSrc = new(int)
+				}
+				l.readInt(v.DesktopstreamerSrc)
+			}
+		default:
+			l.unknown(k, msgSourceUpdateDataFields)
+		}
+	}
+}
+
+var msgTypingFields = []string{"type", "msg_id", "from", "to", "data"}
+
+func (v *MsgTyping) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.MsgBase.Type)
+		case "msg_id":
+			l.readString(&v.MsgBase.MsgID)
+		case "from":
+			l.readString(&v.MsgBase.From)
+		case "to":
+			l.readString(&v.MsgBase.To)
+		case "data":
+			v.Data.decodeJSON(l)
+		default:
+			l.unknown(k, msgTypingFields)
+		}
+	}
+}
+
+func (msg *MsgTyping) unmarshalFast(data []byte) error {
+	c := *msg
+	l := jsonLexer{data: data}
+	c.decodeJSON(&l)
+	if l.end(); l.err != nil {
+		return json.Unmarshal(data, msg)
+	}
+	*msg = c
+	return nil
+}
+
+var msgTypingDataFields = []string{"call_id", "cid", "on"}
+
+func (v *MsgTypingData) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "call_id":
+			l.readString(&v.CallID)
+		case "cid":
+			l.readString(&v.ClientID)
+		case "on":
+			l.readBool(&v.On)
+		default:
+			l.unknown(k, msgTypingDataFields)
+		}
+	}
+}
+
+var platformInfoFields = []string{"name", "version", "os", "arch"}
+
+func (v *PlatformInfo) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "name":
+			l.readString(&v.Name)
+		case "version":
+			l.readString(&v.Version)
+		case "os":
+			l.readString(&v.OS)
+		case "arch":
+			l.readString(&v.Arch)
+		default:
+			l.unknown(k, platformInfoFields)
+		}
+	}
+}
+
+var sdpFields = []string{"type", "sdp"}
+
+func (v *Sdp) decodeJSON(l *jsonLexer) {
+	if l.null() || !l.beginObject() {
+		return
+	}
+	for first := true; l.next('}', &first); {
+		switch k := l.key(); string(k) {
+		case "type":
+			l.readString(&v.SdpType)
+		case "sdp":
+			l.readString(&v.Sdp)
+		default:
+			l.unknown(k, sdpFields)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// decodeBoth decodes data into msgType with the generated decoder and
+// with encoding/json, failing if they disagree.
+func decodeBoth(t *testing.T, msgType string, data []byte) {
+	t.Helper()
+	fast, want := SeppMsgTypes[msgType](), SeppMsgTypes[msgType]()
+	err := fast.(fastUnmarshaler).unmarshalFast(data)
+	wantErr := json.Unmarshal(data, want)
+	if (err == nil) != (wantErr == nil) || (err != nil && err.Error() != wantErr.Error()) {
+		t.Fatalf("%s: errors differ on %s: %v, encoding/json: %v", msgType, data, err, wantErr)
+	}
+	if !reflect.DeepEqual(fast, want) {
+		t.Fatalf("%s: decoded differently from encoding/json on %s:\n%#v\n%#v",
+			msgType, data, fast, want)
+	}
+}
+
+func TestGeneratedDecoders(t *testing.T) {
+	for msgType, factory := range SeppMsgTypes {
+		fixture, err := os.ReadFile(filepath.Join("testdata", "fixtures", msgType+".json"))
+		if err != nil {
+			t.Fatalf("%s: missing fixture [%s]", msgType, err)
+		}
+		decodeBoth(t, msgType, fixture)
+
+		// the fixtures must not need encoding/json.
+		msg, ok := factory().(interface{ decodeJSON(*jsonLexer) })
+		if !ok {
+			t.Fatalf("%s: no generated decoder", msgType)
+		}
+		l := jsonLexer{data: fixture}
+		msg.decodeJSON(&l)
+		if l.end(); l.err != nil {
+			t.Errorf("%s: fixture not decoded by the generated decoder", msgType)
+		}
+	}
+}
+
+func TestGeneratedDecodersEdgeCases(t *testing.T) {
+	tests := []struct {
+		msgType string
+		data    string
+	}{
+		{MsgTypeChat, `{"type":"chat","data":{"content":"a\"\\\/\b\f\n\r\t\u00e4\ud83d\ude00 ü"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"content":"\ud83d"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"content":"\ud83dx\u0041"}}`},
+		{MsgTypeChat, "{\"type\":\"chat\",\"data\":{\"content\":\"\xff\"}}"},
+		{MsgTypeChat, "{\"type\":\"chat\",\"data\":{\"content\":\"a\nb\"}}"},
+		{MsgTypeChat, `{"type":"chat","data":{"content":"\x"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"content":"a","content":null}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"Content":"a"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"content":"a"},"data":{"id":"b"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"ts":"2020-01-01T00:00:00Z"}}`},
+		{MsgTypeChat, `{"type":"chat","data":{"ts":1}}`},
+		{MsgTypeChat, `{"type":"chat","data":null}`},
+		{MsgTypeChat, `{"type":"chat","data":[]}`},
+		{MsgTypeChat, `{"type":"chat","data":{"content":1}}`},
+		{MsgTypeChat, `{"type":"chat","x":[1,{"a":[true,false,null,-0.5e+3]}],"data":{}}`},
+		{MsgTypeChat, `{"type":"chat","x":[1,,2]}`},
+		{MsgTypeChat, `{"type":"chat","x":01}`},
+		{MsgTypeChat, `{"type":"chat","x":1.}`},
+		{MsgTypeChat, `{"type":"chat","x":tru}`},
+		{MsgTypeChat, `{"type":"chat",}`},
+		{MsgTypeChat, `{"type":"chat"} x`},
+		{MsgTypeChat, `{"type":"chat"}` + " \n"},
+		{MsgTypeChat, `{"type":"chat"`},
+		{MsgTypeChat, `{"type":"chat","ü":1}`},
+		{MsgTypeChat, `{"typ\u0065":"chat"}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":3,"add":[{"cid":"a","p":null},{"cid":"b","p":"web"}],"del":[]}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"add":null,"del":["a",null]}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":1.5}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":1e2}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":99999999999999999999}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":-7}}`},
+		{MsgTypeMemberlist, `{"type":"memberlist","data":{"count":"1"}}`},
+		{MsgTypeCallStart, `{"type":"call_start","data":{"metadata":{"a":"1","b":null},"metadata":{"c":"2"}}}`},
+		{MsgTypeCallStart, `{"type":"call_start","data":{"metadata":null,"mute_video":null}}`},
+		{MsgTypeCallStart, `{"type":"call_start","data":{"platform_info":{"os":"linux"},"platform_info":{"arch":"arm"}}}`},
+		{MsgTypeCallStart, `{"type":"call_start","data":{"mute_video":"true"}}`},
+		{MsgTypeFileChunk, `{"type":"file_chunk","data":{"data":"aGVsbG8="}}`},
+		{MsgTypeFileChunk, `{"type":"file_chunk","data":{"data":""}}`},
+		{MsgTypeFileChunk, `{"type":"file_chunk","data":{"data":"!!"}}`},
+		{MsgTypeFileChunk, `{"type":"file_chunk","data":{"data":[1,2]}}`},
+	}
+	for _, tt := range tests {
+		decodeBoth(t, tt.msgType, []byte(tt.data))
+	}
+}
+
+func FuzzGeneratedDecoders(f *testing.F) {
+	f.Add([]byte(`{"type":"memberlist","data":{"count":3,"add":[{"cid":"a"}],"del":["c"]}}`))
+	f.Add([]byte(`{"type":"call_start","data":{"sdp":{"type":"offer","sdp":"v=0\r\n"},"metadata":{"a":"b"}}}`))
+	f.Add([]byte(`{"type":"chat","data":{"content":"\u00e4","ts":"2020-01-01T00:00:00Z"}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, msgType := range []string{MsgTypeMemberlist, MsgTypeCallStart, MsgTypeChat} {
+			decodeBoth(t, msgType, data)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package gosepp
+
+import (
+	"context"
+	"net"
+)
+
+// NetDialContextFunc creates the network connection the websocket is
+// established on.
+type NetDialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithNetDialContext sets the function used to create the network
+// connection, e.g. to dial through a userspace overlay network.
+func WithNetDialContext(dial NetDialContextFunc) GoSeppOption {
+	return func(rtm *GoSepp) {
+		if rtm.wsDialer != nil {
+			rtm.wsDialer.NetDialContext = dial
+		}
+	}
+}
+
+// WithNetDialer dials with d, which allows to set a custom resolver, TCP
+// keepalive, or per-connection socket options via d.Control.
+func WithNetDialer(d *net.Dialer) GoSeppOption {
+	return WithNetDialContext(d.DialContext)
+}
+
+// WithResolver resolves the endpoint host names with r.
+func WithResolver(r *net.Resolver) GoSeppOption {
+	return WithNetDialer(&net.Dialer{Resolver: r})
+}
@@ -0,0 +1,131 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var echoSeq uint64
+
+// Echo sends an echo message and waits until the server reflected it.
+// The returned round-trip time includes the processing by the server,
+// unlike transport level pings.
+func (rtm *GoSepp) Echo(ctx context.Context) (time.Duration, error) {
+	id := strconv.FormatUint(atomic.AddUint64(&echoSeq, 1), 10)
+	ch, cancel := rtm.Subscribe(func(msg MsgInterface) bool {
+		m, ok := msg.(*MsgEcho)
+		return ok && m.Data.ID == id
+	})
+	defer cancel()
+
+	sent := time.Now()
+	if err := rtm.SendMsg(MsgEcho{
+		MsgBase: MsgBase{Type: MsgTypeEcho},
+		Data:    MsgEchoData{ID: id, Timestamp: sent.UnixNano()},
+	}); err != nil {
+		return 0, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	select {
+	case _, ok := <-ch:
+		if !ok {
+			return 0, ErrConnectionClosed
+		}
+		rtt := time.Since(sent)
+		rtm.qos.roundTrip(rtt)
+		return rtt, nil
+	case <-ctx.Done():
+		return 0, ctxError(ctx, "wait for echo")
+	}
+}
+
+// EchoStats summarizes the results of an EchoProber.
+type EchoStats struct {
+	Sent     int
+	Received int
+	LastRTT  time.Duration
+	AvgRTT   time.Duration
+}
+
+// Loss returns the ratio of echos which were not reflected in time.
+func (s EchoStats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Sent-s.Received) / float64(s.Sent)
+}
+
+// EchoProber periodically measures the end-to-end signaling latency
+// and loss via echo messages.
+type EchoProber struct {
+	sepp     *GoSepp
+	interval time.Duration
+	timeout  time.Duration
+	onResult func(EchoStats)
+
+	mu      sync.Mutex
+	stats   EchoStats
+	totalRT time.Duration
+}
+
+// NewEchoProber returns a prober sending an echo every interval. An echo
+// not reflected within timeout is counted as lost. onResult may be nil,
+// else it is called with the updated stats after every probe.
+func NewEchoProber(sepp *GoSepp, interval, timeout time.Duration,
+	onResult func(EchoStats)) *EchoProber {
+	return &EchoProber{
+		sepp:     sepp,
+		interval: interval,
+		timeout:  timeout,
+		onResult: onResult,
+	}
+}
+
+// Run probes until ctx is done.
+func (p *EchoProber) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.probe(ctx)
+		}
+	}
+}
+
+func (p *EchoProber) probe(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	rtt, err := p.sepp.Echo(probeCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.stats.Sent++
+	if err == nil {
+		p.stats.Received++
+		p.stats.LastRTT = rtt
+		p.totalRT += rtt
+		p.stats.AvgRTT = p.totalRT / time.Duration(p.stats.Received)
+	}
+	stats := p.stats
+	p.mu.Unlock()
+
+	if p.onResult != nil {
+		p.onResult(stats)
+	}
+}
+
+// Stats returns the results so far.
+func (p *EchoProber) Stats() EchoStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
@@ -0,0 +1,47 @@
+package gosepp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEchoProber(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for {
+			m := c.read()
+			if m == nil {
+				return
+			}
+			if echo, ok := m.(*MsgEcho); ok {
+				c.write(echo)
+			}
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	results := make(chan EchoStats, 10)
+	prober := NewEchoProber(sepp, 10*time.Millisecond, time.Second,
+		func(stats EchoStats) { results <- stats })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go prober.Run(ctx)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case stats := <-results:
+			if stats.Loss() != 0 || stats.LastRTT <= 0 {
+				t.Fatalf("unexpected stats %+v", stats)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for probe")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package gosepp
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointResolver returns the endpoint urls to connect to, in order of
+// preference. It is called before every connect attempt.
+type EndpointResolver func(ctx context.Context) ([]string, error)
+
+// EndpointStatus is the health of a signaling endpoint.
+type EndpointStatus struct {
+	URL         string
+	Healthy     bool
+	Failures    int
+	LastFailure time.Time
+}
+
+// WithEndpoints adds fallback endpoints. If connecting to the primary
+// endpoint passed to NewGoSepp fails, the fallbacks are tried in order.
+func WithEndpoints(urls ...string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.fallbackURLs = append(rtm.fallbackURLs, urls...)
+	}
+}
+
+// WithEndpointResolver sets a resolver returning the endpoints to use.
+// If set, the endpoints passed to NewGoSepp and WithEndpoints are only
+// used until the resolver succeeded once.
+func WithEndpointResolver(resolver EndpointResolver) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.endpoints.resolver = resolver
+	}
+}
+
+type endpoint struct {
+	url         *url.URL
+	failures    int
+	lastFailure time.Time
+}
+
+// endpointSet tracks the health of the endpoints and picks the order to
+// try them in. Healthy endpoints come first, in order of preference, so
+// the primary is used again as soon as it recovers.
+type endpointSet struct {
+	resolver EndpointResolver
+	// retryAfter is the time an endpoint is considered unhealthy
+	// after a failure.
+	retryAfter time.Duration
+	logger     Logger
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+func (s *endpointSet) set(urls []string) error {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		endpoints = append(endpoints, &endpoint{url: u})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// keep the health of known endpoints
+	for _, e := range endpoints {
+		for _, known := range s.endpoints {
+			if known.url.String() == e.url.String() {
+				*e = *known
+			}
+		}
+	}
+	s.endpoints = endpoints
+	return nil
+}
+
+func (e *endpoint) healthy(retryAfter time.Duration) bool {
+	return e.failures == 0 || time.Since(e.lastFailure) >= retryAfter
+}
+
+// candidates returns the endpoints to try for the next connect.
+func (s *endpointSet) candidates(ctx context.Context) []*url.URL {
+	if s.resolver != nil {
+		urls, err := s.resolver(ctx)
+		if err == nil && len(urls) > 0 {
+			err = s.set(urls)
+		}
+		if err != nil {
+			s.logger.Warn("Failed to resolve endpoints [%s]. Using known ones.", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ordered := make([]*endpoint, len(s.endpoints))
+	copy(ordered, s.endpoints)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		hi := ordered[i].healthy(s.retryAfter)
+		hj := ordered[j].healthy(s.retryAfter)
+		if hi != hj {
+			return hi
+		}
+		if !hi {
+			// try the endpoint failing longest ago first
+			return ordered[i].lastFailure.Before(ordered[j].lastFailure)
+		}
+		return false
+	})
+	urls := make([]*url.URL, len(ordered))
+	for i, e := range ordered {
+		urls[i] = e.url
+	}
+	return urls
+}
+
+// report records the result of a connect to u.
+func (s *endpointSet) report(u *url.URL, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.endpoints {
+		if e.url == u {
+			if err != nil {
+				e.failures++
+				e.lastFailure = time.Now()
+			} else {
+				e.failures = 0
+			}
+		}
+	}
+}
+
+func (s *endpointSet) status() []EndpointStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := make([]EndpointStatus, len(s.endpoints))
+	for i, e := range s.endpoints {
+		status[i] = EndpointStatus{
+			URL:         e.url.String(),
+			Healthy:     e.healthy(s.retryAfter),
+			Failures:    e.failures,
+			LastFailure: e.lastFailure,
+		}
+	}
+	return status
+}
+
+// Endpoint returns the url of the endpoint currently connected to, or
+// last tried.
+func (rtm *GoSepp) Endpoint() string {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	if rtm.wsURL == nil {
+		return ""
+	}
+	return rtm.wsURL.String()
+}
+
+// EndpointStatus returns the health of all known endpoints.
+func (rtm *GoSepp) EndpointStatus() []EndpointStatus {
+	return rtm.endpoints.status()
+}
@@ -0,0 +1,60 @@
+package gosepp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGoSeppFailsOverToFallback(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	// nothing listens on port 1 of localhost
+	sepp, err := NewGoSepp("ws://127.0.0.1:1/call", "", nil, nil,
+		WithEndpoints(srv.URL()))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	select {
+	case connected := <-sepp.ConnectStatusCh():
+		if !connected {
+			t.Fatalf("failed to connect")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout")
+	}
+	if sepp.Endpoint() != srv.URL() {
+		t.Fatalf("connected to %s", sepp.Endpoint())
+	}
+	status := sepp.EndpointStatus()
+	if len(status) != 2 || status[0].Healthy || !status[1].Healthy {
+		t.Fatalf("unexpected endpoint status %+v", status)
+	}
+}
+
+func TestGoSeppNetDialContext(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	dialed := make(chan string, 1)
+	sepp, err := NewGoSepp("ws://sepp.invalid/call", "", nil, nil,
+		WithNetDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed <- addr
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		}))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	if addr := <-dialed; addr != "sepp.invalid:80" {
+		t.Fatalf("dialed %s", addr)
+	}
+}
@@ -0,0 +1,52 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EnvelopeVersion is the schema version of Envelope.
+const EnvelopeVersion = 1
+
+// Direction tells whether a message was sent or received.
+type Direction string
+
+// Message directions
+const (
+	DirectionIn  Direction = "in"
+	DirectionOut Direction = "out"
+)
+
+// Envelope wraps a message for external sinks like Kafka. The version
+// allows consumers to evolve independently of the message set.
+type Envelope struct {
+	Version   int             `json:"v"`
+	Direction Direction       `json:"dir,omitempty"`
+	Type      string          `json:"type"`
+	CallID    string          `json:"call_id,omitempty"`
+	Timestamp time.Time       `json:"ts"`
+	Msg       json.RawMessage `json:"msg"`
+	// Raw holds frames which aren't valid json instead of Msg, base64
+	// encoded.
+	Raw []byte `json:"raw,omitempty"`
+}
+
+// NewEnvelope wraps msg with the current time.
+func NewEnvelope(msg MsgInterface) (*Envelope, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Version:   EnvelopeVersion,
+		Type:      msg.GetType(),
+		CallID:    string(CallIDOf(msg)),
+		Timestamp: time.Now().UTC(),
+		Msg:       data,
+	}, nil
+}
+
+// Decode returns the wrapped message.
+func (e *Envelope) Decode() (MsgInterface, error) {
+	return DecodeMsg(e.Msg)
+}
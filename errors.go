@@ -0,0 +1,146 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the public API. Use errors.Is to test for
+// them, as they are usually wrapped with additional context.
+var (
+	// ErrNotRunning is returned when sending on a stopped GoSepp.
+	ErrNotRunning = errors.New("not running")
+	// ErrTimeout is returned if an operation did not complete in time.
+	ErrTimeout = errors.New("timeout")
+	// ErrConnectFailed is returned if the signaling connection could
+	// not be established.
+	ErrConnectFailed = errors.New("failed to connect")
+	// ErrConnectionClosed is returned if the signaling connection went
+	// down while waiting for a message.
+	ErrConnectionClosed = errors.New("connection closed")
+	// ErrCircuitOpen is returned when sending while the circuit breaker
+	// considers the signaling endpoint unavailable.
+	ErrCircuitOpen = errors.New("signaling unavailable")
+	// ErrNoActiveCall is returned by call operations which require
+	// a started call.
+	ErrNoActiveCall = errors.New("no active call")
+	// ErrCallInProgress is returned when starting a call while another
+	// one is still active.
+	ErrCallInProgress = errors.New("call already in progress")
+	// ErrUnsupportedMsgType is returned when decoding a message of a
+	// type not registered in SeppMsgTypes.
+	ErrUnsupportedMsgType = errors.New("message-type not supported")
+	// ErrNotConnected is returned by SendMsgCtx if there is currently no
+	// signaling connection.
+	ErrNotConnected = errors.New("not connected")
+	// ErrQueueFull is returned by SendMsgCtx if the send queue stayed
+	// full until its context was done.
+	ErrQueueFull = errors.New("send queue full")
+	// ErrMessageTooLarge is returned when sending a message exceeding
+	// the limit set by WithMaxMessageSize.
+	ErrMessageTooLarge = errors.New("message too large")
+	// ErrInvalidCACert is returned if a CA-file could not be appended
+	// to the cert-pool.
+	ErrInvalidCACert = errors.New("failed to append CAcert")
+	// ErrInsecureTLS is returned if the strict TLS profile is violated,
+	// e.g. by skipping certificate verification.
+	ErrInsecureTLS = errors.New("insecure TLS configuration")
+	// ErrReconnectFailed is returned by Err once the limits set by
+	// WithMaxReconnects are exceeded.
+	ErrReconnectFailed = errors.New("giving up reconnecting")
+	// ErrNotMigratable is returned by Migrate for calls sharing the
+	// connection of a ConnPool.
+	ErrNotMigratable = errors.New("call can't be migrated")
+	// ErrServerGoingAway is unwrapped from a CloseError if the server
+	// shuts down or restarts.
+	ErrServerGoingAway = errors.New("server going away")
+	// ErrPolicyViolation is unwrapped from a CloseError if the server
+	// closed the connection for a policy violation.
+	ErrPolicyViolation = errors.New("policy violation")
+	// ErrTryAgainLater is unwrapped from a CloseError if the server is
+	// overloaded.
+	ErrTryAgainLater = errors.New("try again later")
+	// ErrAuthExpired is returned by Err if the server reported an
+	// expired auth token which couldn't be refreshed.
+	ErrAuthExpired = errors.New("auth token expired")
+	// ErrForceDisconnected is returned by Err if the server disconnected
+	// the client for good.
+	ErrForceDisconnected = errors.New("disconnected by server")
+	// ErrDispatcherStuck is reported to the error handler of a call if
+	// its dispatcher stopped consuming, see WithDispatchWatchdog.
+	ErrDispatcherStuck = errors.New("dispatcher stuck")
+)
+
+// CallRejectedError is returned if the remote end rejected the call.
+type CallRejectedError struct {
+	RejectCode int
+}
+
+func (e *CallRejectedError) Error() string {
+	return fmt.Sprintf("call rejected: %d", e.RejectCode)
+}
+
+// CallRedirectedError is returned if the remote end redirected the call
+// to another conference instead of accepting it.
+type CallRedirectedError struct {
+	Target string
+}
+
+func (e *CallRedirectedError) Error() string {
+	return fmt.Sprintf("call redirected to %s", e.Target)
+}
+
+// ServerError is returned if the server answered a request with an
+// error message.
+type ServerError struct {
+	Code     int
+	Reason   string
+	RefMsgID string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error %d: %s", e.Code, e.Reason)
+}
+
+func serverError(m *MsgError) error {
+	return &ServerError{Code: m.Data.Code, Reason: m.Data.Reason,
+		RefMsgID: m.Data.RefMsgID}
+}
+
+// ProtocolError is returned if a message was received which is not
+// expected in the current state.
+type ProtocolError struct {
+	MsgType string
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("protocol error. msg-type: %s", e.MsgType)
+}
+
+// ContextError is returned if an operation was aborted because its context
+// is done. It wraps the context error, so errors.Is(err, context.Canceled)
+// tells an explicit cancel from an expired deadline. An expired deadline
+// additionally matches ErrTimeout.
+type ContextError struct {
+	Op  string
+	Err error
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying context error.
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrTimeout for expired deadlines.
+func (e *ContextError) Is(target error) bool {
+	return target == ErrTimeout && e.Err == context.DeadlineExceeded
+}
+
+func ctxError(ctx context.Context, op string) error {
+	return &ContextError{Op: op, Err: ctx.Err()}
+}
@@ -0,0 +1,164 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	sentinels := []error{ErrNotRunning, ErrTimeout, ErrConnectFailed,
+		ErrConnectionClosed, ErrNoActiveCall, ErrCallInProgress,
+		ErrInvalidCACert}
+	for _, sentinel := range sentinels {
+		err := fmt.Errorf("failed to send message: %w", sentinel)
+		if !errors.Is(err, sentinel) {
+			t.Errorf("wrapped %q doesn't match", sentinel)
+		}
+		for _, other := range sentinels {
+			if other != sentinel && errors.Is(err, other) {
+				t.Errorf("wrapped %q matches %q", sentinel, other)
+			}
+		}
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		err   error
+		check func(error) bool
+	}{
+		{&CallRejectedError{RejectCode: 486}, func(err error) bool {
+			var target *CallRejectedError
+			return errors.As(err, &target) && target.RejectCode == 486
+		}},
+		{&CallRedirectedError{Target: "other"}, func(err error) bool {
+			var target *CallRedirectedError
+			return errors.As(err, &target) && target.Target == "other"
+		}},
+		{&ServerError{Code: 500, Reason: "oops"}, func(err error) bool {
+			var target *ServerError
+			return errors.As(err, &target) && target.Code == 500
+		}},
+		{&ProtocolError{MsgType: "chat"}, func(err error) bool {
+			var target *ProtocolError
+			return errors.As(err, &target) && target.MsgType == "chat"
+		}},
+	}
+	for _, tt := range tests {
+		if !tt.check(fmt.Errorf("start: %w", tt.err)) {
+			t.Errorf("errors.As failed for wrapped %T", tt.err)
+		}
+		if tt.check(errors.New(tt.err.Error())) {
+			t.Errorf("errors.As matched plain error for %T", tt.err)
+		}
+	}
+}
+
+func TestStartSessionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply func(start *MsgCallStart) MsgInterface
+		check func(error) bool
+	}{
+		{"rejected", func(start *MsgCallStart) MsgInterface {
+			return &MsgCallRejected{
+				MsgBase: MsgBase{Type: MsgTypeCallRejected, From: start.To, To: start.From},
+				Data:    MsgCallRejectedData{RejectCode: 486},
+			}
+		}, func(err error) bool {
+			var target *CallRejectedError
+			return errors.As(err, &target) && target.RejectCode == 486
+		}},
+		{"unexpected", func(start *MsgCallStart) MsgInterface {
+			return NewChatMsg("conf", "too early")
+		}, func(err error) bool {
+			var target *ProtocolError
+			return errors.As(err, &target) && target.MsgType == MsgTypeChat
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeServer(t, func(c *fakeConn) {
+				start, ok := c.read().(*MsgCallStart)
+				if !ok {
+					return
+				}
+				c.write(tt.reply(start))
+				c.read()
+			})
+			defer srv.Close()
+
+			call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+				ConfID: "conf"}, nil)
+			if err != nil {
+				t.Fatalf("failed: %s", err)
+			}
+			defer call.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := call.StartSession(ctx, Sdp{}, "bot"); !tt.check(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestContextError(t *testing.T) {
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now())
+	defer cancelExpired()
+	<-expired.Done()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		cause    error
+		deadline bool
+	}{
+		{"deadline", expired, context.DeadlineExceeded, true},
+		{"canceled", canceled, context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("start: %w", ctxError(tt.ctx, "wait for accept"))
+			var target *ContextError
+			if !errors.As(err, &target) || target.Op != "wait for accept" {
+				t.Fatalf("errors.As failed for %v", err)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Fatalf("expected %v to match %v", err, tt.cause)
+			}
+			if errors.Is(err, ErrTimeout) != tt.deadline {
+				t.Fatalf("ErrTimeout match of %v, expected %t", err, tt.deadline)
+			}
+		})
+	}
+}
+
+func TestStartSessionContextError(t *testing.T) {
+	// the server never answers.
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for c.read() != nil {
+		}
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = call.StartSession(ctx, Sdp{}, "bot")
+	var target *ContextError
+	if !errors.As(err, &target) || !errors.Is(err, ErrTimeout) ||
+		!errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
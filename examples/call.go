@@ -18,8 +18,8 @@ func main() {
 	ci := &gosepp.CallInfo{
 		SigEndpoint: "wss://sig.eyeson.com/call",
 		AuthToken:   *authTokenFlag,
-		ClientID:    *clientIDFlag,
-		ConfID:      *confIDFlag,
+		ClientID:    gosepp.ClientID(*clientIDFlag),
+		ConfID:      gosepp.ConfID(*confIDFlag),
 	}
 
 	call, err := gosepp.NewCall(ci, nil)
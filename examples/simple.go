@@ -28,16 +28,9 @@ func main() {
 		log.Fatalf("Failed to connect")
 	}
 
-	if err := sepp.SendMsg(gosepp.MsgCallStart{
-		MsgBase: gosepp.MsgBase{
-			Type: gosepp.MsgTypeCallStart,
-			From: clientID,
-			To:   confID,
-		},
-		Data: gosepp.MsgCallStartData{
-			Sdp:         gosepp.Sdp{SdpType: "offer", Sdp: sdp},
-			DisplayName: clientID},
-	}); err != nil {
+	if err := sepp.SendMsg(gosepp.NewCallStart(gosepp.ClientID(clientID),
+		gosepp.ConfID(confID), gosepp.Sdp{SdpType: "offer", Sdp: sdp},
+		clientID)); err != nil {
 		log.Fatalf("failed to send message:", err)
 	}
 
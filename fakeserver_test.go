@@ -0,0 +1,128 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeServer is a minimal sepp endpoint used by the tests.
+type fakeServer struct {
+	*httptest.Server
+	t *testing.T
+}
+
+// newFakeServer starts a websocket server which calls handler
+// for every accepted connection.
+func newFakeServer(t *testing.T, handler func(*fakeConn)) *fakeServer {
+	upgrader := websocket.Upgrader{}
+	s := &fakeServer{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("upgrade failed: %s", err)
+				return
+			}
+			defer c.Close()
+			handler(&fakeConn{Conn: c, t: t, header: r.Header,
+				query: r.URL.Query()})
+		}))
+	return s
+}
+
+// URL returns the websocket url of the server.
+func (s *fakeServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http")
+}
+
+type fakeConn struct {
+	*websocket.Conn
+	t *testing.T
+	// header holds the headers of the upgrade request.
+	header http.Header
+	// query holds the query parameters of the upgrade request.
+	query url.Values
+}
+
+// read returns the next decoded message or nil if the
+// connection is gone.
+func (c *fakeConn) read() MsgInterface {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return nil
+	}
+	msg, err := DecodeMsg(data)
+	if err != nil {
+		c.t.Errorf("invalid message: %s", err)
+		return nil
+	}
+	return msg
+}
+
+func (c *fakeConn) write(msg interface{}) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("marshal failed: %s", err)
+	}
+	if err := c.WriteMessage(websocket.TextMessage, b); err != nil {
+		c.t.Logf("write failed: %s", err)
+	}
+}
+
+// acceptCalls answers every call_start with call_accepted and every
+// call_terminate with call_terminated.
+func acceptCalls(c *fakeConn) {
+	n := 0
+	for {
+		switch m := c.read().(type) {
+		case nil:
+			return
+		case *MsgCallStart:
+			n++
+			c.write(MsgCallAccepted{
+				MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: m.To, To: m.From},
+				Data: MsgCallAcceptedData{
+					CallID: strings.Repeat("c", n),
+					Sdp:    Sdp{SdpType: "answer", Sdp: "answer-sdp"}},
+			})
+		case *MsgCallTerminate:
+			c.write(MsgCallTerminated{
+				MsgBase: MsgBase{Type: MsgTypeCallTerminated, From: m.To, To: m.From},
+				Data:    MsgCallTerminatedData{CallID: m.Data.CallID},
+			})
+		}
+	}
+}
+
+// relay forwards every frame received on one connection to all other
+// connections of the server.
+type relay struct {
+	mu    sync.Mutex
+	conns []*fakeConn
+}
+
+func (r *relay) handle(c *fakeConn) {
+	r.mu.Lock()
+	r.conns = append(r.conns, c)
+	r.mu.Unlock()
+	for {
+		mt, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		for _, other := range r.conns {
+			if other != c {
+				other.WriteMessage(mt, data)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
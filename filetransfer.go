@@ -0,0 +1,303 @@
+package gosepp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrChecksumMismatch is returned if a received file does not match the
+// checksum of its offer.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrFileTooLarge is returned if a received file exceeds the size of its
+// offer.
+var ErrFileTooLarge = errors.New("file exceeds offered size")
+
+const (
+	// defaultMaxFileSize limits the size of accepted offers.
+	defaultMaxFileSize = 16 << 20
+	// incomingFileBuffer is the capacity of IncomingCh.
+	incomingFileBuffer = 16
+)
+
+// FileTransfer moves small files, e.g. images or PDFs shared in chat,
+// over the signaling connection. A file is offered with file_offer, and
+// once the receiver answered with file_accept, sent as file_chunk messages
+// followed by file_complete.
+//
+// Interrupted transfers are resumed by offering the same file-id again:
+// the receiver accepts at the offset it already received.
+type FileTransfer struct {
+	sepp      *GoSepp
+	from      string
+	to        string
+	chunkSize int
+	// maxSize limits the size of accepted offers.
+	maxSize  int64
+	logger   Logger
+	incoming chan *IncomingFile
+
+	mu    sync.Mutex
+	files map[string]*IncomingFile
+}
+
+// FileTransferOption defines the options interface of the FileTransfer.
+type FileTransferOption func(*FileTransfer)
+
+// WithFileChunkSize sets the payload size of a single chunk. Defaults to
+// 16KiB.
+func WithFileChunkSize(size int) FileTransferOption {
+	return func(ft *FileTransfer) {
+		ft.chunkSize = size
+	}
+}
+
+// WithMaxFileSize sets the largest file size offers are accepted for.
+// Larger offers are ignored. Defaults to 16MiB.
+func WithMaxFileSize(size int64) FileTransferOption {
+	return func(ft *FileTransfer) {
+		ft.maxSize = size
+	}
+}
+
+// NewFileTransfer returns a FileTransfer sending with the from and to
+// headers, usually the client-id and conf-id.
+func NewFileTransfer(sepp *GoSepp, from, to string, logger Logger,
+	options ...FileTransferOption) *FileTransfer {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	ft := &FileTransfer{
+		sepp:      sepp,
+		from:      from,
+		to:        to,
+		chunkSize: 16 * 1024,
+		maxSize:   defaultMaxFileSize,
+		logger:    logger,
+		incoming:  make(chan *IncomingFile, incomingFileBuffer),
+		files:     make(map[string]*IncomingFile),
+	}
+	for _, opt := range options {
+		opt(ft)
+	}
+	return ft
+}
+
+// NewFileID returns a random file-id.
+func NewFileID() (string, error) {
+	id, err := newCallID()
+	return string(id), err
+}
+
+func (ft *FileTransfer) base(msgType string) MsgBase {
+	return MsgBase{Type: msgType, From: ft.from, To: ft.to}
+}
+
+// Send offers data as file fileID and transfers it once accepted by the
+// receiver. Calling Send again with the fileID of an interrupted
+// transfer resumes it.
+func (ft *FileTransfer) Send(ctx context.Context, fileID, name,
+	mimeType string, data []byte) error {
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	reply, err := ft.sepp.sendAndWaitFor(ctx, MsgFileOffer{
+		MsgBase: ft.base(MsgTypeFileOffer),
+		Data: MsgFileOfferData{
+			FileID:   fileID,
+			Name:     name,
+			MimeType: mimeType,
+			Size:     int64(len(data)),
+			SHA256:   checksum},
+	}, func(msg MsgInterface) bool {
+		m, ok := msg.(*MsgFileAccept)
+		return ok && m.Data.FileID == fileID
+	})
+	if err != nil {
+		return err
+	}
+
+	offset := reply.(*MsgFileAccept).Data.Offset
+	if offset < 0 || offset > int64(len(data)) {
+		offset = 0
+	}
+	for offset < int64(len(data)) {
+		if ctx.Err() != nil {
+			return ctxError(ctx, "send file")
+		}
+		end := offset + int64(ft.chunkSize)
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		if err := ft.sepp.SendMsg(MsgFileChunk{
+			MsgBase: ft.base(MsgTypeFileChunk),
+			Data: MsgFileChunkData{
+				FileID: fileID,
+				Offset: offset,
+				Data:   data[offset:end]},
+		}); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		offset = end
+	}
+
+	if err := ft.sepp.SendMsg(MsgFileComplete{
+		MsgBase: ft.base(MsgTypeFileComplete),
+		Data:    MsgFileCompleteData{FileID: fileID, SHA256: checksum},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// IncomingCh returns the channel receiving offered files. Re-offers of a
+// file already received partially are not delivered again, but accepted
+// automatically at the received offset. Offers are dropped if the
+// channel isn't read and its buffer is full, so the receiver isn't
+// blocked.
+func (ft *FileTransfer) IncomingCh() <-chan *IncomingFile {
+	return ft.incoming
+}
+
+// Run processes received file messages until ctx is done or the GoSepp
+// is stopped.
+func (ft *FileTransfer) Run(ctx context.Context) {
+	ch, cancel := ft.sepp.Subscribe(FilterTypes(MsgTypeFileOffer,
+		MsgTypeFileChunk, MsgTypeFileComplete))
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			ft.handle(msg)
+		}
+	}
+}
+
+func (ft *FileTransfer) handle(msg MsgInterface) {
+	switch m := msg.(type) {
+	case *MsgFileOffer:
+		if m.Data.Size < 0 || m.Data.Size > ft.maxSize {
+			ft.logger.Warn("Ignoring offer of file %s with %d bytes.",
+				m.Data.FileID, m.Data.Size)
+			return
+		}
+		ft.mu.Lock()
+		f, resumed := ft.files[m.Data.FileID]
+		if !resumed {
+			f = &IncomingFile{
+				Offer: m.Data,
+				ft:    ft,
+				from:  m.From,
+				done:  make(chan struct{}),
+			}
+			ft.files[m.Data.FileID] = f
+		}
+		ft.mu.Unlock()
+		if resumed {
+			if err := f.Accept(); err != nil {
+				ft.logger.Warn("Failed to resume file %s [%s].", m.Data.FileID, err)
+			}
+			return
+		}
+		select {
+		case ft.incoming <- f:
+		default:
+			ft.logger.Warn("Dropping offer of file %s, as it's not read.",
+				m.Data.FileID)
+			ft.mu.Lock()
+			delete(ft.files, m.Data.FileID)
+			ft.mu.Unlock()
+		}
+	case *MsgFileChunk:
+		if f := ft.file(m.Data.FileID); f != nil {
+			f.write(m.Data.Offset, m.Data.Data)
+		}
+	case *MsgFileComplete:
+		if f := ft.file(m.Data.FileID); f != nil {
+			f.complete(m.Data.SHA256)
+			ft.mu.Lock()
+			delete(ft.files, m.Data.FileID)
+			ft.mu.Unlock()
+		}
+	}
+}
+
+func (ft *FileTransfer) file(fileID string) *IncomingFile {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return ft.files[fileID]
+}
+
+// IncomingFile is a file offered by the remote end.
+type IncomingFile struct {
+	Offer MsgFileOfferData
+	ft    *FileTransfer
+	from  string
+	done  chan struct{}
+
+	mu   sync.Mutex
+	data []byte
+	err  error
+}
+
+// Accept the file. The transfer starts at the offset received so far.
+func (f *IncomingFile) Accept() error {
+	f.mu.Lock()
+	offset := int64(len(f.data))
+	f.mu.Unlock()
+	if err := f.ft.sepp.SendMsg(MsgFileAccept{
+		MsgBase: MsgBase{Type: MsgTypeFileAccept, From: f.ft.from, To: f.from},
+		Data:    MsgFileAcceptData{FileID: f.Offer.FileID, Offset: offset},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Wait blocks until the file is complete and returns its content. An
+// ErrChecksumMismatch is returned if the content does not match the
+// checksum of the sender.
+func (f *IncomingFile) Wait(ctx context.Context) ([]byte, error) {
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctxError(ctx, "wait for file")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data, f.err
+}
+
+func (f *IncomingFile) write(offset int64, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// only append in order, duplicates of a resumed transfer are skipped.
+	if f.err != nil || offset != int64(len(f.data)) {
+		return
+	}
+	if offset+int64(len(data)) > f.Offer.Size {
+		f.err = ErrFileTooLarge
+		f.data = nil
+		return
+	}
+	f.data = append(f.data, data...)
+}
+
+func (f *IncomingFile) complete(checksum string) {
+	f.mu.Lock()
+	sum := sha256.Sum256(f.data)
+	if f.err == nil && hex.EncodeToString(sum[:]) != checksum {
+		f.err = ErrChecksumMismatch
+	}
+	f.mu.Unlock()
+	close(f.done)
+}
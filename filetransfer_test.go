@@ -0,0 +1,100 @@
+package gosepp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFileTransfer(t *testing.T) {
+	r := &relay{}
+	srv := newFakeServer(t, r.handle)
+	defer srv.Close()
+
+	peers := make([]*GoSepp, 2)
+	for i := range peers {
+		sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+		if err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+		defer sepp.Stop()
+		if connected := <-sepp.ConnectStatusCh(); !connected {
+			t.Fatalf("failed to connect")
+		}
+		go func() {
+			for range sepp.RcvCh() {
+			}
+		}()
+		peers[i] = sepp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	receiver := NewFileTransfer(peers[1], "b", "a", nil)
+	go receiver.Run(ctx)
+	sender := NewFileTransfer(peers[0], "a", "b", nil, WithFileChunkSize(3))
+
+	content := []byte("a small pdf, shared in chat")
+	received := make(chan []byte, 1)
+	go func() {
+		f := <-receiver.IncomingCh()
+		if f.Offer.Name != "doc.pdf" || f.Offer.Size != int64(len(content)) {
+			t.Errorf("unexpected offer %+v", f.Offer)
+		}
+		if err := f.Accept(); err != nil {
+			t.Errorf("accept failed: %s", err)
+		}
+		data, err := f.Wait(ctx)
+		if err != nil {
+			t.Errorf("wait failed: %s", err)
+		}
+		received <- data
+	}()
+
+	if err := sender.Send(ctx, "f1", "doc.pdf", "application/pdf", content); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case data := <-received:
+		if !bytes.Equal(data, content) {
+			t.Fatalf("unexpected content %q", data)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for file")
+	}
+}
+
+func TestIncomingFileLimits(t *testing.T) {
+	ft := NewFileTransfer(nil, "b", "a", nil, WithMaxFileSize(8))
+
+	ft.handle(&MsgFileOffer{Data: MsgFileOfferData{FileID: "big", Size: 9}})
+	select {
+	case f := <-ft.IncomingCh():
+		t.Fatalf("offer over the maximum delivered: %+v", f.Offer)
+	default:
+	}
+
+	ft.handle(&MsgFileOffer{Data: MsgFileOfferData{FileID: "f1", Size: 4}})
+	f := <-ft.IncomingCh()
+	ft.handle(&MsgFileChunk{Data: MsgFileChunkData{FileID: "f1", Data: []byte("abc")}})
+	ft.handle(&MsgFileChunk{Data: MsgFileChunkData{FileID: "f1", Offset: 3,
+		Data: []byte("defgh")}})
+	ft.handle(&MsgFileComplete{Data: MsgFileCompleteData{FileID: "f1"}})
+	if _, err := f.Wait(context.Background()); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestIncomingFileNotRead(t *testing.T) {
+	ft := NewFileTransfer(nil, "b", "a", nil)
+	// nobody reads IncomingCh, so offers beyond its buffer are dropped.
+	for i := 0; i <= incomingFileBuffer; i++ {
+		ft.handle(&MsgFileOffer{Data: MsgFileOfferData{FileID: fmt.Sprint(i), Size: 1}})
+	}
+	if len(ft.IncomingCh()) != incomingFileBuffer || ft.file(fmt.Sprint(incomingFileBuffer)) != nil {
+		t.Fatalf("expected the last offer to be dropped")
+	}
+}
@@ -0,0 +1,116 @@
+package gosepp
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+// FragmentCapability is advertised in the hello message by ends which
+// reassemble fragment messages.
+const FragmentCapability = MsgTypeFragment
+
+const (
+	// fragmentOverhead is reserved for the envelope of a fragment.
+	fragmentOverhead = 256
+	// maxFragments limits the fragments of a single message.
+	maxFragments = 1024
+	// maxPartialMessages limits the messages reassembled concurrently.
+	maxPartialMessages = 16
+)
+
+var fragmentSeq uint64
+
+// sendText queues the text message data, split into fragments if it
+// exceeds the size limit and the remote end reassembles fragments.
+func (rtm *GoSepp) sendText(ctx context.Context, data []byte, lane Lane) error {
+	if rtm.maxMessageSize <= fragmentOverhead || len(data) <= rtm.maxMessageSize ||
+		!rtm.Supports(FragmentCapability) {
+		return rtm.sendCtx(ctx, TextMessage, data, lane)
+	}
+	// the payload is base64 encoded, which adds a third.
+	size := (rtm.maxMessageSize - fragmentOverhead) * 3 / 4
+	count := (len(data) + size - 1) / size
+	if count > maxFragments {
+		return rtm.checkSize(data)
+	}
+	id := strconv.FormatUint(atomic.AddUint64(&fragmentSeq, 1), 10)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+		b, err := rtm.encodeMsg(MsgFragment{
+			MsgBase: MsgBase{Type: MsgTypeFragment},
+			Data: MsgFragmentData{ID: id, Index: i, Count: count,
+				Payload: data[i*size : end]},
+		})
+		if err != nil {
+			return err
+		}
+		if err := rtm.sendCtx(ctx, TextMessage, b, lane); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reassembler collects the fragments received on a connection.
+type reassembler struct {
+	partial map[string][][]byte
+	// order holds the ids of partial messages, oldest first.
+	order []string
+}
+
+// add stores fragment f and returns the reassembled message once all
+// fragments were received.
+func (r *reassembler) add(rtm *GoSepp, f *MsgFragment) ([]byte, bool) {
+	if f.Data.Count <= 0 || f.Data.Count > maxFragments ||
+		f.Data.Index < 0 || f.Data.Index >= f.Data.Count {
+		rtm.logger.Warn("Dropping invalid fragment %d/%d.", f.Data.Index, f.Data.Count)
+		return nil, false
+	}
+	if r.partial == nil {
+		r.partial = make(map[string][][]byte)
+	}
+	parts, ok := r.partial[f.Data.ID]
+	if !ok {
+		if len(r.order) == maxPartialMessages {
+			rtm.logger.Warn("Dropping incomplete message %s.", r.order[0])
+			delete(r.partial, r.order[0])
+			r.order = r.order[1:]
+		}
+		parts = make([][]byte, f.Data.Count)
+		r.partial[f.Data.ID] = parts
+		r.order = append(r.order, f.Data.ID)
+	}
+	if len(parts) != f.Data.Count {
+		rtm.logger.Warn("Dropping fragment of %s with mismatching count.", f.Data.ID)
+		return nil, false
+	}
+	parts[f.Data.Index] = f.Data.Payload
+
+	size := 0
+	for _, p := range parts {
+		if p == nil {
+			return nil, false
+		}
+		size += len(p)
+	}
+	r.remove(f.Data.ID)
+	data := make([]byte, 0, size)
+	for _, p := range parts {
+		data = append(data, p...)
+	}
+	return data, true
+}
+
+func (r *reassembler) remove(id string) {
+	delete(r.partial, id)
+	for i, o := range r.order {
+		if o == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			return
+		}
+	}
+}
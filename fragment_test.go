@@ -0,0 +1,81 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFragmentation(t *testing.T) {
+	content := strings.Repeat("x", 5000)
+	received := make(chan MsgInterface, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.read()
+		c.write(MsgHello{MsgBase: MsgBase{Type: MsgTypeHello},
+			Data: MsgHelloData{ProtocolVersion: 1,
+				MsgTypes: []string{MsgTypeChat, FragmentCapability}}})
+		var r reassembler
+		sepp := &GoSepp{logger: &silentLogger{}}
+		for {
+			f, ok := c.read().(*MsgFragment)
+			if !ok {
+				return
+			}
+			if data, complete := r.add(sepp, f); complete {
+				msg, _ := DecodeMsg(data)
+				received <- msg
+				break
+			}
+		}
+		// answer with the fragments in reverse order.
+		b, _ := json.Marshal(NewChatMsg("conf", content))
+		count := (len(b) + 511) / 512
+		for i := count - 1; i >= 0; i-- {
+			end := (i + 1) * 512
+			if end > len(b) {
+				end = len(b)
+			}
+			c.write(MsgFragment{MsgBase: MsgBase{Type: MsgTypeFragment},
+				Data: MsgFragmentData{ID: "1", Index: i, Count: count,
+					Payload: b[i*512 : end]}})
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithHandshake(),
+		WithMaxMessageSize(1024))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !sepp.Supports(FragmentCapability) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for capabilities")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sepp.SendMsg(NewChatMsg("conf", content)); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+
+	select {
+	case msg := <-received:
+		if m, ok := msg.(*MsgChat); !ok || m.Data.Content != content {
+			t.Fatalf("unexpected message %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for reassembled message")
+	}
+	select {
+	case msg := <-sepp.RcvCh():
+		if m, ok := msg.(*MsgChat); !ok || m.Data.Content != content {
+			t.Fatalf("unexpected message %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for reassembled message")
+	}
+}
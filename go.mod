@@ -2,4 +2,4 @@ module github.com/eyeson-team/gosepp/v3
 
 require github.com/gorilla/websocket v1.5.0
 
-go 1.13
+go 1.18
@@ -0,0 +1,39 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGoldenFixtures asserts that every message type round-trips its
+// fixture unchanged. Run go run ./internal/genfixtures after adding a
+// message type, and with -update after deliberate wire format changes.
+func TestGoldenFixtures(t *testing.T) {
+	for msgType := range SeppMsgTypes {
+		path := filepath.Join("testdata", "fixtures", msgType+".json")
+		fixture, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s: missing fixture [%s]", msgType, err)
+			continue
+		}
+		msg, err := DecodeMsg(fixture)
+		if err != nil {
+			t.Errorf("%s: decode failed: %s", msgType, err)
+			continue
+		}
+		if msg.GetType() != msgType {
+			t.Errorf("%s: decoded as %s", msgType, msg.GetType())
+		}
+		b, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			t.Errorf("%s: marshal failed: %s", msgType, err)
+			continue
+		}
+		if !bytes.Equal(append(b, '\n'), fixture) {
+			t.Errorf("%s: round-trip differs from %s:\n%s", msgType, path, b)
+		}
+	}
+}
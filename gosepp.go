@@ -3,10 +3,8 @@ package gosepp
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sync"
@@ -38,22 +36,111 @@ const SeppEndpoint string = "wss://sig.eyeson.com/call"
 // GoSepp Confserver signaling.
 type GoSepp struct {
 	wsURL             *url.URL
-	wsClient          *websocket.Conn
+	wsClient          Conn
 	run               bool
 	rcvCh             chan MsgInterface
 	wsDialer          *websocket.Dialer
 	senderWaitGroup   sync.WaitGroup
 	receiverWaitGroup sync.WaitGroup
-	sendCh            chan []byte
+	deliverWaitGroup  sync.WaitGroup
+	// recvBuf decouples reading the connection from RcvCh consumers.
+	recvBuf           *recvRing
+	recvBufSize       int
+	overflowPolicy    OverflowPolicy
+	sendCh            chan frame
+	bulkCh            chan frame
+	binaryCh          chan BinaryFrame
 	connectStatusCh   chan bool
+	receiverCtx       context.Context
 	receiverCtxCancel context.CancelFunc
 	authToken         string
 	logger            Logger
+	// accepted is set for connections accepted by a Listener, which
+	// can't be re-established.
+	accepted bool
+	stopOnce sync.Once
+	journal  *Journal
+	// transport overrides the transport chosen by url scheme.
+	transport Transport
+	// endpoints holds the primary and fallback endpoints.
+	endpoints    *endpointSet
+	fallbackURLs []string
+	breaker      *CircuitBreaker
+	// handshake enables the hello exchange after connecting.
+	handshake       bool
+	protocolVersion int
+	// signingKey enables signing and verification of messages.
+	signingKey    []byte
+	idGenerator   IDGenerator
+	sequence      bool
+	onSequenceGap func(expected, received uint64)
+	validate      bool
+	pooled        bool
+	batchInterval time.Duration
+	queue         sendQueue
+	// maxMessageSize limits sent and received frames, if set.
+	maxMessageSize int
+	tokenProvider  TokenProvider
+	tokenMargin    time.Duration
+	tokenOnce      sync.Once
+	authPlacement  authPlacement
+	authName       string
+	subprotocols   []string
+	requestHeader  http.Header
+	strictTLS      bool
+	frameTrace     bool
+	stats          stats
+	qos            qos
+	// maxReconnects and reconnectBudget limit failed connect attempts.
+	maxReconnects   int
+	reconnectBudget time.Duration
+	redactSDP       bool
+	pprofLabels     []string
+	// mu guards wsClient, run, connected, authToken, remoteCaps,
+	// subprotocol, state, err, sessionID and resumeToken, which are shared
+	// by the receiver and sender goroutines.
+	mu          sync.Mutex
+	connected   bool
+	remoteCaps  *Capabilities
+	subprotocol string
+	state       ConnState
+	err         error
+	sessionID   string
+	resumeToken string
+
+	connStateHandler func(ConnState)
+	reconnectHandler func(ReconnectAttempt)
+	closeHandler     func(*CloseError)
+	closePolicy      ClosePolicy
+
+	connListenersMu sync.Mutex
+	connListeners   map[*connListener]struct{}
+
+	handlersMu sync.Mutex
+	handlers   []*handlerEntry
+
+	rawHooksMu      sync.Mutex
+	rawReceiveHooks []*rawHookEntry
+	rawSendHooks    []*rawHookEntry
+
+	subsMu     sync.Mutex
+	subs       map[*subscription]struct{}
+	subsClosed bool
+}
+
+// GoSeppOption defines the options interface of GoSepp.
+type GoSeppOption func(*GoSepp)
+
+// WithJournal records every sent and received message in j.
+func WithJournal(j *Journal) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.journal = j
+	}
 }
 
 // NewGoSepp returns a new GoSepp client.
 func NewGoSepp(baseURL, authToken string, tlsConfig *tls.Config,
-	logger Logger) (*GoSepp, error) {
+	logger Logger, options ...GoSeppOption) (*GoSepp, error) {
 	d := websocket.Dialer{TLSClientConfig: tlsConfig}
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
@@ -64,66 +151,94 @@ func NewGoSepp(baseURL, authToken string, tlsConfig *tls.Config,
 	if logger == nil {
 		logger = &silentLogger{}
 	}
+	logger = WithLogField(logger, "conn_id", nextConnID())
 
 	receiverCtx, receiverCancel := context.WithCancel(context.Background())
 	rtm := &GoSepp{
 		wsURL:             parsedURL,
+		endpoints:         &endpointSet{retryAfter: 30 * time.Second, logger: logger},
 		rcvCh:             make(chan MsgInterface, 1),
 		wsDialer:          &d,
-		sendCh:            make(chan []byte, 1),
+		sendCh:            make(chan frame, 1),
+		bulkCh:            make(chan frame, bulkQueueSize),
+		binaryCh:          make(chan BinaryFrame, binaryBufferSize),
 		connectStatusCh:   make(chan bool, 1),
 		receiverCtxCancel: receiverCancel,
 		run:               true,
 		authToken:         authToken,
+		protocolVersion:   ProtocolVersion,
 		logger:            logger}
 
+	for _, opt := range options {
+		opt(rtm)
+	}
+	rtm.pprofLabels = append([]string{"endpoint", endpointLabel(parsedURL)},
+		rtm.pprofLabels...)
+	if rtm.strictTLS {
+		if d.TLSClientConfig, err = strictTLSConfig(d.TLSClientConfig); err != nil {
+			return nil, err
+		}
+	}
+	if err := rtm.endpoints.set(append([]string{baseURL},
+		rtm.fallbackURLs...)); err != nil {
+		return nil, err
+	}
+
 	rtm.start(receiverCtx)
 	rtm.sender()
 	return rtm, nil
 }
 
+// newAcceptedGoSepp returns a GoSepp for an already established
+// connection. The receive channel is closed once the connection
+// is lost.
+func newAcceptedGoSepp(conn Conn, logger Logger,
+	options ...GoSeppOption) *GoSepp {
+	logger = WithLogField(logger, "conn_id", nextConnID())
+	receiverCtx, receiverCancel := context.WithCancel(context.Background())
+	rtm := &GoSepp{
+		wsClient:          conn,
+		endpoints:         &endpointSet{logger: logger},
+		rcvCh:             make(chan MsgInterface, 1),
+		sendCh:            make(chan frame, 1),
+		bulkCh:            make(chan frame, bulkQueueSize),
+		binaryCh:          make(chan BinaryFrame, binaryBufferSize),
+		connectStatusCh:   make(chan bool, 1),
+		receiverCtxCancel: receiverCancel,
+		run:               true,
+		protocolVersion:   ProtocolVersion,
+		logger:            logger,
+		accepted:          true,
+		connected:         true}
+
+	for _, opt := range options {
+		opt(rtm)
+	}
+	rtm.limitReads(conn)
+
+	rtm.start(receiverCtx)
+	rtm.sender()
+	return rtm
+}
+
 // CreateTLSConfig helper to create tls-config depending on configuration
-// parameters.
+// parameters. Use NewTLSConfig for further settings.
 func CreateTLSConfig(certFile, keyFile, caFile string, useSystemCAPool bool,
 	insecure bool) (*tls.Config, error) {
-	// load cert, key, and CA-file
-	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
-	if err != nil {
-		return nil, err
-	}
-
-	var caCertPool *x509.CertPool
+	options := []TLSOption{TLSClientCert(certFile, keyFile)}
 	if useSystemCAPool {
-		caCertPool, err = x509.SystemCertPool()
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		if len(caFile) > 0 {
-			// Load CA cert
-			caCert, err := ioutil.ReadFile(caFile)
-			if err != nil {
-				return nil, err
-			}
-			caCertPool = x509.NewCertPool()
-			if !caCertPool.AppendCertsFromPEM(caCert) {
-				return nil, fmt.Errorf("Failed to append CAcert")
-			}
-		}
+		options = append(options, TLSSystemRoots())
+	} else if len(caFile) > 0 {
+		options = append(options, TLSRootCAFile(caFile))
 	}
-
-	// Setup HTTPS client
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      caCertPool,
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-	}
-
 	if insecure {
-		tlsConfig.InsecureSkipVerify = insecure
+		options = append(options, TLSInsecureSkipVerify())
 	}
-
-	tlsConfig.BuildNameToCertificate()
+	tlsConfig, err := NewTLSConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	return tlsConfig, nil
 }
 
@@ -134,145 +249,584 @@ func (rtm *GoSepp) RcvCh() chan MsgInterface {
 }
 
 // ConnectStatusCh allow to monitor the websockets connection status.
+// If the channel is not consumed, further status updates are dropped.
+// SetConnStateHandler also tells reconnecting from closed.
 func (rtm *GoSepp) ConnectStatusCh() chan bool {
 	return rtm.connectStatusCh
 }
 
+type connListener struct {
+	fn func(connected bool)
+}
+
+// addConnectListener registers fn to be called on the receiving
+// goroutine after every connect attempt.
+func (rtm *GoSepp) addConnectListener(fn func(connected bool)) func() {
+	l := &connListener{fn: fn}
+	rtm.connListenersMu.Lock()
+	defer rtm.connListenersMu.Unlock()
+	if rtm.connListeners == nil {
+		rtm.connListeners = make(map[*connListener]struct{})
+	}
+	rtm.connListeners[l] = struct{}{}
+	return func() {
+		rtm.connListenersMu.Lock()
+		delete(rtm.connListeners, l)
+		rtm.connListenersMu.Unlock()
+	}
+}
+
+func (rtm *GoSepp) notifyConnectStatus(connected bool) {
+	select {
+	case rtm.connectStatusCh <- connected:
+	default:
+		rtm.logger.Debug("Connect status not consumed. Dropping.")
+	}
+
+	rtm.connListenersMu.Lock()
+	listeners := make([]*connListener, 0, len(rtm.connListeners))
+	for l := range rtm.connListeners {
+		listeners = append(listeners, l)
+	}
+	rtm.connListenersMu.Unlock()
+	for _, l := range listeners {
+		l.fn(connected)
+	}
+}
+
+// connect tries the endpoints in order of their health and preference
+// until one succeeds.
 func (rtm *GoSepp) connect(parentCtx context.Context) error {
+	var err error
+	for _, u := range rtm.endpoints.candidates(parentCtx) {
+		err = rtm.dial(parentCtx, u)
+		rtm.endpoints.report(u, err)
+		if err == nil {
+			return nil
+		}
+		rtm.logger.Warn("Failed to connect to %s [%s].", u, err)
+		if parentCtx.Err() != nil {
+			break
+		}
+	}
+	return err
+}
+
+func (rtm *GoSepp) dial(parentCtx context.Context, u *url.URL) error {
 	ctx, cancel := context.WithTimeout(parentCtx, 8*time.Second)
 	defer cancel()
 
-	requestHeader := make(http.Header)
-	if len(rtm.authToken) > 0 {
-		requestHeader.Add("Authorization", fmt.Sprintf("Bearer %s", rtm.authToken))
+	rtm.mu.Lock()
+	rtm.wsURL = u
+	rtm.mu.Unlock()
+
+	if rtm.tokenProvider != nil && (len(rtm.token()) == 0 || rtm.tokenExpiring()) {
+		if err := rtm.refreshToken(ctx); err != nil {
+			return fmt.Errorf("failed to refresh auth token: %w", err)
+		}
 	}
-	c, _, err := rtm.wsDialer.DialContext(ctx, rtm.wsURL.String(), requestHeader)
-	if err == nil {
-		rtm.wsClient = c
+	requestHeader := rtm.requestHeader.Clone()
+	if requestHeader == nil {
+		requestHeader = make(http.Header)
 	}
-	return err
+	dialURL := u
+	if authToken := rtm.token(); len(authToken) > 0 {
+		dialURL = rtm.applyAuth(u, requestHeader, authToken)
+	}
+	if err := rtm.applySession(requestHeader); err != nil {
+		return err
+	}
+	if len(rtm.subprotocols) > 0 {
+		requestHeader.Set("Sec-WebSocket-Protocol", rtm.subprotocolHeader())
+	}
+	transport, err := rtm.transportFor(u)
+	if err != nil {
+		return err
+	}
+	c, err := transport.Dial(ctx, dialURL, requestHeader)
+	if err != nil {
+		return err
+	}
+	subprotocol, err := rtm.negotiatedSubprotocol(c)
+	if err != nil {
+		c.Close()
+		return err
+	}
+	rtm.mu.Lock()
+	rtm.limitReads(c)
+	rtm.wsClient = c
+	rtm.connected = true
+	rtm.remoteCaps = nil
+	rtm.subprotocol = subprotocol
+	rtm.mu.Unlock()
+	return nil
+}
+
+func (rtm *GoSepp) conn() Conn {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.wsClient
+}
+
+func (rtm *GoSepp) isConnected() bool {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.connected
+}
+
+func (rtm *GoSepp) setConnected(connected bool) {
+	rtm.mu.Lock()
+	rtm.connected = connected
+	rtm.mu.Unlock()
+}
+
+func (rtm *GoSepp) running() bool {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.run
+}
+
+func (rtm *GoSepp) setRunning(run bool) {
+	rtm.mu.Lock()
+	rtm.run = run
+	rtm.mu.Unlock()
 }
 
 // Stop the internal messaging loop.
+// Calling Stop more than once has no effect.
 func (rtm *GoSepp) Stop() {
+	rtm.stopOnce.Do(rtm.stop)
+}
 
+func (rtm *GoSepp) stop() {
 	// 1. stop receive-path
-	rtm.run = false
-	if wsClient := rtm.wsClient; wsClient != nil {
+	rtm.setRunning(false)
+	if wsClient := rtm.conn(); wsClient != nil {
 		wsClient.Close()
 	}
 
 	// cancel receiver-ctx. So any possible running connect
 	// will return.
 	rtm.receiverCtxCancel()
+	rtm.recvBuf.close()
+	// the receiver closes the rcvCh, binaryCh and connectStatusCh
+	// on exit.
 	rtm.receiverWaitGroup.Wait()
-	// receiver is done now. So it's save to close the rcvCh
-	close(rtm.rcvCh)
-	close(rtm.connectStatusCh)
 
 	close(rtm.sendCh)
+	close(rtm.bulkCh)
 	rtm.senderWaitGroup.Wait()
+	rtm.queue.close()
 }
 
-// SendMsg sends a message over the underlying websocket.
+// SendMsg sends a message over the underlying connection.
 // In order to support concurrent writes, messages
 // are send through an internal channel.
 // Therefore messages are not sent immediately down
 // the wire.
 func (rtm *GoSepp) SendMsg(msg interface{}) error {
-	b, err := json.Marshal(msg)
+	b, err := rtm.encodeMsg(msg)
 	if err != nil {
 		return err
 	}
-	if rtm.run {
-		rtm.sendCh <- b
-	} else {
-		return fmt.Errorf("Not running")
+	return rtm.sendText(context.Background(), b, laneOf(msg))
+}
+
+// SendMsgCtx sends a message like SendMsg, but fails fast instead of
+// blocking: it returns ErrNotConnected if there is no connection, and
+// ErrQueueFull if msg could not be queued before ctx is done.
+func (rtm *GoSepp) SendMsgCtx(ctx context.Context, msg interface{}) error {
+	if !rtm.isConnected() {
+		return ErrNotConnected
 	}
-	return nil
+	b, err := rtm.encodeMsg(msg)
+	if err != nil {
+		return err
+	}
+	return rtm.sendText(ctx, b, laneOf(msg))
+}
+
+// SendMsgConfirmed sends a message like SendMsgCtx, but blocks until it
+// was written to the connection. It fails if the message was dropped
+// for lack of a connection or the write failed, so the caller can retry.
+// Messages are not fragmented, see WithMaxMessageSize.
+func (rtm *GoSepp) SendMsgConfirmed(ctx context.Context, msg interface{}) error {
+	if !rtm.isConnected() {
+		return ErrNotConnected
+	}
+	b, err := rtm.encodeMsg(msg)
+	if err != nil {
+		return err
+	}
+	written := make(chan error, 1)
+	if err := rtm.queueFrame(ctx, frame{messageType: TextMessage, data: b,
+		written: written}, laneOf(msg)); err != nil {
+		return err
+	}
+	select {
+	case err := <-written:
+		return err
+	case <-ctx.Done():
+		return ctxError(ctx, "wait for write")
+	}
+}
+
+// encodeMsg validates, if enabled, and marshals msg.
+func (rtm *GoSepp) encodeMsg(msg interface{}) ([]byte, error) {
+	if rtm.validate {
+		if err := ValidateMsg(msg); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(rtm.assignMsgID(msg))
+}
+
+// frame is a message queued for the sender.
+type frame struct {
+	messageType int
+	data        []byte
+	// enqueued is the time the frame was sent, for QoS reports.
+	enqueued time.Time
+	// written, if set, receives the result of writing the frame.
+	written chan error
+}
+
+// confirmFrames reports err as result of writing frames.
+func confirmFrames(frames []frame, err error) {
+	for _, f := range frames {
+		if f.written != nil {
+			f.written <- err
+		}
+	}
+}
+
+func (rtm *GoSepp) send(messageType int, data []byte, lane Lane) error {
+	return rtm.sendCtx(context.Background(), messageType, data, lane)
+}
+
+// sendCtx queues a frame on lane, blocking until it's queued or ctx is
+// done.
+func (rtm *GoSepp) sendCtx(ctx context.Context, messageType int, data []byte,
+	lane Lane) error {
+	return rtm.queueFrame(ctx, frame{messageType: messageType, data: data}, lane)
+}
 
+// queueFrame queues f on lane, blocking until it's queued or ctx is
+// done.
+func (rtm *GoSepp) queueFrame(ctx context.Context, f frame, lane Lane) error {
+	if rtm.breaker != nil && !rtm.breaker.allowSend() {
+		return ErrCircuitOpen
+	}
+	if !rtm.running() {
+		return ErrNotRunning
+	}
+	if err := rtm.checkSize(f.data); err != nil {
+		return err
+	}
+	ch := rtm.sendCh
+	if lane == LaneBulk {
+		ch = rtm.bulkCh
+	}
+	rtm.queue.add()
+	f.enqueued = time.Now()
+	select {
+	case ch <- f:
+		return nil
+	case <-ctx.Done():
+		rtm.queue.done(1)
+		return fmt.Errorf("%w: %s", ErrQueueFull, ctxError(ctx, "send"))
+	}
 }
 
 func (rtm *GoSepp) sender() {
 	rtm.senderWaitGroup.Add(1)
 	go func() {
 		defer rtm.senderWaitGroup.Done()
+		rtm.labelGoroutine("sender")
+		var seq seqCounter
 		for {
+			// control frames take precedence over queued bulk frames.
+			select {
+			case f, ok := <-rtm.sendCh:
+				if !rtm.sendFrame(f, ok, &seq) {
+					return
+				}
+				continue
+			default:
+			}
 			pingInterval := time.After(3 * time.Second)
 			select {
 			case <-pingInterval:
-				if wsClient := rtm.wsClient; wsClient != nil {
-					err := wsClient.WriteMessage(websocket.PingMessage, []byte("keepalive"))
+				if wsClient := rtm.conn(); wsClient != nil {
+					err := wsClient.WriteMessage(PingMessage, []byte("keepalive"))
 					if err != nil {
 						rtm.logger.Warn("failed to send ping")
 					}
 				}
-			case msg, ok := <-rtm.sendCh:
-				if !ok {
-					// exit sender
+			case f, ok := <-rtm.sendCh:
+				if !rtm.sendFrame(f, ok, &seq) {
 					return
 				}
-				if wsClient := rtm.wsClient; wsClient != nil {
-					err := wsClient.WriteMessage(websocket.TextMessage, msg)
-					if err != nil {
-						rtm.logger.Warn("failed to send.")
-					}
+			case f, ok := <-rtm.bulkCh:
+				if !rtm.sendFrame(f, ok, &seq) {
+					return
 				}
 			}
 		}
 	}()
 }
 
+// sendFrame writes the dequeued frame f, along with further frames if
+// batching. It returns false once the queue was closed.
+func (rtm *GoSepp) sendFrame(f frame, ok bool, seq *seqCounter) bool {
+	if !ok {
+		// exit sender
+		return false
+	}
+	frames := []frame{f}
+	if f.messageType == TextMessage && rtm.batching() {
+		frames, ok = rtm.collectBatch(f)
+	}
+	rtm.flush(frames, seq)
+	return ok
+}
+
+// prepare adds the sequence number and signature to an outgoing
+// message, if enabled.
+func (rtm *GoSepp) prepare(data []byte, seq uint64) []byte {
+	if rtm.sequence {
+		data = addSequence(data, seq)
+	}
+	if rtm.signingKey != nil {
+		signed, err := SignMsg(rtm.signingKey, data)
+		if err != nil {
+			rtm.logger.Warn("Failed to sign message [%s].", err)
+			return data
+		}
+		data = signed
+	}
+	return data
+}
+
+// record counts, traces and journals a sent or received message.
+func (rtm *GoSepp) record(dir Direction, data []byte) {
+	rtm.countMsg(dir, data)
+	if rtm.frameTrace {
+		rtm.logger.Trace("Frame %s: %s", dir, RedactFrame(data, rtm.redactSDP))
+	}
+	if rtm.journal == nil {
+		return
+	}
+	if err := rtm.journal.Record(dir, data); err != nil {
+		rtm.logger.Warn("Failed to write journal [%s].", err)
+	}
+}
+
+// sleep for d or until ctx is done.
+func (rtm *GoSepp) sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
 func (rtm *GoSepp) start(ctx context.Context) {
+	rtm.receiverCtx = ctx
+	rtm.recvBuf = newRecvRing(rtm.recvBufSize, rtm.overflowPolicy)
+	rtm.deliverWaitGroup.Add(1)
+	go func() {
+		rtm.labelGoroutine("deliverer")
+		rtm.deliver(ctx)
+	}()
 	rtm.receiverWaitGroup.Add(1)
 
 	go func() {
 		defer rtm.receiverWaitGroup.Done()
-		for rtm.run == true {
-			// try to connect
-			err := rtm.connect(ctx)
-			if err != nil {
-				rtm.logger.Warn("Failed to connect to %s [%s]. Retrying.", rtm.wsURL, err)
-				rtm.connectStatusCh <- false
-				if rtm.run {
-					time.Sleep(2 * time.Second)
+		rtm.labelGoroutine("receiver")
+		// the receiver and deliverer are the only writers of these
+		// channels, so it's save to close them here.
+		defer close(rtm.connectStatusCh)
+		defer close(rtm.rcvCh)
+		defer rtm.deliverWaitGroup.Wait()
+		defer rtm.recvBuf.close()
+		defer close(rtm.binaryCh)
+		defer rtm.closeSubscriptions()
+		defer rtm.setState(ConnClosed)
+		var attempts reconnectTracker
+		for rtm.running() {
+			if !rtm.accepted {
+				if rtm.breaker != nil && !rtm.breaker.allowConnect() {
+					rtm.sleep(ctx, 2*time.Second)
+					continue
 				}
-				continue
-			}
-			rtm.connectStatusCh <- true
-
-			// start recv and send loop
-			for {
-				messageType, message, err := rtm.wsClient.ReadMessage()
+				// try to connect
+				err := rtm.connect(ctx)
 				if err != nil {
-					rtm.logger.Warn("read failed with: %s.", err)
-					// Note, breaking the inner for loop here, triggering
-					// a new reconnect.
-					break
-				}
-
-				if messageType == websocket.TextMessage {
-					// parse
-					var msgBase MsgBase
-					err := json.Unmarshal(message, &msgBase)
-					if err != nil {
-						rtm.logger.Warn("Failed to unmarshal [%s].\n", err)
-						continue
+					rtm.logger.Warn("Failed to connect [%s]. Retrying.", err)
+					if rtm.breaker != nil {
+						rtm.breaker.failure()
 					}
-					msgInitFunc, ok := SeppMsgTypes[msgBase.Type]
-					if !ok {
-						rtm.logger.Warn("Message-type %s not supported.", msgBase.Type)
-						continue
+					rtm.notifyConnectStatus(false)
+					if giveUpErr := attempts.failed(rtm, err); giveUpErr != nil {
+						rtm.giveUp(giveUpErr)
+						break
 					}
-					interf := msgInitFunc()
-					err = json.Unmarshal(message, interf)
-					if err != nil {
-						rtm.logger.Warn("Failed to unmarshal.")
-						continue
+					if rtm.running() {
+						rtm.notifyReconnect(attempts.failures, err)
+						rtm.sleep(ctx, reconnectDelay)
 					}
-					rtm.rcvCh <- interf
+					continue
+				}
+				attempts.reset()
+				if rtm.breaker != nil {
+					rtm.breaker.success()
+				}
+				if !rtm.running() {
+					// stopped while connecting, so stop didn't
+					// close this connection.
+					rtm.conn().Close()
+					break
 				}
+				if rtm.tokenProvider != nil {
+					rtm.tokenOnce.Do(func() { go rtm.refreshTokens(ctx) })
+				}
+			}
+			if rtm.handshake {
+				rtm.sendHello()
+			}
+			rtm.stats.connected()
+			rtm.setState(ConnConnected)
+			rtm.notifyConnectStatus(true)
+
+			err := rtm.receive()
+			rtm.setConnected(false)
+			if closeErr := asCloseError(err); closeErr != nil &&
+				!rtm.handleClose(closeErr) && rtm.running() {
+				rtm.giveUp(closeErr)
+			}
+			if rtm.running() && !rtm.accepted {
+				rtm.setState(ConnReconnecting)
+			}
+
+			if rtm.accepted {
+				// accepted connections can't be re-established.
+				rtm.setRunning(false)
 			}
 		}
 	}()
 }
+
+// receive reads and decodes messages until the connection fails, and
+// returns why.
+func (rtm *GoSepp) receive() error {
+	var state recvState
+	for {
+		messageType, message, release, err := readMessage(rtm.conn())
+		if err != nil {
+			rtm.logger.Warn("read failed with: %s.", err)
+			return err
+		}
+		rtm.stats.received(len(message))
+		rtm.runRawHooks(&rtm.rawReceiveHooks, messageType, message)
+		rtm.handleFrame(messageType, message, &state)
+		// message is not referenced after handling, so its buffer
+		// can be reused.
+		release()
+	}
+}
+
+// recvState is the receive state of a connection.
+type recvState struct {
+	seq       seqChecker
+	fragments reassembler
+}
+
+func (rtm *GoSepp) handleFrame(messageType int, message []byte, state *recvState) {
+	if messageType == BinaryMessage {
+		rtm.stats.msg(DirectionIn, binaryFrameType)
+		rtm.receiveBinary(message)
+		return
+	}
+	if messageType != TextMessage {
+		return
+	}
+	if err := rtm.checkSize(message); err != nil {
+		rtm.logger.Warn("Dropping message [%s].", err)
+		return
+	}
+	if !isBatch(message) {
+		rtm.handleText(message, state)
+		return
+	}
+	msgs, err := splitBatch(message)
+	if err != nil {
+		rtm.logger.Warn("Failed to decode batch [%s].", err)
+		return
+	}
+	for _, msg := range msgs {
+		rtm.handleText(msg, state)
+	}
+}
+
+// handleText handles a single received message.
+func (rtm *GoSepp) handleText(message []byte, state *recvState) {
+	rtm.record(DirectionIn, message)
+	if rtm.signingKey != nil {
+		if err := VerifyMsg(rtm.signingKey, message); err != nil {
+			rtm.logger.Warn("Dropping message [%s].", err)
+			return
+		}
+	}
+	if rtm.sequence {
+		state.seq.check(rtm, message)
+	}
+	rtm.dispatch(message, state)
+}
+
+// dispatch decodes message and delivers it.
+func (rtm *GoSepp) dispatch(message []byte, state *recvState) {
+	decode := DecodeMsgVersion
+	if rtm.pooled {
+		decode = decodePooled
+	}
+	msg, err := decode(message, rtm.ProtocolVersion())
+	if err != nil {
+		rtm.logger.Warn("Failed to decode message [%s].", err)
+		return
+	}
+	if f, ok := msg.(*MsgFragment); ok {
+		data, complete := state.fragments.add(rtm, f)
+		if rtm.pooled {
+			ReleaseMsg(f)
+		}
+		if complete {
+			rtm.dispatch(data, state)
+		}
+		return
+	}
+	if m, ok := msg.(interface{ setReceivedAt(time.Time) }); ok {
+		m.setReceivedAt(time.Now())
+	}
+	switch m := msg.(type) {
+	case *MsgHello:
+		rtm.setRemoteCapabilities(&Capabilities{
+			ProtocolVersion: m.Data.ProtocolVersion,
+			MsgTypes:        m.Data.MsgTypes})
+	case *MsgResumeToken:
+		rtm.setResumeToken(m.Data.Token)
+	case *MsgAuthExpired:
+		// refreshing must not block the receiver.
+		go rtm.handleAuthExpired(rtm.receiverCtx, m.Data)
+	case *MsgForceDisconnect:
+		rtm.handleForceDisconnect(m.Data)
+	}
+	rtm.runHandlers(msg)
+	rtm.publish(msg)
+	switch msg.(type) {
+	case *MsgHello, *MsgEcho, *MsgResumeToken:
+		// connection level messages never reach the
+		// receive channel.
+	default:
+		rtm.enqueue(msg)
+	}
+}
@@ -0,0 +1,84 @@
+package gosepp
+
+import "sort"
+
+// MsgHandler is called for received messages. Handlers run on the
+// receiving goroutine, so they must not block.
+type MsgHandler func(msg MsgInterface)
+
+// MsgTypeAny registers a handler for all message types.
+const MsgTypeAny = ""
+
+type handlerEntry struct {
+	msgType  string
+	priority int
+	once     bool
+	handler  MsgHandler
+}
+
+// On registers handler for messages of msgType. Handlers are called in
+// order of registration, before the message is delivered to
+// subscriptions and RcvCh. The returned function removes the handler.
+func (rtm *GoSepp) On(msgType string, handler MsgHandler) func() {
+	return rtm.addHandler(&handlerEntry{msgType: msgType, handler: handler})
+}
+
+// OnPriority registers handler like On. Handlers with a higher priority
+// are called first, so middleware can be guaranteed to see a message
+// before normal handlers. On registers with priority 0.
+func (rtm *GoSepp) OnPriority(msgType string, priority int, handler MsgHandler) func() {
+	return rtm.addHandler(&handlerEntry{msgType: msgType, priority: priority,
+		handler: handler})
+}
+
+// Once registers handler to be called for the next message of msgType
+// only, e.g. to wait for the next call_accepted.
+func (rtm *GoSepp) Once(msgType string, handler MsgHandler) func() {
+	return rtm.addHandler(&handlerEntry{msgType: msgType, once: true,
+		handler: handler})
+}
+
+func (rtm *GoSepp) addHandler(entry *handlerEntry) func() {
+	rtm.handlersMu.Lock()
+	defer rtm.handlersMu.Unlock()
+	rtm.handlers = append(rtm.handlers, entry)
+	// stable, so equal priorities keep the order of registration
+	sort.SliceStable(rtm.handlers, func(i, j int) bool {
+		return rtm.handlers[i].priority > rtm.handlers[j].priority
+	})
+	return func() { rtm.removeHandler(entry) }
+}
+
+func (rtm *GoSepp) removeHandler(entry *handlerEntry) {
+	rtm.handlersMu.Lock()
+	defer rtm.handlersMu.Unlock()
+	for i, e := range rtm.handlers {
+		if e == entry {
+			rtm.handlers = append(rtm.handlers[:i], rtm.handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+// runHandlers calls the handlers matching msg. Once-handlers are
+// removed before being called.
+func (rtm *GoSepp) runHandlers(msg MsgInterface) {
+	rtm.handlersMu.Lock()
+	var matched []MsgHandler
+	remaining := rtm.handlers[:0]
+	for _, e := range rtm.handlers {
+		if e.msgType == MsgTypeAny || e.msgType == msg.GetType() {
+			matched = append(matched, e.handler)
+			if e.once {
+				continue
+			}
+		}
+		remaining = append(remaining, e)
+	}
+	rtm.handlers = remaining
+	rtm.handlersMu.Unlock()
+
+	for _, handler := range matched {
+		handler(msg)
+	}
+}
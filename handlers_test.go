@@ -0,0 +1,27 @@
+package gosepp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHandlersPriorityAndOnce(t *testing.T) {
+	rtm := &GoSepp{}
+	var calls []string
+	rtm.On(MsgTypeChat, func(MsgInterface) { calls = append(calls, "normal") })
+	rtm.Once(MsgTypeChat, func(MsgInterface) { calls = append(calls, "once") })
+	rtm.OnPriority(MsgTypeAny, 10, func(MsgInterface) { calls = append(calls, "middleware") })
+	remove := rtm.On(MsgTypeRecording, func(MsgInterface) { calls = append(calls, "recording") })
+	remove()
+
+	chat := &MsgChat{MsgBase: MsgBase{Type: MsgTypeChat}}
+	rtm.runHandlers(chat)
+	rtm.runHandlers(chat)
+	rtm.runHandlers(&MsgRecording{MsgBase: MsgBase{Type: MsgTypeRecording}})
+
+	expected := []string{"middleware", "normal", "once", "middleware", "normal",
+		"middleware"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("got %v, want %v", calls, expected)
+	}
+}
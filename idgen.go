@@ -0,0 +1,115 @@
+package gosepp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// IDGenerator creates the msg_id of outgoing messages.
+type IDGenerator interface {
+	NewID(msg MsgInterface) string
+}
+
+// IDGeneratorFunc adapts a function to an IDGenerator.
+type IDGeneratorFunc func(msg MsgInterface) string
+
+// NewID calls f.
+func (f IDGeneratorFunc) NewID(msg MsgInterface) string {
+	return f(msg)
+}
+
+// WithIDGenerator sets the msg_id of every outgoing message without one
+// using gen.
+func WithIDGenerator(gen IDGenerator) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.idGenerator = gen
+	}
+}
+
+// assignMsgID returns msg with the msg_id set by the id generator. The
+// message is copied, so values passed by the caller are not modified.
+func (rtm *GoSepp) assignMsgID(msg interface{}) interface{} {
+	if rtm.idGenerator == nil || len(msgIDOf(msg)) > 0 {
+		return msg
+	}
+	v := reflect.ValueOf(msg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return msg
+	}
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	m, ok := cp.Interface().(MsgInterface)
+	if !ok {
+		return msg
+	}
+	cp.Elem().FieldByName("MsgID").SetString(rtm.idGenerator.NewID(m))
+	return m
+}
+
+// UUIDv7Generator returns time ordered UUIDs version 7.
+func UUIDv7Generator() IDGenerator {
+	return IDGeneratorFunc(func(msg MsgInterface) string {
+		var u [16]byte
+		rand.Read(u[6:])
+		ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+		for i := 0; i < 6; i++ {
+			u[i] = byte(ms >> (40 - 8*i))
+		}
+		u[6] = u[6]&0x0f | 0x70 // version 7
+		u[8] = u[8]&0x3f | 0x80 // variant
+		h := hex.EncodeToString(u[:])
+		return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+	})
+}
+
+// snowflakeEpoch is the epoch of SnowflakeGenerator ids, 2020-01-01.
+const snowflakeEpoch = 1577836800000
+
+// SnowflakeGenerator returns 63 bit ids composed of a millisecond
+// timestamp, the node (0-1023) and a sequence number.
+func SnowflakeGenerator(node int64) IDGenerator {
+	var mu sync.Mutex
+	var last, seq int64
+	return IDGeneratorFunc(func(msg MsgInterface) string {
+		mu.Lock()
+		defer mu.Unlock()
+		ms := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+		if ms <= last {
+			// same millisecond or clock went backwards
+			ms = last
+			seq = (seq + 1) & 0xfff
+			if seq == 0 {
+				ms++
+			}
+		} else {
+			seq = 0
+		}
+		last = ms
+		return strconv.FormatInt(ms<<22|(node&0x3ff)<<12|seq, 10)
+	})
+}
+
+// CallSequenceGenerator returns ids of the form <call-id>-<n>, counting
+// per call. Messages without call-id are counted by their to header.
+func CallSequenceGenerator() IDGenerator {
+	var mu sync.Mutex
+	seqs := make(map[string]uint64)
+	return IDGeneratorFunc(func(msg MsgInterface) string {
+		key := string(CallIDOf(msg))
+		if len(key) == 0 {
+			key = msg.GetTo()
+		}
+		mu.Lock()
+		seqs[key]++
+		n := seqs[key]
+		mu.Unlock()
+		return key + "-" + strconv.FormatUint(n, 10)
+	})
+}
@@ -0,0 +1,33 @@
+package gosepp
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestIDGenerators(t *testing.T) {
+	uuid := UUIDv7Generator().NewID(&MsgChat{})
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Fatalf("invalid uuid %s", uuid)
+	}
+
+	snowflake := SnowflakeGenerator(1)
+	if a, b := snowflake.NewID(nil), snowflake.NewID(nil); a == b {
+		t.Fatalf("duplicate snowflake id %s", a)
+	}
+
+	rtm := &GoSepp{idGenerator: CallSequenceGenerator()}
+	msg := MsgChat{Data: MsgChatData{CallID: "call"}}
+	for _, want := range []string{"call-1", "call-2"} {
+		if id := msgIDOf(rtm.assignMsgID(msg)); id != want {
+			t.Fatalf("expected %s, got %s", want, id)
+		}
+	}
+	if len(msg.MsgID) > 0 {
+		t.Fatalf("message of caller was modified")
+	}
+	msg.MsgID = "given"
+	if id := msgIDOf(rtm.assignMsgID(&msg)); id != "given" {
+		t.Fatalf("existing msg_id was replaced with %s", id)
+	}
+}
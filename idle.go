@@ -0,0 +1,100 @@
+package gosepp
+
+import (
+	"time"
+)
+
+// IdleCondition selects when a call counts as idle.
+type IdleCondition int
+
+const (
+	// IdleAlone is met while no members besides the call itself are
+	// listed in the memberlist.
+	IdleAlone IdleCondition = iota
+	// IdleSilent is met while no messages are received for the call.
+	IdleSilent
+)
+
+// WithIdleTerminate terminates calls which were idle for timeout, so
+// unattended bots don't hold rooms open forever.
+func WithIdleTerminate(timeout time.Duration, condition IdleCondition) CallOption {
+	return func(c *Call) {
+		c.idleTimeout = timeout
+		c.idleCondition = condition
+	}
+}
+
+// idleTimer fires once a call was idle for its timeout.
+type idleTimer struct {
+	timeout   time.Duration
+	condition IdleCondition
+	self      string
+	timer     *time.Timer
+	// others are the members besides the call itself.
+	others map[string]bool
+}
+
+func newIdleTimer(timeout time.Duration, condition IdleCondition,
+	self string) *idleTimer {
+	return &idleTimer{
+		timeout:   timeout,
+		condition: condition,
+		self:      self,
+		timer:     time.NewTimer(timeout),
+		others:    make(map[string]bool),
+	}
+}
+
+// C returns the channel the expiry is delivered on. A nil timer never
+// fires.
+func (t *idleTimer) C() <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.timer.C
+}
+
+// observe updates the idle state with a received message.
+func (t *idleTimer) observe(msg MsgInterface) {
+	if t == nil {
+		return
+	}
+	switch t.condition {
+	case IdleSilent:
+		t.restart()
+	case IdleAlone:
+		m, ok := msg.(*MsgMemberlist)
+		if !ok {
+			return
+		}
+		wasAlone := len(t.others) == 0
+		for _, member := range m.Data.Add {
+			if member.ClientID != t.self {
+				t.others[member.ClientID] = true
+			}
+		}
+		for _, clientID := range m.Data.Del {
+			delete(t.others, clientID)
+		}
+		switch {
+		case len(t.others) > 0:
+			t.stop()
+		case !wasAlone:
+			t.restart()
+		}
+	}
+}
+
+func (t *idleTimer) stop() {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+}
+
+func (t *idleTimer) restart() {
+	t.stop()
+	t.timer.Reset(t.timeout)
+}
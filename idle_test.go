@@ -0,0 +1,46 @@
+package gosepp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdleTerminate(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "bot",
+		ConfID: "conf"}, nil, WithIdleTerminate(100*time.Millisecond, IdleAlone))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	terminated := make(chan struct{})
+	call.SetTerminatedHandler(func() { close(terminated) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := call.Start(ctx, Sdp{SdpType: "offer", Sdp: "sdp"}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	select {
+	case <-terminated:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("idle call was not terminated")
+	}
+}
+
+func TestIdleTimerAlone(t *testing.T) {
+	idle := newIdleTimer(time.Hour, IdleAlone, "bot")
+	defer idle.stop()
+	idle.observe(&MsgMemberlist{Data: MsgMemberlistData{
+		Add: []Member{{ClientID: "bot"}, {ClientID: "alice"}}}})
+	if len(idle.others) != 1 {
+		t.Fatalf("expected one other member, got %v", idle.others)
+	}
+	idle.observe(&MsgMemberlist{Data: MsgMemberlistData{Del: []string{"alice"}}})
+	if len(idle.others) != 0 {
+		t.Fatalf("expected to be alone, got %v", idle.others)
+	}
+}
@@ -0,0 +1,73 @@
+package gosepp
+
+import (
+	"errors"
+	"unicode"
+)
+
+// ErrInvalidID is returned for malformed client or conference ids.
+var ErrInvalidID = errors.New("invalid id")
+
+// ClientID identifies a client. On the wire it is a plain string.
+type ClientID string
+
+// ConfID identifies a conference. On the wire it is a plain string.
+type ConfID string
+
+func (id ClientID) String() string {
+	return string(id)
+}
+
+// Validate reports ErrInvalidID if id is empty or contains whitespace
+// or control characters.
+func (id ClientID) Validate() error {
+	return validateID(string(id))
+}
+
+func (id ConfID) String() string {
+	return string(id)
+}
+
+// Validate reports ErrInvalidID if id is empty or contains whitespace
+// or control characters.
+func (id ConfID) Validate() error {
+	return validateID(string(id))
+}
+
+func (id CallID) String() string {
+	return string(id)
+}
+
+// ParseClientID converts s to a validated ClientID.
+func ParseClientID(s string) (ClientID, error) {
+	id := ClientID(s)
+	return id, id.Validate()
+}
+
+// ParseConfID converts s to a validated ConfID.
+func ParseConfID(s string) (ConfID, error) {
+	id := ConfID(s)
+	return id, id.Validate()
+}
+
+func validateID(id string) error {
+	if len(id) == 0 {
+		return ErrInvalidID
+	}
+	for _, r := range id {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return ErrInvalidID
+		}
+	}
+	return nil
+}
+
+// FromClientID returns the from header of a message sent by a client.
+func (msg *MsgBase) FromClientID() ClientID {
+	return ClientID(msg.From)
+}
+
+// ToConfID returns the to header of a message sent to a conference.
+func (msg *MsgBase) ToConfID() ConfID {
+	return ConfID(msg.To)
+}
@@ -0,0 +1,204 @@
+// Command genclone generates the Clone methods of the sepp messages.
+//
+//	go run ./internal/genclone
+//
+// It reads sepp_messages.go and sepp_messages_gen.go and writes
+// clone_gen.go. Every struct
+// embedding MsgBase gets a Clone method returning a deep copy, so a
+// message can be handed to multiple goroutines without aliasing slices
+// or pointers.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+)
+
+const output = "clone_gen.go"
+
+var inputs = []string{"sepp_messages.go", "sepp_messages_gen.go"}
+
+type generator struct {
+	structs map[string]*ast.StructType
+	deep    map[string]bool
+	buf     bytes.Buffer
+}
+
+func main() {
+	g := &generator{
+		structs: make(map[string]*ast.StructType),
+		deep:    make(map[string]bool),
+	}
+	fset := token.NewFileSet()
+	for _, input := range inputs {
+		file, err := parser.ParseFile(fset, input, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					g.structs[ts.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	names := make([]string, 0, len(g.structs))
+	for name := range g.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.printf("// Code generated by genclone. DO NOT EDIT.\n\n")
+	g.printf("package gosepp\n\n")
+	var msgs []string
+	for _, name := range names {
+		if g.isDeep(name, nil) {
+			g.genClone(name)
+		}
+		if embedsMsgBase(g.structs[name]) {
+			msgs = append(msgs, name)
+			g.genMsgClone(name)
+		}
+	}
+	g.genCloneMsg(msgs)
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("invalid generated code: %s\n%s", err, g.buf.Bytes())
+	}
+	if err := ioutil.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func embedsMsgBase(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if id, ok := f.Type.(*ast.Ident); ok && len(f.Names) == 0 && id.Name == "MsgBase" {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeep reports whether a value of the local struct name holds slices
+// or pointers, which a plain assignment would alias.
+func (g *generator) isDeep(name string, seen map[string]bool) bool {
+	if deep, ok := g.deep[name]; ok {
+		return deep
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	if seen[name] {
+		return false
+	}
+	seen[name] = true
+	deep := false
+	for _, f := range g.structs[name].Fields.List {
+		if g.isDeepExpr(f.Type, seen) {
+			deep = true
+		}
+	}
+	g.deep[name] = deep
+	return deep
+}
+
+func (g *generator) isDeepExpr(expr ast.Expr, seen map[string]bool) bool {
+	switch t := expr.(type) {
+	case *ast.ArrayType, *ast.StarExpr, *ast.MapType:
+		return true
+	case *ast.Ident:
+		if _, local := g.structs[t.Name]; local {
+			return g.isDeep(t.Name, seen)
+		}
+	}
+	return false
+}
+
+func typeString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+// genClone writes the clone method of a deep local struct.
+func (g *generator) genClone(name string) {
+	g.printf("func (v %s) clone() %s {\n\tc := v\n", name, name)
+	for _, f := range g.structs[name].Fields.List {
+		if !g.isDeepExpr(f.Type, nil) {
+			continue
+		}
+		for _, n := range f.Names {
+			g.genFieldCopy("c."+n.Name, "v."+n.Name, f.Type)
+		}
+	}
+	g.printf("\treturn c\n}\n\n")
+}
+
+func (g *generator) genFieldCopy(dst, src string, expr ast.Expr) {
+	switch t := expr.(type) {
+	case *ast.ArrayType:
+		g.printf("\tif %s != nil {\n", src)
+		g.printf("\t\t%s = make(%s, len(%s))\n", dst, typeString(t), src)
+		if g.isDeepExpr(t.Elt, nil) {
+			g.printf("\t\tfor i := range %s {\n\t\t\t%s[i] = %s[i].clone()\n\t\t}\n",
+				src, dst, src)
+		} else {
+			g.printf("\t\tcopy(%s, %s)\n", dst, src)
+		}
+		g.printf("\t}\n")
+	case *ast.StarExpr:
+		g.printf("\tif %s != nil {\n", src)
+		if g.isDeepExpr(t.X, nil) {
+			g.printf("\t\tp := %s.clone()\n", src)
+		} else {
+			g.printf("\t\tp := *%s\n", src)
+		}
+		g.printf("\t\t%s = &p\n\t}\n", dst)
+	case *ast.MapType:
+		g.printf("\tif %s != nil {\n", src)
+		g.printf("\t\t%s = make(%s, len(%s))\n", dst, typeString(t), src)
+		g.printf("\t\tfor k, e := range %s {\n\t\t\t%s[k] = e\n\t\t}\n\t}\n", src, dst)
+	case *ast.Ident:
+		g.printf("\t%s = %s.clone()\n", dst, src)
+	}
+}
+
+// genMsgClone writes the exported Clone method of a message.
+func (g *generator) genMsgClone(name string) {
+	g.printf("// Clone returns a deep copy of msg.\n")
+	g.printf("func (msg *%s) Clone() *%s {\n", name, name)
+	g.printf("\tif msg == nil {\n\t\treturn nil\n\t}\n")
+	if g.isDeep(name, nil) {
+		g.printf("\tc := msg.clone()\n")
+	} else {
+		g.printf("\tc := *msg\n")
+	}
+	g.printf("\treturn &c\n}\n\n")
+}
+
+// genCloneMsg writes CloneMsg dispatching to the Clone methods.
+func (g *generator) genCloneMsg(msgs []string) {
+	g.printf("// CloneMsg returns a deep copy of msg. Messages of types not\n")
+	g.printf("// declared by this package are returned unchanged.\n")
+	g.printf("func CloneMsg(msg MsgInterface) MsgInterface {\n")
+	g.printf("\tswitch m := msg.(type) {\n")
+	for _, name := range msgs {
+		g.printf("\tcase *%s:\n\t\treturn m.Clone()\n", name)
+	}
+	g.printf("\t}\n\treturn msg\n}\n")
+}
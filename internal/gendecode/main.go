@@ -0,0 +1,343 @@
+// Command gendecode generates json decoders for the sepp messages.
+//
+//	go run ./internal/gendecode
+//
+// It reads sepp_messages.go and sepp_messages_gen.go and writes
+// decode_gen.go. Every struct gets a decodeJSON method reading it with
+// the jsonLexer, without reflection, and every struct embedding MsgBase
+// an unmarshalFast method used by DecodeMsg. Input the lexer doesn't
+// handle exactly like encoding/json is decoded with encoding/json
+// instead, so both decode the same. Field types the generator doesn't
+// know, e.g. json.Unmarshaler implementations, are decoded with
+// encoding/json as well.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const output = "decode_gen.go"
+
+var inputs = []string{"sepp_messages.go", "sepp_messages_gen.go"}
+
+// readers are the jsonLexer methods decoding basic types.
+var readers = map[string]string{
+	"string": "readString",
+	"int":    "readInt",
+	"int64":  "readInt64",
+	"bool":   "readBool",
+}
+
+// zeros are the zero values of the basic types.
+var zeros = map[string]string{
+	"string": `""`,
+	"int":    "0",
+	"int64":  "0",
+	"bool":   "false",
+}
+
+type generator struct {
+	structs map[string]*ast.StructType
+	buf     bytes.Buffer
+}
+
+// field is a json field of a struct, possibly promoted from an embedded
+// struct.
+type field struct {
+	name   string
+	path   string
+	typ    ast.Expr
+	depth  int
+	tagged bool
+}
+
+func main() {
+	g := &generator{structs: make(map[string]*ast.StructType)}
+	fset := token.NewFileSet()
+	for _, input := range inputs {
+		file, err := parser.ParseFile(fset, input, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					g.structs[ts.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	names := make([]string, 0, len(g.structs))
+	for name := range g.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.printf("// Code generated by gendecode. DO NOT EDIT.\n\n")
+	g.printf("package gosepp\n\n")
+	g.printf("import \"encoding/json\"\n\n")
+	for _, name := range names {
+		g.genDecode(name)
+		if embedsMsgBase(g.structs[name]) {
+			g.genUnmarshalFast(name)
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("invalid generated code: %s\n%s", err, g.buf.Bytes())
+	}
+	if err := ioutil.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func embedsMsgBase(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if id, ok := f.Type.(*ast.Ident); ok && len(f.Names) == 0 && id.Name == "MsgBase" {
+			return true
+		}
+	}
+	return false
+}
+
+func typeString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+// collect returns the json fields of the struct name, including the
+// promoted ones, in declaration order.
+func (g *generator) collect(name, prefix string, depth int) []field {
+	var fields []field
+	for _, f := range g.structs[name].Fields.List {
+		tag := ""
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				log.Fatal(err)
+			}
+			tag = reflect.StructTag(unquoted).Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		jsonName, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",string,") {
+			log.Fatalf("%s: the string option is not supported", name)
+		}
+		if len(f.Names) == 0 {
+			id, ok := f.Type.(*ast.Ident)
+			if !ok {
+				log.Fatalf("%s: unsupported embedded field %s", name, typeString(f.Type))
+			}
+			if _, local := g.structs[id.Name]; local && jsonName == "" {
+				fields = append(fields, g.collect(id.Name, prefix+id.Name+".", depth+1)...)
+				continue
+			}
+			f.Names = []*ast.Ident{id}
+		}
+		for _, n := range f.Names {
+			if !ast.IsExported(n.Name) {
+				continue
+			}
+			fields = append(fields, field{
+				name:   jsonName,
+				path:   prefix + n.Name,
+				typ:    f.Type,
+				depth:  depth,
+				tagged: jsonName != "",
+			})
+			if jsonName == "" {
+				fields[len(fields)-1].name = n.Name
+			}
+		}
+	}
+	return fields
+}
+
+// dominant drops the fields hidden by others of the same name, by the
+// rules of encoding/json.
+func dominant(fields []field) []field {
+	byName := make(map[string][]field)
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	var result []field
+	for _, f := range fields {
+		winner, ok := dominantOf(byName[f.name])
+		if ok && winner.path == f.path {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func dominantOf(fields []field) (field, bool) {
+	depth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < depth {
+			depth = f.depth
+		}
+	}
+	var candidates, tagged []field
+	for _, f := range fields {
+		if f.depth == depth {
+			candidates = append(candidates, f)
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return field{}, false
+}
+
+// fieldsVar returns the name of the variable listing the json fields of
+// the struct name.
+func fieldsVar(name string) string {
+	return strings.ToLower(name[:1]) + name[1:] + "Fields"
+}
+
+// genDecode writes the decodeJSON method of the struct name.
+func (g *generator) genDecode(name string) {
+	fields := dominant(g.collect(name, "", 0))
+	g.printf("var %s = []string{", fieldsVar(name))
+	for i, f := range fields {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%q", f.name)
+	}
+	g.printf("}\n\n")
+
+	g.printf("func (v *%s) decodeJSON(l *jsonLexer) {\n", name)
+	g.printf("if l.null() || !l.beginObject() {\nreturn\n}\n")
+	g.printf("for first := true; l.next('}', &first); {\n")
+	g.printf("switch k := l.key(); string(k) {\n")
+	for _, f := range fields {
+		g.printf("case %q:\n", f.name)
+		g.genValue("v."+f.path, f.typ, 0)
+	}
+	g.printf("default:\nl.unknown(k, %s)\n", fieldsVar(name))
+	g.printf("}\n}\n}\n\n")
+}
+
+// addr returns the address of the value dst.
+func addr(dst string) string {
+	if strings.HasPrefix(dst, "*") {
+		return dst[1:]
+	}
+	return "&" + dst
+}
+
+// recv returns dst as method receiver.
+func recv(dst string) string {
+	return strings.TrimPrefix(dst, "*")
+}
+
+// genValue writes the code decoding the next value into dst of type
+// expr. depth numbers the variables of nested slices and maps.
+func (g *generator) genValue(dst string, expr ast.Expr, depth int) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if reader, ok := readers[t.Name]; ok {
+			g.printf("l.%s(%s)\n", reader, addr(dst))
+			return
+		}
+		if _, local := g.structs[t.Name]; local {
+			g.printf("%s.decodeJSON(l)\n", recv(dst))
+			return
+		}
+	case *ast.StarExpr:
+		g.printf("if l.null() {\n%s = nil\n} else {\n", recv(dst))
+		g.printf("if %s == nil {\n%s = new(%s)\n}\n", recv(dst), recv(dst), typeString(t.X))
+		g.genValue("*"+recv(dst), t.X, depth)
+		g.printf("}\n")
+		return
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if id, ok := t.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			g.printf("l.readBytes(%s)\n", addr(dst))
+			return
+		}
+		s := fmt.Sprintf("s%d", depth)
+		g.printf("if l.null() {\n%s = nil\n} else if l.beginArray() {\n", dst)
+		g.printf("%s := %s[:0]\n", s, dst)
+		g.printf("for first := true; l.next(']', &first); {\n")
+		// start with the capacity used by encoding/json.
+		g.printf("if %s == nil {\n%s = make(%s, 0, 4)\n}\n", s, s, typeString(t))
+		g.printf("%s = append(%s, %s)\n", s, s, g.zero(t.Elt))
+		g.genValue(fmt.Sprintf("%s[len(%s)-1]", s, s), t.Elt, depth+1)
+		g.printf("}\n")
+		g.printf("if %s == nil {\n%s = %s{}\n}\n", s, s, typeString(t))
+		g.printf("%s = %s\n}\n", dst, s)
+		return
+	case *ast.MapType:
+		if id, ok := t.Key.(*ast.Ident); !ok || id.Name != "string" {
+			break
+		}
+		k, e := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		g.printf("if l.null() {\n%s = nil\n} else if l.beginObject() {\n", dst)
+		g.printf("if %s == nil {\n%s = make(%s)\n}\n", dst, dst, typeString(t))
+		g.printf("for first := true; l.next('}', &first); {\n")
+		g.printf("%s := string(l.key())\n", k)
+		g.printf("var %s %s\n", e, typeString(t.Value))
+		g.genValue(e, t.Value, depth+1)
+		g.printf("if l.err == nil {\n%s[%s] = %s\n}\n", dst, k, e)
+		g.printf("}\n}\n")
+		return
+	}
+	g.printf("l.unmarshal(%s)\n", addr(dst))
+}
+
+// zero returns the zero value of expr.
+func (g *generator) zero(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if zero, ok := zeros[t.Name]; ok {
+			return zero
+		}
+		if _, local := g.structs[t.Name]; local {
+			return t.Name + "{}"
+		}
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+		return "nil"
+	}
+	return fmt.Sprintf("*new(%s)", typeString(expr))
+}
+
+// genUnmarshalFast writes the unmarshalFast method of the message name.
+func (g *generator) genUnmarshalFast(name string) {
+	g.printf("func (msg *%s) unmarshalFast(data []byte) error {\n", name)
+	g.printf("c := *msg\n")
+	g.printf("l := jsonLexer{data: data}\n")
+	g.printf("c.decodeJSON(&l)\n")
+	g.printf("if l.end(); l.err != nil {\nreturn json.Unmarshal(data, msg)\n}\n")
+	g.printf("*msg = c\nreturn nil\n}\n\n")
+}
@@ -0,0 +1,127 @@
+// Command genfixtures writes the golden JSON fixtures of the sepp
+// messages.
+//
+//	go run ./internal/genfixtures [-update]
+//
+// Every registered message type gets testdata/fixtures/<type>.json
+// with all fields set to deterministic values. Existing fixtures are
+// kept unless -update is given, so changes of the wire format show up
+// as failing round-trip tests and fixture diffs.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+const dir = "testdata/fixtures"
+
+var timestampType = reflect.TypeOf(gosepp.Timestamp{})
+
+func main() {
+	update := flag.Bool("update", false, "overwrite existing fixtures")
+	flag.Parse()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatal(err)
+	}
+	types := make([]string, 0, len(gosepp.SeppMsgTypes))
+	for msgType := range gosepp.SeppMsgTypes {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+	for _, msgType := range types {
+		path := filepath.Join(dir, msgType+".json")
+		if _, err := os.Stat(path); err == nil && !*update {
+			continue
+		}
+		b, err := Fixture(msgType)
+		if err != nil {
+			log.Fatalf("%s: %s", msgType, err)
+		}
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("wrote", path)
+	}
+}
+
+// Fixture returns the canonical JSON of a message of msgType.
+func Fixture(msgType string) ([]byte, error) {
+	msg := gosepp.SeppMsgTypes[msgType]()
+	f := &filler{}
+	f.fill(reflect.ValueOf(msg).Elem(), "")
+	reflect.ValueOf(msg).Elem().FieldByName("Type").SetString(msgType)
+	b, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// filler sets values to deterministic, distinct non-zero values.
+type filler struct {
+	n int
+}
+
+func (f *filler) next() int {
+	f.n++
+	return f.n
+}
+
+func (f *filler) fill(v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timestampType {
+			v.Set(reflect.ValueOf(gosepp.Timestamp{Time: time.Date(2021, 1, 2, 3, 4,
+				f.next(), 0, time.UTC)}))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.PkgPath != "" || tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = strings.ToLower(field.Name)
+			}
+			f.fill(v.Field(i), tag)
+		}
+	case reflect.String:
+		v.SetString(fmt.Sprintf("%s-%d", name, f.next()))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(f.next()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(f.next()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(f.next()) + 0.5)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		f.fill(v.Elem(), name)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		f.fill(s.Index(0), name)
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		f.fill(key, name+"-key")
+		value := reflect.New(v.Type().Elem()).Elem()
+		f.fill(value, name)
+		m.SetMapIndex(key, value)
+		v.Set(m)
+	}
+}
@@ -0,0 +1,255 @@
+// Command genmsgs generates the sepp message structs from the schema.
+//
+//	go run ./internal/genmsgs
+//
+// It reads sepp_messages.schema.json and writes sepp_messages_gen.go:
+// a struct for every definition, and for the message definitions,
+// which embed MsgBase with allOf and fix their type property with
+// const, the MsgType constants and the SeppMsgTypes registry.
+// Definitions marked x-go-external are implemented by hand. Go names
+// default to the camel case of the json names, x-go-name overrides
+// them; x-go-type overrides the Go type derived from the schema.
+// Properties not listed as required are tagged omitempty.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	input  = "sepp_messages.schema.json"
+	output = "sepp_messages_gen.go"
+)
+
+// object is a json object keeping the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *object) get(key string) interface{} {
+	if o == nil {
+		return nil
+	}
+	return o.values[key]
+}
+
+func (o *object) str(key string) string {
+	s, _ := o.get(key).(string)
+	return s
+}
+
+func (o *object) obj(key string) *object {
+	v, _ := o.get(key).(*object)
+	return v
+}
+
+func (o *object) list(key string) []interface{} {
+	l, _ := o.get(key).([]interface{})
+	return l
+}
+
+// decode reads the next value of dec, decoding objects as *object.
+func decode(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := &object{values: make(map[string]interface{})}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			o.keys = append(o.keys, key.(string))
+			o.values[key.(string)] = value
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		var l []interface{}
+		for dec.More() {
+			value, err := decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, value)
+		}
+		_, err := dec.Token()
+		return l, err
+	}
+	return tok, nil
+}
+
+type message struct {
+	constName string
+	msgType   string
+	name      string
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func main() {
+	f, err := os.Open(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	root, err := decode(json.NewDecoder(f))
+	if err != nil && err != io.EOF {
+		log.Fatalf("invalid schema: %s", err)
+	}
+	defs := root.(*object).obj("$defs")
+	if defs == nil {
+		log.Fatal("schema has no $defs")
+	}
+
+	var msgs []message
+	for _, name := range defs.keys {
+		def := defs.obj(name)
+		if def.get("x-go-external") == true || len(def.str("x-go-const")) == 0 {
+			continue
+		}
+		msgType := def.obj("properties").obj("type").str("const")
+		if len(msgType) == 0 {
+			log.Fatalf("%s: message without type const", name)
+		}
+		msgs = append(msgs, message{def.str("x-go-const"), msgType, name})
+	}
+
+	g := &generator{}
+	g.printf("// Code generated by genmsgs from %s. DO NOT EDIT.\n\n", input)
+	g.printf("package gosepp\n\n")
+	g.printf("// Messages types\nconst (\n")
+	for _, m := range msgs {
+		g.printf("%s string = %q\n", m.constName, m.msgType)
+	}
+	g.printf(")\n\n")
+	g.printf("// SeppMsgTypes defines a mapping of message types\n")
+	g.printf("// and an interface function which create a messages\n")
+	g.printf("// adhering to the MsgInterface.\n")
+	g.printf("var SeppMsgTypes = map[string]func() MsgInterface{\n")
+	for _, m := range msgs {
+		g.printf("%s: func() MsgInterface { return &%s{} },\n", m.constName, m.name)
+	}
+	g.printf("}\n")
+	for _, name := range defs.keys {
+		def := defs.obj(name)
+		if def.get("x-go-external") == true {
+			continue
+		}
+		g.genStruct(name, def)
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("invalid generated code: %s\n%s", err, g.buf.Bytes())
+	}
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) genStruct(name string, def *object) {
+	g.printf("\n")
+	for _, line := range strings.Split(def.str("description"), "\n") {
+		g.printf("// %s\n", line)
+	}
+	g.printf("type %s struct {\n", name)
+	for _, embedded := range def.list("allOf") {
+		g.printf("%s\n", refName(embedded.(*object).str("$ref")))
+	}
+	required := map[string]bool{}
+	for _, r := range def.list("required") {
+		required[r.(string)] = true
+	}
+	props := def.obj("properties")
+	for _, key := range props.keys {
+		prop := props.obj(key)
+		if prop.get("const") != nil {
+			// the discriminator is set by the embedded MsgBase.
+			continue
+		}
+		if desc := prop.str("description"); len(desc) > 0 {
+			g.printf("// %s\n", desc)
+		}
+		goName := prop.str("x-go-name")
+		if len(goName) == 0 {
+			goName = exportName(key)
+		}
+		tag := key
+		if !required[key] {
+			tag += ",omitempty"
+		}
+		g.printf("%s %s `json:%q`\n", goName, goType(name+"."+key, prop), tag)
+	}
+	g.printf("}\n")
+}
+
+// goType returns the Go type of values matching schema.
+func goType(path string, schema *object) string {
+	if t := schema.str("x-go-type"); len(t) > 0 {
+		return t
+	}
+	if ref := schema.str("$ref"); len(ref) > 0 {
+		return refName(ref)
+	}
+	switch schema.str("type") {
+	case "string":
+		if schema.str("contentEncoding") == "base64" {
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "boolean":
+		return "bool"
+	case "number":
+		return "float64"
+	case "array":
+		return "[]" + goType(path, schema.obj("items"))
+	case "object":
+		if values := schema.obj("additionalProperties"); values != nil {
+			return "map[string]" + goType(path, values)
+		}
+	}
+	log.Fatalf("%s: no Go type for schema, set x-go-type", path)
+	return ""
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/$defs/")
+}
+
+// exportName returns the camel case of the snake case json name s.
+func exportName(s string) string {
+	var name string
+	for _, part := range strings.Split(s, "_") {
+		switch {
+		case part == "id":
+			name += "ID"
+		case len(part) > 0:
+			name += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return name
+}
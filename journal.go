@@ -0,0 +1,106 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Journal is an append-only log of signaled messages, written as one
+// json encoded Envelope per line. Attach it to a GoSepp with
+// WithJournal to audit exactly what was signaled.
+type Journal struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewJournal returns a Journal writing to w.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenJournal opens or creates the journal file at path for appending.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJournal(f), nil
+}
+
+// Record appends the json encoded message data with its direction and
+// the current time. Data which isn't valid json is kept in Raw, as
+// malformed frames matter most when auditing.
+func (j *Journal) Record(dir Direction, data []byte) error {
+	entry := Envelope{
+		Version:   EnvelopeVersion,
+		Direction: dir,
+		Timestamp: time.Now().UTC(),
+	}
+	if !json.Valid(data) {
+		entry.Raw = data
+	} else if msg, err := DecodeMsg(data); err == nil {
+		entry.Msg = json.RawMessage(data)
+		entry.Type = msg.GetType()
+		entry.CallID = string(CallIDOf(msg))
+	} else {
+		// keep unknown messages, with the type if present.
+		entry.Msg = json.RawMessage(data)
+		var msgBase MsgBase
+		json.Unmarshal(data, &msgBase)
+		entry.Type = msgBase.Type
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.enc.Encode(&entry)
+}
+
+// Close closes the underlying writer, if it is an io.Closer.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if c, ok := j.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// JournalReader reads the entries written by a Journal.
+type JournalReader struct {
+	dec *json.Decoder
+}
+
+// NewJournalReader returns a reader for the journal in r.
+func NewJournalReader(r io.Reader) *JournalReader {
+	return &JournalReader{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next entry, or io.EOF at the end of the journal.
+func (r *JournalReader) Next() (*Envelope, error) {
+	var entry Envelope
+	if err := r.dec.Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CallEntries returns all entries of the call callID.
+func (r *JournalReader) CallEntries(callID CallID) ([]*Envelope, error) {
+	var entries []*Envelope
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		if entry.CallID == string(callID) {
+			entries = append(entries, entry)
+		}
+	}
+}
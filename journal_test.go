@@ -0,0 +1,68 @@
+package gosepp
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJournalRecordsCall(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	journal := NewJournal(&buf)
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, WithSeppOptions(WithJournal(journal)))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if err := session.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	call.Close()
+
+	entries, err := NewJournalReader(&buf).CallEntries(session.ID())
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	// the reply may be recorded before the written request, so
+	// don't rely on the order.
+	expected := map[string]Direction{
+		MsgTypeCallAccepted:   DirectionIn,
+		MsgTypeCallTerminate:  DirectionOut,
+		MsgTypeCallTerminated: DirectionIn,
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for _, entry := range entries {
+		if dir, ok := expected[entry.Type]; !ok || dir != entry.Direction {
+			t.Errorf("unexpected entry %s %s", entry.Direction, entry.Type)
+		}
+	}
+}
+
+func TestJournalRecordsInvalidJSON(t *testing.T) {
+	var buf bytes.Buffer
+	journal := NewJournal(&buf)
+	frame := []byte("not json \xff")
+	if err := journal.Record(DirectionIn, frame); err != nil {
+		t.Fatalf("record failed: %s", err)
+	}
+	entry, err := NewJournalReader(&buf).Next()
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	if !bytes.Equal(entry.Raw, frame) || entry.Direction != DirectionIn {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+}
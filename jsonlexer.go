@@ -0,0 +1,458 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxJSONDepth is the nesting limit of encoding/json.
+const maxJSONDepth = 10000
+
+var errJSONFallback = errors.New("can't decode without encoding/json")
+
+// fastUnmarshaler is implemented by the messages with a generated
+// decoder, see decode_gen.go.
+type fastUnmarshaler interface {
+	unmarshalFast(data []byte) error
+}
+
+// unmarshalMsg decodes data into msg, with the generated decoder if msg
+// has one.
+func unmarshalMsg(data []byte, msg MsgInterface) error {
+	if d, ok := msg.(fastUnmarshaler); ok {
+		return d.unmarshalFast(data)
+	}
+	return json.Unmarshal(data, msg)
+}
+
+// jsonLexer reads json for the generated decoders. It only accepts
+// input it decodes exactly like encoding/json and sets err otherwise,
+// e.g. on syntax errors, mismatching types, keys only matching
+// case-insensitively or invalid UTF-8. The decoders then start over
+// with encoding/json, which also produces the errors.
+type jsonLexer struct {
+	data  []byte
+	pos   int
+	depth int
+	err   error
+}
+
+func (l *jsonLexer) fail() {
+	if l.err == nil {
+		l.err = errJSONFallback
+	}
+}
+
+// peek returns the next non-space byte without consuming it.
+func (l *jsonLexer) peek() byte {
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\n', '\r':
+			l.pos++
+		default:
+			return l.data[l.pos]
+		}
+	}
+	l.fail()
+	return 0
+}
+
+// end checks that only space follows the decoded value.
+func (l *jsonLexer) end() {
+	for ; l.err == nil && l.pos < len(l.data); l.pos++ {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\n', '\r':
+		default:
+			l.fail()
+		}
+	}
+}
+
+func (l *jsonLexer) literal(lit string) {
+	if !bytes.HasPrefix(l.data[l.pos:], []byte(lit)) {
+		l.fail()
+		return
+	}
+	l.pos += len(lit)
+}
+
+// null consumes a null, which leaves most values unchanged.
+func (l *jsonLexer) null() bool {
+	if l.err != nil || l.peek() != 'n' {
+		return false
+	}
+	l.literal("null")
+	return l.err == nil
+}
+
+func (l *jsonLexer) begin(delim byte) bool {
+	if l.err != nil || l.peek() != delim {
+		l.fail()
+		return false
+	}
+	l.pos++
+	if l.depth++; l.depth > maxJSONDepth {
+		l.fail()
+		return false
+	}
+	return true
+}
+
+// beginObject consumes the opening brace of an object.
+func (l *jsonLexer) beginObject() bool {
+	return l.begin('{')
+}
+
+// beginArray consumes the opening bracket of an array.
+func (l *jsonLexer) beginArray() bool {
+	return l.begin('[')
+}
+
+// next reports whether another member of the object or array precedes
+// end, consuming the separating comma. first must be true initially.
+func (l *jsonLexer) next(end byte, first *bool) bool {
+	c := l.peek()
+	if l.err != nil {
+		return false
+	}
+	if c == end {
+		l.pos++
+		l.depth--
+		return false
+	}
+	if !*first {
+		if c != ',' {
+			l.fail()
+			return false
+		}
+		l.pos++
+	}
+	*first = false
+	return true
+}
+
+// key reads an object key and the following colon. The result may
+// point into data.
+func (l *jsonLexer) key() []byte {
+	k := l.rawString()
+	if l.err == nil && l.peek() != ':' {
+		l.fail()
+	}
+	if l.err != nil {
+		return nil
+	}
+	l.pos++
+	return k
+}
+
+// unknown skips the value of key k, which didn't match any of fields
+// exactly. Keys possibly matching case-insensitively are left to
+// encoding/json.
+func (l *jsonLexer) unknown(k []byte, fields []string) {
+	for _, c := range k {
+		if c >= utf8.RuneSelf {
+			l.fail()
+			return
+		}
+	}
+	for _, f := range fields {
+		if bytes.EqualFold(k, []byte(f)) {
+			l.fail()
+			return
+		}
+	}
+	l.skip()
+}
+
+// skip consumes the next value, checking its syntax.
+func (l *jsonLexer) skip() {
+	switch c := l.peek(); {
+	case l.err != nil:
+	case c == '{':
+		l.beginObject()
+		for first := true; l.next('}', &first); {
+			l.key()
+			l.skip()
+		}
+	case c == '[':
+		l.beginArray()
+		for first := true; l.next(']', &first); {
+			l.skip()
+		}
+	case c == '"':
+		l.rawString()
+	case c == 't':
+		l.literal("true")
+	case c == 'f':
+		l.literal("false")
+	case c == 'n':
+		l.literal("null")
+	default:
+		l.number()
+	}
+}
+
+// rawString reads a string and returns its unescaped content, which
+// may point into data.
+func (l *jsonLexer) rawString() []byte {
+	if l.err != nil || l.peek() != '"' {
+		l.fail()
+		return nil
+	}
+	start := l.pos + 1
+	escaped, ascii := false, true
+	for i := start; i < len(l.data); i++ {
+		switch c := l.data[i]; {
+		case c == '"':
+			l.pos = i + 1
+			s := l.data[start:i]
+			if !ascii && !utf8.Valid(s) {
+				// encoding/json replaces invalid bytes.
+				l.fail()
+				return nil
+			}
+			if escaped {
+				return l.unescape(s)
+			}
+			return s
+		case c == '\\':
+			escaped = true
+			i++
+		case c < 0x20:
+			l.fail()
+			return nil
+		case c >= utf8.RuneSelf:
+			ascii = false
+		}
+	}
+	l.fail()
+	return nil
+}
+
+func (l *jsonLexer) unescape(s []byte) []byte {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b = append(b, s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			l.fail()
+			return nil
+		}
+		switch s[i] {
+		case '"', '\\', '/':
+			b = append(b, s[i])
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'u':
+			r, ok := hex4(s[i+1:])
+			if !ok {
+				l.fail()
+				return nil
+			}
+			i += 4
+			if utf16.IsSurrogate(r) {
+				// only valid pairs, encoding/json replaces the rest.
+				if len(s) < i+7 || s[i+1] != '\\' || s[i+2] != 'u' {
+					l.fail()
+					return nil
+				}
+				r2, ok := hex4(s[i+3:])
+				if !ok {
+					l.fail()
+					return nil
+				}
+				if r = utf16.DecodeRune(r, r2); r == utf8.RuneError {
+					l.fail()
+					return nil
+				}
+				i += 6
+			}
+			b = utf8.AppendRune(b, r)
+		default:
+			l.fail()
+			return nil
+		}
+	}
+	return b
+}
+
+func hex4(s []byte) (rune, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+	var r rune
+	for _, c := range s[:4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r*16 + rune(c)
+	}
+	return r, true
+}
+
+// number reads a number, checking its syntax.
+func (l *jsonLexer) number() []byte {
+	if l.err != nil {
+		return nil
+	}
+	start, i := l.pos, l.pos
+	digits := func() bool {
+		n := i
+		for i < len(l.data) && '0' <= l.data[i] && l.data[i] <= '9' {
+			i++
+		}
+		return i > n
+	}
+	if i < len(l.data) && l.data[i] == '-' {
+		i++
+	}
+	if i < len(l.data) && l.data[i] == '0' {
+		i++
+	} else if !digits() {
+		l.fail()
+		return nil
+	}
+	if i < len(l.data) && l.data[i] == '.' {
+		i++
+		if !digits() {
+			l.fail()
+			return nil
+		}
+	}
+	if i < len(l.data) && (l.data[i] == 'e' || l.data[i] == 'E') {
+		i++
+		if i < len(l.data) && (l.data[i] == '+' || l.data[i] == '-') {
+			i++
+		}
+		if !digits() {
+			l.fail()
+			return nil
+		}
+	}
+	l.pos = i
+	return l.data[start:i]
+}
+
+// readString decodes a string into p, a null leaves it unchanged.
+func (l *jsonLexer) readString(p *string) {
+	if !l.null() {
+		if s := l.rawString(); l.err == nil {
+			*p = string(s)
+		}
+	}
+}
+
+// readInt decodes an integer into p, a null leaves it unchanged.
+func (l *jsonLexer) readInt(p *int) {
+	var v int64
+	if l.readInteger(&v, strconv.IntSize) {
+		*p = int(v)
+	}
+}
+
+// readInt64 decodes an integer into p, a null leaves it unchanged.
+func (l *jsonLexer) readInt64(p *int64) {
+	l.readInteger(p, 64)
+}
+
+func (l *jsonLexer) readInteger(p *int64, bitSize int) bool {
+	if l.null() || l.err != nil {
+		return false
+	}
+	if c := l.peek(); c != '-' && (c < '0' || c > '9') {
+		l.fail()
+		return false
+	}
+	n := l.number()
+	if l.err != nil {
+		return false
+	}
+	// fractions and exponents are rejected by encoding/json as well.
+	v, err := strconv.ParseInt(string(n), 10, bitSize)
+	if err != nil {
+		l.fail()
+		return false
+	}
+	*p = v
+	return true
+}
+
+// readBool decodes a boolean into p, a null leaves it unchanged.
+func (l *jsonLexer) readBool(p *bool) {
+	if l.err != nil {
+		return
+	}
+	switch l.peek() {
+	case 't':
+		if l.literal("true"); l.err == nil {
+			*p = true
+		}
+	case 'f':
+		if l.literal("false"); l.err == nil {
+			*p = false
+		}
+	case 'n':
+		l.literal("null")
+	default:
+		l.fail()
+	}
+}
+
+// readBytes decodes a base64 string into p, a null clears it.
+func (l *jsonLexer) readBytes(p *[]byte) {
+	if l.null() {
+		*p = nil
+		return
+	}
+	s := l.rawString()
+	if l.err != nil {
+		return
+	}
+	b := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
+	n, err := base64.StdEncoding.Decode(b, s)
+	if err != nil {
+		l.fail()
+		return
+	}
+	*p = b[:n]
+}
+
+// unmarshal decodes the next value into v with encoding/json, for types
+// the generator doesn't handle, e.g. json.Unmarshaler implementations.
+func (l *jsonLexer) unmarshal(v interface{}) {
+	if l.err != nil {
+		return
+	}
+	l.peek()
+	start := l.pos
+	l.skip()
+	if l.err != nil {
+		return
+	}
+	if err := json.Unmarshal(l.data[start:l.pos], v); err != nil {
+		l.fail()
+	}
+}
@@ -0,0 +1,193 @@
+// Package jwt mints the JWTs accepted by sepp servers, so test
+// environments and self-hosted servers can create tokens with the same
+// library they use to connect. Tokens are signed with HS256 or RS256.
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned by Parse for tokens which aren't JWTs.
+	ErrMalformed = errors.New("malformed token")
+	// ErrInvalidSignature is returned by Parse if the signature doesn't
+	// match.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpired is returned by Parse for expired tokens.
+	ErrExpired = errors.New("token expired")
+)
+
+// Claims are the claims of a sepp token.
+type Claims struct {
+	ClientID  string    `json:"client_id"`
+	ConfID    string    `json:"conf_id"`
+	ExpiresAt time.Time `json:"-"`
+	IssuedAt  time.Time `json:"-"`
+}
+
+// claims is the wire format of Claims, with times as unix seconds.
+type claims struct {
+	ClientID  string `json:"client_id"`
+	ConfID    string `json:"conf_id"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+// Key signs and verifies tokens.
+type Key interface {
+	// Alg returns the name of the algorithm in the token header.
+	Alg() string
+	Sign(data []byte) ([]byte, error)
+	Verify(data, sig []byte) error
+}
+
+type hs256 []byte
+
+// HS256 returns a key signing with HMAC-SHA256 and secret.
+func HS256(secret []byte) Key {
+	return hs256(secret)
+}
+
+func (k hs256) Alg() string {
+	return "HS256"
+}
+
+func (k hs256) Sign(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func (k hs256) Verify(data, sig []byte) error {
+	expected, _ := k.Sign(data)
+	if !hmac.Equal(expected, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type rs256 struct {
+	key *rsa.PrivateKey
+}
+
+// RS256 returns a key signing with RSASSA-PKCS1-v1_5 SHA-256 and key.
+func RS256(key *rsa.PrivateKey) Key {
+	return rs256{key: key}
+}
+
+func (k rs256) Alg() string {
+	return "RS256"
+}
+
+func (k rs256) Sign(data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(rand.Reader, k.key, crypto.SHA256, digest[:])
+}
+
+func (k rs256) Verify(data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	if err := rsa.VerifyPKCS1v15(&k.key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// New returns a token for clientID in conference confID, valid for ttl.
+func New(clientID, confID string, ttl time.Duration, key Key) (string, error) {
+	now := time.Now()
+	return Sign(Claims{ClientID: clientID, ConfID: confID,
+		IssuedAt: now, ExpiresAt: now.Add(ttl)}, key)
+}
+
+// Sign returns a token of c signed with key.
+func Sign(c Claims, key Key) (string, error) {
+	header, err := json.Marshal(struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}{key.Alg(), "JWT"})
+	if err != nil {
+		return "", err
+	}
+	wire := claims{ClientID: c.ClientID, ConfID: c.ConfID}
+	if !c.ExpiresAt.IsZero() {
+		wire.ExpiresAt = c.ExpiresAt.Unix()
+	}
+	if !c.IssuedAt.IsZero() {
+		wire.IssuedAt = c.IssuedAt.Unix()
+	}
+	payload, err := json.Marshal(wire)
+	if err != nil {
+		return "", err
+	}
+	signed := encode(header) + "." + encode(payload)
+	sig, err := key.Sign([]byte(signed))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed + "." + encode(sig), nil
+}
+
+// Parse verifies token with key and returns its claims. Expired tokens
+// are rejected with ErrExpired.
+func Parse(token string, key Key) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformed
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decode(parts[0], &header); err != nil {
+		return Claims{}, err
+	}
+	if header.Alg != key.Alg() {
+		return Claims{}, fmt.Errorf("%w: unexpected alg %s", ErrInvalidSignature, header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if err := key.Verify([]byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return Claims{}, err
+	}
+	var wire claims
+	if err := decode(parts[1], &wire); err != nil {
+		return Claims{}, err
+	}
+	c := Claims{ClientID: wire.ClientID, ConfID: wire.ConfID}
+	if wire.ExpiresAt != 0 {
+		c.ExpiresAt = time.Unix(wire.ExpiresAt, 0)
+		if time.Now().After(c.ExpiresAt) {
+			return c, ErrExpired
+		}
+	}
+	if wire.IssuedAt != 0 {
+		c.IssuedAt = time.Unix(wire.IssuedAt, 0)
+	}
+	return c, nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	return nil
+}
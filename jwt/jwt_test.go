@@ -0,0 +1,46 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	for _, key := range []Key{HS256([]byte("secret")), RS256(rsaKey)} {
+		token, err := New("client", "conf", time.Hour, key)
+		if err != nil {
+			t.Fatalf("%s: sign failed: %s", key.Alg(), err)
+		}
+		claims, err := Parse(token, key)
+		if err != nil {
+			t.Fatalf("%s: parse failed: %s", key.Alg(), err)
+		}
+		if claims.ClientID != "client" || claims.ConfID != "conf" ||
+			time.Until(claims.ExpiresAt) <= 0 {
+			t.Fatalf("%s: unexpected claims %#v", key.Alg(), claims)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	key := HS256([]byte("secret"))
+	token, _ := New("client", "conf", time.Hour, HS256([]byte("other")))
+	if _, err := Parse(token, key); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	expired, _ := Sign(Claims{ClientID: "client",
+		ExpiresAt: time.Now().Add(-time.Minute)}, key)
+	if _, err := Parse(expired, key); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	if _, err := Parse("garbage", key); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// KafkaProducer is implemented by an adapter around the kafka client of
+// choice, e.g. a synchronous producer. Produce must only return nil once
+// the record is acknowledged by the brokers, as the KafkaSink relies on
+// it for at-least-once delivery.
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+// KafkaSink streams messages wrapped in an Envelope into a kafka topic.
+// Records are keyed by call-id, so all messages of a call end up in the
+// same partition. Delivery is at-least-once: a failed record is retried
+// until it is acknowledged or the context is done.
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+	backoff  time.Duration
+	logger   Logger
+}
+
+// KafkaOption defines the options interface of the KafkaSink.
+type KafkaOption func(*KafkaSink)
+
+// WithKafkaRetryBackoff sets the delay between delivery attempts.
+func WithKafkaRetryBackoff(backoff time.Duration) KafkaOption {
+	return func(k *KafkaSink) {
+		k.backoff = backoff
+	}
+}
+
+// NewKafkaSink returns a sink producing to topic.
+func NewKafkaSink(producer KafkaProducer, topic string, logger Logger,
+	options ...KafkaOption) *KafkaSink {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	k := &KafkaSink{
+		producer: producer,
+		topic:    topic,
+		backoff:  time.Second,
+		logger:   logger,
+	}
+	for _, opt := range options {
+		opt(k)
+	}
+	return k
+}
+
+// Write produces a single message, retrying until it is acknowledged.
+func (k *KafkaSink) Write(ctx context.Context, msg MsgInterface) error {
+	envelope, err := NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	var key []byte
+	if len(envelope.CallID) > 0 {
+		key = []byte(envelope.CallID)
+	}
+
+	for {
+		err := k.producer.Produce(ctx, k.topic, key, value)
+		if err == nil {
+			return nil
+		}
+		k.logger.Warn("Failed to produce %s [%s]. Retrying.", msg.GetType(), err)
+		select {
+		case <-ctx.Done():
+			return ctxError(ctx, "kafka produce")
+		case <-time.After(k.backoff):
+		}
+	}
+}
+
+// Run produces all messages received on ch until ch is closed or ctx
+// is done.
+func (k *KafkaSink) Run(ctx context.Context, ch <-chan MsgInterface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := k.Write(ctx, msg); err != nil {
+				k.logger.Warn("Failed to produce %s [%s].", msg.GetType(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type kafkaRecord struct {
+	topic      string
+	key, value []byte
+}
+
+// fakeKafka fails the first failures produce calls.
+type fakeKafka struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	records  []kafkaRecord
+}
+
+func (p *fakeKafka) Produce(ctx context.Context, topic string, key, value []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	if p.attempts <= p.failures {
+		return errors.New("broker unavailable")
+	}
+	p.records = append(p.records, kafkaRecord{topic, key, value})
+	return nil
+}
+
+func TestKafkaSinkRetries(t *testing.T) {
+	producer := &fakeKafka{failures: 2}
+	sink := NewKafkaSink(producer, "signaling", nil,
+		WithKafkaRetryBackoff(time.Millisecond))
+
+	msg := &MsgCallTerminated{MsgBase: MsgBase{Type: MsgTypeCallTerminated},
+		Data: MsgCallTerminatedData{CallID: "call"}}
+	if err := sink.Write(context.Background(), msg); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	if producer.attempts != 3 || len(producer.records) != 1 {
+		t.Fatalf("expected delivery on the third attempt, got %d attempts",
+			producer.attempts)
+	}
+	record := producer.records[0]
+	if record.topic != "signaling" || string(record.key) != "call" {
+		t.Fatalf("unexpected record %s %s", record.topic, record.key)
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(record.value, &envelope); err != nil ||
+		envelope.Type != MsgTypeCallTerminated || envelope.CallID != "call" {
+		t.Fatalf("unexpected envelope %s", record.value)
+	}
+}
+
+func TestKafkaSinkGivesUp(t *testing.T) {
+	sink := NewKafkaSink(&fakeKafka{failures: 1 << 30}, "signaling", nil,
+		WithKafkaRetryBackoff(time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := sink.Write(ctx, NewChatMsg("conf", "hi")); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
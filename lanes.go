@@ -0,0 +1,56 @@
+package gosepp
+
+import (
+	"reflect"
+)
+
+// Lane is a priority of the send queue. Messages of the control lane are
+// always sent before queued messages of the bulk lane, so a flood of
+// chat messages can't delay call signaling.
+type Lane int
+
+const (
+	// LaneControl carries call signaling like call_start, sdp_update
+	// and call_terminate.
+	LaneControl Lane = iota
+	// LaneBulk carries chat, file transfer and binary frames.
+	LaneBulk
+)
+
+// bulkQueueSize is the capacity of the bulk lane.
+const bulkQueueSize = 64
+
+// bulkMsgTypes are the message types sent on the bulk lane. All other
+// messages are sent on the control lane.
+var bulkMsgTypes = map[string]bool{
+	MsgTypeChat:           true,
+	MsgTypeChatReceipt:    true,
+	MsgTypeTyping:         true,
+	MsgTypeChatHistoryReq: true,
+	MsgTypeFileOffer:      true,
+	MsgTypeFileAccept:     true,
+	MsgTypeFileChunk:      true,
+	MsgTypeFileComplete:   true,
+}
+
+// laneOf returns the lane msg is sent on.
+func laneOf(msg interface{}) Lane {
+	v := reflect.Indirect(reflect.ValueOf(msg))
+	if v.Kind() != reflect.Struct {
+		return LaneControl
+	}
+	msgType := v.FieldByName("Type")
+	if msgType.IsValid() && msgType.Kind() == reflect.String &&
+		bulkMsgTypes[msgType.String()] {
+		return LaneBulk
+	}
+	return LaneControl
+}
+
+// QueueDepth returns the number of frames queued on lane.
+func (rtm *GoSepp) QueueDepth(lane Lane) int {
+	if lane == LaneBulk {
+		return len(rtm.bulkCh)
+	}
+	return len(rtm.sendCh)
+}
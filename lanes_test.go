@@ -0,0 +1,21 @@
+package gosepp
+
+import "testing"
+
+func TestLaneOf(t *testing.T) {
+	tests := []struct {
+		msg  interface{}
+		lane Lane
+	}{
+		{NewChatMsg("conf", "hi"), LaneBulk},
+		{MsgTyping{MsgBase: MsgBase{Type: MsgTypeTyping}}, LaneBulk},
+		{NewCallTerminate("call"), LaneControl},
+		{NewSdpUpdate("call", Sdp{SdpType: "offer", Sdp: "sdp"}), LaneControl},
+		{"not a message", LaneControl},
+	}
+	for _, test := range tests {
+		if lane := laneOf(test.msg); lane != test.lane {
+			t.Errorf("%T: expected lane %d, got %d", test.msg, test.lane, lane)
+		}
+	}
+}
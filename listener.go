@@ -0,0 +1,104 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultAcceptTimeout limits the wait for AcceptCh to be read.
+const defaultAcceptTimeout = 10 * time.Second
+
+// Listener accepts sepp connections from clients. It implements
+// http.Handler and delivers every accepted connection as GoSepp,
+// so the server side can use the same messaging interface as
+// the client side.
+type Listener struct {
+	upgrader websocket.Upgrader
+	acceptCh chan *GoSepp
+	logger   Logger
+	options  []GoSeppOption
+	// acceptTimeout limits the wait for AcceptCh to be read.
+	acceptTimeout time.Duration
+	closed        chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewListener returns a new Listener. Mount it on a http.ServeMux
+// to accept connections. The options are applied to every accepted
+// connection.
+func NewListener(logger Logger, options ...GoSeppOption) *Listener {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	return &Listener{
+		acceptCh:      make(chan *GoSepp),
+		logger:        logger,
+		options:       options,
+		acceptTimeout: defaultAcceptTimeout,
+		closed:        make(chan struct{}),
+	}
+}
+
+// SetAcceptTimeout sets how long a connection waits for AcceptCh to be
+// read before it's closed. Defaults to 10 seconds.
+func (l *Listener) SetAcceptTimeout(timeout time.Duration) {
+	l.acceptTimeout = timeout
+}
+
+// Close stops accepting connections. Connections waiting to be
+// delivered on AcceptCh are closed.
+func (l *Listener) Close() {
+	l.closeOnce.Do(func() { close(l.closed) })
+}
+
+// AcceptCh get the channel where accepted connections are delivered.
+// The receive channel of an accepted GoSepp is closed once the client
+// disconnects. Stop must be called nevertheless.
+func (l *Listener) AcceptCh() <-chan *GoSepp {
+	return l.acceptCh
+}
+
+// ServeHTTP upgrades the request to a websocket connection.
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		l.logger.Warn("Failed to upgrade connection from %s [%s].", r.RemoteAddr, err)
+		return
+	}
+	// the request context isn't canceled once hijacked, so the wait is
+	// bounded by the accept timeout.
+	if err := l.accept(r.Context(), newAcceptedGoSepp(conn, l.logger, l.options...)); err != nil {
+		l.logger.Warn("Dropping connection from %s [%s].", r.RemoteAddr, err)
+	}
+}
+
+// ServeConn delivers a connection established by other means, e.g. a
+// stream wrapped with NewStreamConn, as accepted GoSepp.
+func (l *Listener) ServeConn(ctx context.Context, conn Conn) error {
+	return l.accept(ctx, newAcceptedGoSepp(conn, l.logger, l.options...))
+}
+
+// accept delivers sepp on AcceptCh. It's stopped if not read before ctx
+// is done, the listener is closed or the accept timeout expired.
+func (l *Listener) accept(ctx context.Context, sepp *GoSepp) error {
+	timer := time.NewTimer(l.acceptTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case l.acceptCh <- sepp:
+		return nil
+	case <-ctx.Done():
+		err = ctxError(ctx, "serve connection")
+	case <-l.closed:
+		err = ErrNotRunning
+	case <-timer.C:
+		err = fmt.Errorf("%w: connection not accepted", ErrTimeout)
+	}
+	sepp.Stop()
+	return err
+}
@@ -0,0 +1,56 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestListenerAcceptTimeout(t *testing.T) {
+	listener := NewListener(nil)
+	listener.SetAcceptTimeout(100 * time.Millisecond)
+	srv := httptest.NewServer(listener)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %s", err)
+	}
+	defer conn.Close()
+	// nobody reads AcceptCh, so the connection is closed.
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Fatalf("connection not closed")
+			}
+			return
+		}
+	}
+}
+
+func TestListenerClose(t *testing.T) {
+	listener := NewListener(nil)
+	client, server := net.Pipe()
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- listener.ServeConn(context.Background(), NewStreamConn(server)) }()
+	listener.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrNotRunning) {
+			t.Fatalf("expected ErrNotRunning, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ServeConn didn't return")
+	}
+}
@@ -35,16 +35,8 @@ func TestSeppLive(t *testing.T) {
 		log.Fatalf("Failed to connect")
 	}
 
-	if err := sepp.SendMsg(MsgCallStart{
-		MsgBase: MsgBase{
-			Type: MsgTypeCallStart,
-			From: clientID,
-			To:   confID,
-		},
-		Data: MsgCallStartData{
-			Sdp:         Sdp{SdpType: "offer", Sdp: ""},
-			DisplayName: clientID},
-	}); err != nil {
+	if err := sepp.SendMsg(NewCallStart(ClientID(clientID), ConfID(confID),
+		Sdp{SdpType: "offer", Sdp: ""}, clientID)); err != nil {
 		fmt.Println("failed to send message:", err)
 		return
 	}
@@ -0,0 +1,70 @@
+package gosepp
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// FieldLogger is a Logger supporting bound fields, e.g. an adapter of a
+// structured logger. Other loggers get bound fields prefixed to every
+// message.
+type FieldLogger interface {
+	Logger
+	With(key, value string) Logger
+}
+
+// connIDs numbers the GoSepp instances for the conn_id log field.
+var connIDs uint64
+
+func nextConnID() string {
+	return fmt.Sprintf("c%d", atomic.AddUint64(&connIDs, 1))
+}
+
+// WithLogField returns logger with key bound to value. Calls, sessions
+// and connections bind conf_id, client_id, call_id and conn_id.
+func WithLogField(logger Logger, key, value string) Logger {
+	switch l := logger.(type) {
+	case nil:
+		return &silentLogger{}
+	case *silentLogger:
+		return l
+	case FieldLogger:
+		return l.With(key, value)
+	case *prefixLogger:
+		return &prefixLogger{logger: l.logger, fields: append(
+			l.fields[:len(l.fields):len(l.fields)], key+"="+value)}
+	}
+	return &prefixLogger{logger: logger, fields: []string{key + "=" + value}}
+}
+
+// prefixLogger prefixes messages with its fields.
+type prefixLogger struct {
+	logger Logger
+	fields []string
+}
+
+func (l *prefixLogger) format(format string) string {
+	prefix := strings.ReplaceAll(strings.Join(l.fields, " "), "%", "%%")
+	return "[" + prefix + "] " + format
+}
+
+func (l *prefixLogger) Error(format string, v ...interface{}) {
+	l.logger.Error(l.format(format), v...)
+}
+
+func (l *prefixLogger) Warn(format string, v ...interface{}) {
+	l.logger.Warn(l.format(format), v...)
+}
+
+func (l *prefixLogger) Info(format string, v ...interface{}) {
+	l.logger.Info(l.format(format), v...)
+}
+
+func (l *prefixLogger) Debug(format string, v ...interface{}) {
+	l.logger.Debug(l.format(format), v...)
+}
+
+func (l *prefixLogger) Trace(format string, v ...interface{}) {
+	l.logger.Trace(l.format(format), v...)
+}
@@ -0,0 +1,25 @@
+package gosepp
+
+import (
+	"fmt"
+	"testing"
+)
+
+type recordingLogger struct {
+	silentLogger
+	lines []string
+}
+
+func (l *recordingLogger) Info(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestWithLogField(t *testing.T) {
+	rec := &recordingLogger{}
+	logger := WithLogField(WithLogField(rec, "conf_id", "conf"), "call_id", "100%")
+	WithLogField(logger, "unused", "x")
+	logger.Info("Call %s.", "started")
+	if len(rec.lines) != 1 || rec.lines[0] != "[conf_id=conf call_id=100%] Call started." {
+		t.Fatalf("unexpected lines %q", rec.lines)
+	}
+}
@@ -0,0 +1,40 @@
+package gosepp
+
+import (
+	"fmt"
+)
+
+// readLimiter is implemented by connections which can limit the size of
+// received frames, like *websocket.Conn.
+type readLimiter interface {
+	SetReadLimit(limit int64)
+}
+
+// WithMaxMessageSize rejects messages larger than n bytes. Outgoing
+// messages exceeding the limit are not queued and SendMsg returns
+// ErrMessageTooLarge. Received frames exceeding it close the connection,
+// or are dropped if the transport can't limit reads.
+func WithMaxMessageSize(n int) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.maxMessageSize = n
+	}
+}
+
+// checkSize returns ErrMessageTooLarge if data exceeds the limit.
+func (rtm *GoSepp) checkSize(data []byte) error {
+	if rtm.maxMessageSize > 0 && len(data) > rtm.maxMessageSize {
+		return fmt.Errorf("%w: %d bytes, limit is %d", ErrMessageTooLarge,
+			len(data), rtm.maxMessageSize)
+	}
+	return nil
+}
+
+// limitReads applies the size limit to received frames of conn.
+func (rtm *GoSepp) limitReads(conn Conn) {
+	if rtm.maxMessageSize <= 0 {
+		return
+	}
+	if l, ok := conn.(readLimiter); ok {
+		l.SetReadLimit(int64(rtm.maxMessageSize))
+	}
+}
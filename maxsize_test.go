@@ -0,0 +1,29 @@
+package gosepp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxMessageSize(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for c.read() != nil {
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithMaxMessageSize(1024))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	err = sepp.SendMsg(NewChatMsg("conf", strings.Repeat("x", 2048)))
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if err := sepp.SendMsg(NewChatMsg("conf", "hi")); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+}
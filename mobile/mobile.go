@@ -0,0 +1,162 @@
+// Package mobile is a simplified, callback based wrapper of gosepp for
+// gomobile bind. It only uses types supported by gomobile: no channels,
+// no maps, and complex data is passed as json encoded strings.
+//
+//	gomobile bind -target=android github.com/eyeson-team/gosepp/v3/mobile
+package mobile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+// Listener receives the events of a call. It is implemented on the
+// platform side.
+type Listener interface {
+	OnTerminated()
+	OnSdpUpdate(sdpType, sdp string)
+	// OnMemberlist receives the json encoded memberlist data.
+	OnMemberlist(data string)
+	// OnSourceUpdate receives the json encoded source update data.
+	OnSourceUpdate(data string)
+}
+
+// Logger receives log lines. level is one of error, warn, info, debug
+// and trace.
+type Logger interface {
+	Log(level, msg string)
+}
+
+// Call wraps a gosepp call.
+type Call struct {
+	call    *gosepp.Call
+	session *gosepp.CallSession
+}
+
+// NewCall creates a call to the conference confID. logger may be nil.
+func NewCall(endpoint, authToken, clientID, confID string,
+	listener Listener, logger Logger) (*Call, error) {
+	var l gosepp.Logger
+	if logger != nil {
+		l = &loggerAdapter{logger}
+	}
+	call, err := gosepp.NewCall(&gosepp.CallInfo{
+		SigEndpoint: endpoint,
+		AuthToken:   authToken,
+		ClientID:    gosepp.ClientID(clientID),
+		ConfID:      gosepp.ConfID(confID),
+	}, l)
+	if err != nil {
+		return nil, err
+	}
+
+	if listener != nil {
+		call.SetTerminatedHandler(listener.OnTerminated)
+		call.SetSDPUpdateHandler(func(sdp gosepp.Sdp) {
+			listener.OnSdpUpdate(sdp.SdpType, sdp.Sdp)
+		})
+		call.SetMemberlistHandler(func(data gosepp.MsgMemberlistData) {
+			listener.OnMemberlist(encode(data))
+		})
+		call.SetSourceUpdateHandler(func(data gosepp.MsgSourceUpdateData) {
+			listener.OnSourceUpdate(encode(data))
+		})
+	}
+	return &Call{call: call}, nil
+}
+
+// Start the call with the sdp offer and returns the sdp answer. A
+// timeoutMillis of 0 or less waits without timeout.
+func (c *Call) Start(sdpType, sdp, displayName string, timeoutMillis int64) (string, error) {
+	ctx, cancel := timeoutCtx(timeoutMillis)
+	defer cancel()
+	session, err := c.call.StartSession(ctx,
+		gosepp.Sdp{SdpType: sdpType, Sdp: sdp}, displayName)
+	if err != nil {
+		return "", err
+	}
+	c.session = session
+	return session.RemoteSdp().Sdp, nil
+}
+
+// CallID returns the id of the started call.
+func (c *Call) CallID() string {
+	if c.session == nil {
+		return ""
+	}
+	return string(c.session.ID())
+}
+
+// UpdateSDP sends an sdp update to the remote end.
+func (c *Call) UpdateSDP(sdpType, sdp string) error {
+	return c.call.UpdateSDP(context.Background(),
+		gosepp.Sdp{SdpType: sdpType, Sdp: sdp})
+}
+
+// TurnOffVideo mutes or unmutes video.
+func (c *Call) TurnOffVideo(off bool) error {
+	return c.call.TurnOffVideo(context.Background(), off)
+}
+
+// Hold puts the call on hold.
+func (c *Call) Hold() error {
+	return c.call.Hold(context.Background())
+}
+
+// Unhold resumes the call.
+func (c *Call) Unhold() error {
+	return c.call.Unhold(context.Background())
+}
+
+// Terminate the call and waits for the confirmation. A timeoutMillis
+// of 0 or less waits without timeout.
+func (c *Call) Terminate(timeoutMillis int64) error {
+	ctx, cancel := timeoutCtx(timeoutMillis)
+	defer cancel()
+	return c.call.Terminate(ctx)
+}
+
+// Close the signaling connection without terminating the call.
+func (c *Call) Close() {
+	c.call.Close()
+}
+
+func timeoutCtx(timeoutMillis int64) (context.Context, context.CancelFunc) {
+	if timeoutMillis <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(),
+		time.Duration(timeoutMillis)*time.Millisecond)
+}
+
+func encode(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+type loggerAdapter struct {
+	l Logger
+}
+
+func (la *loggerAdapter) Error(format string, v ...interface{}) {
+	la.l.Log("error", fmt.Sprintf(format, v...))
+}
+func (la *loggerAdapter) Warn(format string, v ...interface{}) {
+	la.l.Log("warn", fmt.Sprintf(format, v...))
+}
+func (la *loggerAdapter) Info(format string, v ...interface{}) {
+	la.l.Log("info", fmt.Sprintf(format, v...))
+}
+func (la *loggerAdapter) Debug(format string, v ...interface{}) {
+	la.l.Log("debug", fmt.Sprintf(format, v...))
+}
+func (la *loggerAdapter) Trace(format string, v ...interface{}) {
+	la.l.Log("trace", fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,121 @@
+package mobile
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+	"github.com/gorilla/websocket"
+)
+
+// fakeServer accepts calls, announces a member and confirms terminates.
+func fakeServer() *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			msg, err := gosepp.DecodeMsg(data)
+			if err != nil {
+				continue
+			}
+			switch m := msg.(type) {
+			case *gosepp.MsgCallStart:
+				conn.WriteJSON(gosepp.MsgCallAccepted{
+					MsgBase: gosepp.MsgBase{Type: gosepp.MsgTypeCallAccepted,
+						From: m.To, To: m.From},
+					Data: gosepp.MsgCallAcceptedData{CallID: "call",
+						Sdp: gosepp.Sdp{SdpType: "answer", Sdp: "answer-sdp"}},
+				})
+				conn.WriteJSON(gosepp.MsgMemberlist{
+					MsgBase: gosepp.MsgBase{Type: gosepp.MsgTypeMemberlist,
+						From: m.To, To: m.From},
+					Data: gosepp.MsgMemberlistData{CallID: "call", Count: 1},
+				})
+			case *gosepp.MsgCallTerminate:
+				conn.WriteJSON(gosepp.MsgCallTerminated{
+					MsgBase: gosepp.MsgBase{Type: gosepp.MsgTypeCallTerminated,
+						From: m.To, To: m.From},
+					Data: gosepp.MsgCallTerminatedData{CallID: m.Data.CallID},
+				})
+			}
+		}
+	}))
+}
+
+type fakeListener struct {
+	terminated chan struct{}
+	memberlist chan string
+}
+
+func (l *fakeListener) OnTerminated()                   { close(l.terminated) }
+func (l *fakeListener) OnSdpUpdate(sdpType, sdp string) {}
+func (l *fakeListener) OnMemberlist(data string)        { l.memberlist <- data }
+func (l *fakeListener) OnSourceUpdate(data string)      {}
+
+func TestCall(t *testing.T) {
+	srv := fakeServer()
+	defer srv.Close()
+
+	listener := &fakeListener{terminated: make(chan struct{}),
+		memberlist: make(chan string, 1)}
+	call, err := NewCall("ws"+strings.TrimPrefix(srv.URL, "http"), "", "client",
+		"conf", listener, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	// no timeout, instead of an already expired context.
+	answer, err := call.Start("offer", "offer-sdp", "bot", 0)
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if answer != "answer-sdp" || call.CallID() != "call" {
+		t.Fatalf("unexpected answer %q of call %q", answer, call.CallID())
+	}
+	select {
+	case data := <-listener.memberlist:
+		var memberlist gosepp.MsgMemberlistData
+		if err := json.Unmarshal([]byte(data), &memberlist); err != nil ||
+			memberlist.Count != 1 {
+			t.Fatalf("unexpected memberlist %q %v", data, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for memberlist")
+	}
+	if err := call.Terminate(5000); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	select {
+	case <-listener.terminated:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("terminated not reported")
+	}
+}
+
+func TestTimeoutCtx(t *testing.T) {
+	for _, millis := range []int64{0, -1} {
+		ctx, cancel := timeoutCtx(millis)
+		if _, ok := ctx.Deadline(); ok || ctx.Err() != nil {
+			t.Fatalf("expected no timeout for %d", millis)
+		}
+		cancel()
+	}
+	ctx, cancel := timeoutCtx(1000)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatalf("expected deadline")
+	}
+}
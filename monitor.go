@@ -0,0 +1,180 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+)
+
+// monitorEvents are the message types a Monitor subscribes to.
+var monitorEvents = []string{MsgTypeMemberlist, MsgTypeSourceUpdate,
+	MsgTypeRecording, MsgTypeChat}
+
+// Monitor follows the events of a conference without taking part in it,
+// for dashboards and analytics collectors which must not appear as
+// participants. It never sends call_start.
+type Monitor struct {
+	sepp     *GoSepp
+	confID   ConfID
+	clientID ClientID
+	logger   Logger
+
+	memberlistHandler   func(MsgMemberlistData)
+	sourceUpdateHandler func(MsgSourceUpdateData)
+	recordingHandler    func(MsgRecordingData)
+	chatHandler         func(MsgChatData)
+	eventHandler        func(MsgInterface)
+
+	events         []string
+	removeListener func()
+	// done is closed once dispatch stopped.
+	done chan struct{}
+}
+
+// NewMonitor returns a monitor of the conference of callInfo. Like
+// NewCall, it honors CallInfoTLS, CallInfoHeaders and CallInfoEndpoints.
+func NewMonitor(callInfo CallInfoInterface, logger Logger,
+	options ...GoSeppOption) (*Monitor, error) {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	m := &Monitor{
+		confID:   ConfID(callInfo.GetConfID()),
+		clientID: ClientID(callInfo.GetClientID()),
+		logger:   logger,
+		events:   monitorEvents,
+		done:     make(chan struct{}),
+	}
+	if err := m.clientID.Validate(); err != nil {
+		return nil, fmt.Errorf("client-id: %w", err)
+	}
+	if err := m.confID.Validate(); err != nil {
+		return nil, fmt.Errorf("conf-id: %w", err)
+	}
+	tlsConfig, seppOptions, fallbacks, err := connConfig(callInfo, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(fallbacks) > 0 {
+		seppOptions = append(seppOptions, WithEndpoints(fallbacks...))
+	}
+	sepp, err := NewGoSepp(callInfo.GetSigEndpoint(), callInfo.GetAuthToken(),
+		tlsConfig, logger, append(seppOptions, options...)...)
+	if err != nil {
+		return nil, err
+	}
+	m.sepp = sepp
+	return m, nil
+}
+
+// SetMemberlistHandler sets the handler called on memberlist updates.
+func (m *Monitor) SetMemberlistHandler(handler func(MsgMemberlistData)) {
+	m.memberlistHandler = handler
+}
+
+// SetSourceUpdateHandler sets the handler called on source updates.
+func (m *Monitor) SetSourceUpdateHandler(handler func(MsgSourceUpdateData)) {
+	m.sourceUpdateHandler = handler
+}
+
+// SetRecordingHandler sets the handler called on recording updates.
+func (m *Monitor) SetRecordingHandler(handler func(MsgRecordingData)) {
+	m.recordingHandler = handler
+}
+
+// SetChatHandler sets the handler called on chat messages.
+func (m *Monitor) SetChatHandler(handler func(MsgChatData)) {
+	m.chatHandler = handler
+}
+
+// SetEventHandler sets a handler called with every received message,
+// before the typed handlers.
+func (m *Monitor) SetEventHandler(handler func(MsgInterface)) {
+	m.eventHandler = handler
+}
+
+// SetEvents sets the message types to subscribe to. Defaults to
+// memberlist, source_update, recording and chat. An empty list
+// subscribes to all events of the conference. Must be set before Start.
+func (m *Monitor) SetEvents(events []string) {
+	m.events = events
+}
+
+// Start waits for the connection and subscribes to the events of the
+// conference. The subscription is renewed after reconnects. Handlers
+// must be set before.
+func (m *Monitor) Start(ctx context.Context) error {
+	select {
+	case connected, ok := <-m.sepp.ConnectStatusCh():
+		if !ok || !connected {
+			return ErrConnectFailed
+		}
+	case <-ctx.Done():
+		return ctxError(ctx, "wait for connect")
+	}
+	m.removeListener = m.sepp.addConnectListener(func(connected bool) {
+		if connected {
+			if err := m.subscribe(); err != nil {
+				m.logger.Warn("Failed to renew monitor subscription [%s].", err)
+			}
+		}
+	})
+	if err := m.subscribe(); err != nil {
+		return err
+	}
+	go m.dispatch()
+	return nil
+}
+
+func (m *Monitor) subscribe() error {
+	if err := m.sepp.SendMsg(MsgMonitor{
+		MsgBase: MsgBase{Type: MsgTypeMonitor, From: string(m.clientID),
+			To: string(m.confID)},
+		Data: MsgMonitorData{Events: m.events},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// dispatch calls the handlers until the monitor is closed.
+func (m *Monitor) dispatch() {
+	defer close(m.done)
+	m.sepp.labelGoroutine("dispatcher")
+	for msg := range m.sepp.RcvCh() {
+		if m.eventHandler != nil {
+			m.eventHandler(msg)
+		}
+		switch msg := msg.(type) {
+		case *MsgMemberlist:
+			if m.memberlistHandler != nil {
+				m.memberlistHandler(msg.Data)
+			}
+		case *MsgSourceUpdate:
+			if m.sourceUpdateHandler != nil {
+				m.sourceUpdateHandler(msg.Data)
+			}
+		case *MsgRecording:
+			if m.recordingHandler != nil {
+				m.recordingHandler(msg.Data)
+			}
+		case *MsgChat:
+			if m.chatHandler != nil {
+				m.chatHandler(msg.Data)
+			}
+		}
+	}
+}
+
+// Done returns a channel which is closed once the started monitor stopped
+// receiving events, as it was closed or the connection was given up.
+func (m *Monitor) Done() <-chan struct{} {
+	return m.done
+}
+
+// Close shuts down the connection to the signaling service.
+func (m *Monitor) Close() {
+	if m.removeListener != nil {
+		m.removeListener()
+	}
+	m.sepp.Stop()
+}
@@ -0,0 +1,85 @@
+package gosepp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		m, ok := c.read().(*MsgMonitor)
+		if !ok {
+			t.Errorf("expected monitor subscription")
+			return
+		}
+		c.write(MsgRecording{
+			MsgBase: MsgBase{Type: MsgTypeRecording, From: m.To, To: m.From},
+			Data:    MsgRecordingData{Active: true},
+		})
+		c.read()
+	})
+	defer srv.Close()
+
+	monitor, err := NewMonitor(&CallInfo{SigEndpoint: srv.URL(),
+		ClientID: "dashboard", ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer monitor.Close()
+	recording := make(chan MsgRecordingData, 1)
+	monitor.SetRecordingHandler(func(data MsgRecordingData) {
+		recording <- data
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := monitor.Start(ctx); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	select {
+	case data := <-recording:
+		if !data.Active {
+			t.Fatalf("unexpected recording %#v", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for recording")
+	}
+
+	monitor.Close()
+	select {
+	case <-monitor.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the monitor to stop")
+	}
+}
+
+func TestMonitorCallConfig(t *testing.T) {
+	routes := make(chan string, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		routes <- c.header.Get("X-Route")
+		c.read()
+	})
+	defer srv.Close()
+
+	monitor, err := NewMonitor(&CallConfig{
+		CallInfo: CallInfo{SigEndpoint: "ws://127.0.0.1:1", ClientID: "dashboard",
+			ConfID: "conf"},
+		Headers:           http.Header{"X-Route": {"blue"}},
+		FallbackEndpoints: []string{srv.URL()},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer monitor.Close()
+
+	select {
+	case route := <-routes:
+		if route != "blue" {
+			t.Fatalf("expected route blue, got %q", route)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the fallback endpoint")
+	}
+}
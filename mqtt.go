@@ -0,0 +1,142 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// MQTTClient is the subset of an MQTT client used by the MQTT transport.
+// Implement it with an adapter around the client of an already
+// established MQTT session.
+type MQTTClient interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error
+	Unsubscribe(ctx context.Context, topic string) error
+}
+
+// MQTTAuthenticator is implemented by MQTT clients which can forward
+// the auth token of the sepp connection, e.g. as MQTT 5 user property.
+type MQTTAuthenticator interface {
+	Authenticate(ctx context.Context, token string) error
+}
+
+// errMQTTAuth is returned by Dial if a token can't be forwarded.
+var errMQTTAuth = errors.New("mqtt client can't forward the auth token")
+
+// MQTTTransport carries sepp messages over MQTT, for devices which
+// already maintain an MQTT session and shouldn't open a second socket.
+// Messages are published to <prefix>/client/<client-id> and received
+// from <prefix>/conf/<conf-id>. The endpoint url is ignored.
+type MQTTTransport struct {
+	client   MQTTClient
+	clientID string
+	confID   string
+	prefix   string
+}
+
+// NewMQTTTransport returns a transport for the client clientID calling
+// into the conference confID. Use it with WithTransport.
+func NewMQTTTransport(client MQTTClient, clientID, confID string) *MQTTTransport {
+	return &MQTTTransport{
+		client:   client,
+		clientID: clientID,
+		confID:   confID,
+		prefix:   "sepp",
+	}
+}
+
+// SetTopicPrefix sets the first level of the topics used.
+func (t *MQTTTransport) SetTopicPrefix(prefix string) {
+	t.prefix = prefix
+}
+
+// PublishTopic returns the topic outgoing messages are published to.
+func (t *MQTTTransport) PublishTopic() string {
+	return t.prefix + "/client/" + t.clientID
+}
+
+// SubscribeTopic returns the topic incoming messages are received from.
+func (t *MQTTTransport) SubscribeTopic() string {
+	return t.prefix + "/conf/" + t.confID
+}
+
+// Dial subscribes to the conference topic. A bearer token in header is
+// passed to the client, which has to implement MQTTAuthenticator.
+func (t *MQTTTransport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (Conn, error) {
+	if token := strings.TrimPrefix(header.Get("Authorization"), "Bearer "); token != "" {
+		auth, ok := t.client.(MQTTAuthenticator)
+		if !ok {
+			return nil, errMQTTAuth
+		}
+		if err := auth.Authenticate(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	c := &mqttConn{
+		transport: t,
+		rcvCh:     make(chan []byte, 16),
+		closed:    make(chan struct{}),
+	}
+	if err := t.client.Subscribe(ctx, t.SubscribeTopic(), c.deliver); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type mqttConn struct {
+	transport *MQTTTransport
+	rcvCh     chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *mqttConn) deliver(payload []byte) {
+	select {
+	case c.rcvCh <- payload:
+	case <-c.closed:
+	}
+}
+
+func (c *mqttConn) ReadMessage() (int, []byte, error) {
+	select {
+	case payload := <-c.rcvCh:
+		return TextMessage, payload, nil
+	case <-c.closed:
+		return 0, nil, ErrConnectionClosed
+	}
+}
+
+func (c *mqttConn) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case TextMessage:
+	case PingMessage, PongMessage:
+		// MQTT has its own keepalive
+		return nil
+	default:
+		return fmt.Errorf("%w: frame type %d over MQTT",
+			ErrUnsupportedMsgType, messageType)
+	}
+	select {
+	case <-c.closed:
+		return ErrConnectionClosed
+	default:
+	}
+	return c.transport.client.Publish(context.Background(),
+		c.transport.PublishTopic(), data)
+}
+
+func (c *mqttConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.transport.client.Unsubscribe(context.Background(),
+			c.transport.SubscribeTopic())
+	})
+	return err
+}
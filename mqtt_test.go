@@ -0,0 +1,121 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// fakeMQTT is an in-memory broker for a single client.
+type fakeMQTT struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	handlers  map[string]func([]byte)
+	token     string
+}
+
+func newFakeMQTT() *fakeMQTT {
+	return &fakeMQTT{
+		published: make(map[string][][]byte),
+		handlers:  make(map[string]func([]byte)),
+	}
+}
+
+func (b *fakeMQTT) Publish(ctx context.Context, topic string, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published[topic] = append(b.published[topic], payload)
+	return nil
+}
+
+func (b *fakeMQTT) Subscribe(ctx context.Context, topic string, handler func([]byte)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = handler
+	return nil
+}
+
+func (b *fakeMQTT) Unsubscribe(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.handlers, topic)
+	return nil
+}
+
+func (b *fakeMQTT) handler(topic string) func([]byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.handlers[topic]
+}
+
+// fakeMQTTAuth additionally forwards auth tokens.
+type fakeMQTTAuth struct {
+	*fakeMQTT
+}
+
+func (b fakeMQTTAuth) Authenticate(ctx context.Context, token string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.token = token
+	return nil
+}
+
+func TestMQTTTransport(t *testing.T) {
+	broker := newFakeMQTT()
+	transport := NewMQTTTransport(broker, "client", "conf")
+	conn, err := transport.Dial(context.Background(), nil, http.Header{})
+	if err != nil {
+		t.Fatalf("dial failed: %s", err)
+	}
+
+	if err := conn.WriteMessage(TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	if got := broker.published["sepp/client/client"]; len(got) != 1 ||
+		string(got[0]) != "hello" {
+		t.Fatalf("unexpected publishes %q", got)
+	}
+	if err := conn.WriteMessage(PingMessage, nil); err != nil {
+		t.Fatalf("ping failed: %s", err)
+	}
+	if err := conn.WriteMessage(BinaryMessage, []byte{1}); !errors.Is(err, ErrUnsupportedMsgType) {
+		t.Fatalf("expected binary frames to fail, got %v", err)
+	}
+
+	go broker.handler("sepp/conf/conf")([]byte("welcome"))
+	mt, data, err := conn.ReadMessage()
+	if err != nil || mt != TextMessage || string(data) != "welcome" {
+		t.Fatalf("unexpected read %d %q %v", mt, data, err)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("close failed: %s", err)
+	}
+	if broker.handler("sepp/conf/conf") != nil {
+		t.Fatalf("still subscribed after close")
+	}
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrConnectionClosed) {
+		t.Fatalf("expected closed connection, got %v", err)
+	}
+}
+
+func TestMQTTTransportAuth(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+
+	transport := NewMQTTTransport(newFakeMQTT(), "client", "conf")
+	if _, err := transport.Dial(context.Background(), nil, header); err == nil {
+		t.Fatalf("expected dial to fail without MQTTAuthenticator")
+	}
+
+	broker := fakeMQTTAuth{newFakeMQTT()}
+	transport = NewMQTTTransport(broker, "client", "conf")
+	if _, err := transport.Dial(context.Background(), nil, header); err != nil {
+		t.Fatalf("dial failed: %s", err)
+	}
+	if broker.token != "secret" {
+		t.Fatalf("token not forwarded, got %q", broker.token)
+	}
+}
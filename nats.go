@@ -0,0 +1,101 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// NATSPublisher is the subset of *nats.Conn used by the NATSBridge, so
+// a *nats.Conn can be passed as is.
+type NATSPublisher interface {
+	Publish(subj string, data []byte) error
+}
+
+// NATSBridge publishes decoded messages to NATS subjects and sends
+// messages received from NATS over the signaling connection, so
+// signaling can be fanned out across services.
+//
+// Messages are published to <prefix>.<conf_id>.<type>, with the prefix
+// defaulting to "sepp".
+type NATSBridge struct {
+	nc     NATSPublisher
+	sepp   *GoSepp
+	confID string
+	prefix string
+	logger Logger
+}
+
+// NATSOption defines the options interface of the NATSBridge.
+type NATSOption func(*NATSBridge)
+
+// WithNATSSubjectPrefix sets the first token of the published subjects.
+func WithNATSSubjectPrefix(prefix string) NATSOption {
+	return func(b *NATSBridge) {
+		b.prefix = prefix
+	}
+}
+
+// NewNATSBridge returns a bridge publishing the messages of the
+// conference confID to nc. Outgoing messages are sent on sepp.
+func NewNATSBridge(nc NATSPublisher, sepp *GoSepp, confID string,
+	logger Logger, options ...NATSOption) *NATSBridge {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	b := &NATSBridge{
+		nc:     nc,
+		sepp:   sepp,
+		confID: confID,
+		prefix: "sepp",
+		logger: logger,
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	return b
+}
+
+// Subject returns the subject msg is published to.
+func (b *NATSBridge) Subject(msg MsgInterface) string {
+	return fmt.Sprintf("%s.%s.%s", b.prefix, b.confID, msg.GetType())
+}
+
+// Publish a single message.
+func (b *NATSBridge) Publish(msg MsgInterface) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return b.nc.Publish(b.Subject(msg), data)
+}
+
+// Run publishes all messages received on ch until ch is closed or ctx
+// is done.
+func (b *NATSBridge) Run(ctx context.Context, ch <-chan MsgInterface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := b.Publish(msg); err != nil {
+				b.logger.Warn("Failed to publish %s [%s].", msg.GetType(), err)
+			}
+		}
+	}
+}
+
+// HandleOutgoing decodes data received from NATS and sends it over the
+// signaling connection. Wire it to a subscription, e.g.
+//
+//	nc.Subscribe("sepp.out", func(m *nats.Msg) { b.HandleOutgoing(m.Data) })
+func (b *NATSBridge) HandleOutgoing(data []byte) error {
+	msg, err := DecodeMsg(data)
+	if err != nil {
+		return err
+	}
+	return b.sepp.SendMsg(msg)
+}
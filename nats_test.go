@@ -0,0 +1,80 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeNATS struct {
+	mu        sync.Mutex
+	published map[string][]byte
+}
+
+func (nc *fakeNATS) Publish(subj string, data []byte) error {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	if nc.published == nil {
+		nc.published = make(map[string][]byte)
+	}
+	nc.published[subj] = data
+	return nil
+}
+
+func TestNATSBridgeRun(t *testing.T) {
+	nc := &fakeNATS{}
+	bridge := NewNATSBridge(nc, nil, "conf", nil, WithNATSSubjectPrefix("sig"))
+
+	ch := make(chan MsgInterface, 2)
+	ch <- NewChatMsg("conf", "hi")
+	ch <- &MsgMemberlist{MsgBase: MsgBase{Type: MsgTypeMemberlist}}
+	close(ch)
+	bridge.Run(context.Background(), ch)
+
+	data, ok := nc.published["sig.conf.chat"]
+	if !ok || len(nc.published) != 2 {
+		t.Fatalf("unexpected subjects %v", nc.published)
+	}
+	var chat MsgChat
+	if err := json.Unmarshal(data, &chat); err != nil || chat.Data.Content != "hi" {
+		t.Fatalf("unexpected payload %s", data)
+	}
+	if _, ok := nc.published["sig.conf.memberlist"]; !ok {
+		t.Fatalf("memberlist not published")
+	}
+}
+
+func TestNATSBridgeHandleOutgoing(t *testing.T) {
+	received := make(chan MsgInterface, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		if msg := c.read(); msg != nil {
+			received <- msg
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+	bridge := NewNATSBridge(&fakeNATS{}, sepp, "conf", nil)
+
+	if err := bridge.HandleOutgoing([]byte("no json")); err == nil {
+		t.Fatalf("expected undecodable data to fail")
+	}
+	if err := bridge.HandleOutgoing([]byte(`{"type":"chat","data":{"content":"out"}}`)); err != nil {
+		t.Fatalf("handle failed: %s", err)
+	}
+	select {
+	case msg := <-received:
+		if chat, ok := msg.(*MsgChat); !ok || chat.Data.Content != "out" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for message")
+	}
+}
@@ -0,0 +1,238 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OutboxEntry is a queued message.
+type OutboxEntry struct {
+	ID   string
+	Data []byte
+}
+
+// Outbox queues messages per client until they are delivered. Server
+// instances accepting clients with a Listener share an Outbox, so a
+// message can be queued on any instance and is delivered by the one
+// the client is connected to.
+type Outbox interface {
+	// Push queues msg for clientID.
+	Push(ctx context.Context, clientID string, msg []byte) error
+	// Pop blocks until a message for clientID is available. Messages
+	// popped but not acknowledged are returned again.
+	Pop(ctx context.Context, clientID string) (OutboxEntry, error)
+	// Ack marks the entry as delivered.
+	Ack(ctx context.Context, clientID string, entryID string) error
+}
+
+// ServeOutbox delivers the messages queued for clientID over sepp
+// until ctx is done or sending fails. Entries are acknowledged once
+// written to the connection, so entries lost by a disconnect are
+// delivered again.
+func ServeOutbox(ctx context.Context, sepp *GoSepp, outbox Outbox,
+	clientID string) error {
+	for {
+		entry, err := outbox.Pop(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		if err := sepp.SendMsgConfirmed(ctx, json.RawMessage(entry.Data)); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		if err := outbox.Ack(ctx, clientID, entry.ID); err != nil {
+			return err
+		}
+	}
+}
+
+// MemoryOutbox is an Outbox for a single server instance. Queued
+// messages don't survive a restart.
+type MemoryOutbox struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+	seq    int
+}
+
+// memoryQueue holds the entries of a client.
+type memoryQueue struct {
+	entries chan OutboxEntry
+	// unacked are popped entries, returned again until acknowledged.
+	unacked []OutboxEntry
+}
+
+// NewMemoryOutbox returns an empty MemoryOutbox.
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{queues: make(map[string]*memoryQueue)}
+}
+
+func (o *MemoryOutbox) queue(clientID string) *memoryQueue {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	q, ok := o.queues[clientID]
+	if !ok {
+		q = &memoryQueue{entries: make(chan OutboxEntry, 64)}
+		o.queues[clientID] = q
+	}
+	return q
+}
+
+// Push queues msg for clientID. It blocks if the queue is full.
+func (o *MemoryOutbox) Push(ctx context.Context, clientID string, msg []byte) error {
+	o.mu.Lock()
+	o.seq++
+	entry := OutboxEntry{ID: fmt.Sprint(o.seq), Data: msg}
+	o.mu.Unlock()
+	select {
+	case o.queue(clientID).entries <- entry:
+		return nil
+	case <-ctx.Done():
+		return ctxError(ctx, "outbox push")
+	}
+}
+
+// Pop returns the oldest unacknowledged entry first, then blocks until
+// a message for clientID is available.
+func (o *MemoryOutbox) Pop(ctx context.Context, clientID string) (OutboxEntry, error) {
+	q := o.queue(clientID)
+	o.mu.Lock()
+	if len(q.unacked) > 0 {
+		entry := q.unacked[0]
+		o.mu.Unlock()
+		return entry, nil
+	}
+	o.mu.Unlock()
+	select {
+	case entry := <-q.entries:
+		o.mu.Lock()
+		q.unacked = append(q.unacked, entry)
+		o.mu.Unlock()
+		return entry, nil
+	case <-ctx.Done():
+		return OutboxEntry{}, ctxError(ctx, "outbox pop")
+	}
+}
+
+// Ack removes the popped entry.
+func (o *MemoryOutbox) Ack(ctx context.Context, clientID string, entryID string) error {
+	q := o.queue(clientID)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i, entry := range q.unacked {
+		if entry.ID == entryID {
+			q.unacked = append(q.unacked[:i], q.unacked[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// RedisStreamEntry is an entry read from a redis stream.
+type RedisStreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+// RedisStreams is the subset of redis stream commands used by the
+// RedisOutbox. Implement it with an adapter around the redis client
+// of choice.
+type RedisStreams interface {
+	// XGroupCreate creates group on stream, creating the stream if it
+	// doesn't exist (MKSTREAM). An existing group is not an error.
+	XGroupCreate(ctx context.Context, stream, group string) error
+	// XAdd appends an entry to stream and returns its id.
+	XAdd(ctx context.Context, stream string, fields map[string]string) (string, error)
+	// XReadGroup reads at most one entry of stream for consumer. If
+	// pending is set, entries delivered before but not acknowledged
+	// are read (id "0"), else new entries (id ">"), blocking up to
+	// block.
+	XReadGroup(ctx context.Context, stream, group, consumer string,
+		pending bool, block time.Duration) ([]RedisStreamEntry, error)
+	// XAck acknowledges the entry id.
+	XAck(ctx context.Context, stream, group, id string) error
+}
+
+// RedisOutbox is an Outbox backed by one redis stream per client. As
+// unacknowledged entries stay pending in the stream, queued messages
+// survive restarts of the server instances.
+type RedisOutbox struct {
+	client RedisStreams
+	prefix string
+	group  string
+	block  time.Duration
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+// NewRedisOutbox returns an outbox storing the queue of a client in
+// the stream <prefix><client-id>.
+func NewRedisOutbox(client RedisStreams, prefix string) *RedisOutbox {
+	return &RedisOutbox{
+		client:  client,
+		prefix:  prefix,
+		group:   "gosepp",
+		block:   5 * time.Second,
+		created: make(map[string]bool),
+	}
+}
+
+func (o *RedisOutbox) stream(ctx context.Context, clientID string) (string, error) {
+	stream := o.prefix + clientID
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.created[stream] {
+		if err := o.client.XGroupCreate(ctx, stream, o.group); err != nil {
+			return "", err
+		}
+		o.created[stream] = true
+	}
+	return stream, nil
+}
+
+// Push queues msg for clientID.
+func (o *RedisOutbox) Push(ctx context.Context, clientID string, msg []byte) error {
+	stream, err := o.stream(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	_, err = o.client.XAdd(ctx, stream, map[string]string{"msg": string(msg)})
+	return err
+}
+
+// Pop returns pending entries first, then blocks for new ones. The
+// client-id is used as consumer name, so whichever instance the client
+// is connected to picks up its pending entries.
+func (o *RedisOutbox) Pop(ctx context.Context, clientID string) (OutboxEntry, error) {
+	stream, err := o.stream(ctx, clientID)
+	if err != nil {
+		return OutboxEntry{}, err
+	}
+	pending := true
+	for {
+		entries, err := o.client.XReadGroup(ctx, stream, o.group, clientID,
+			pending, o.block)
+		if err != nil {
+			return OutboxEntry{}, err
+		}
+		if len(entries) > 0 {
+			return OutboxEntry{ID: entries[0].ID,
+				Data: []byte(entries[0].Fields["msg"])}, nil
+		}
+		if ctx.Err() != nil {
+			return OutboxEntry{}, ctxError(ctx, "outbox pop")
+		}
+		pending = false
+	}
+}
+
+// Ack removes the entry from the pending list.
+func (o *RedisOutbox) Ack(ctx context.Context, clientID string, entryID string) error {
+	stream, err := o.stream(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	return o.client.XAck(ctx, stream, o.group, entryID)
+}
@@ -0,0 +1,231 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis implements the stream commands of a single consumer group
+// in memory.
+type fakeRedis struct {
+	mu      sync.Mutex
+	seq     int
+	streams map[string][]RedisStreamEntry
+	// next is the index of the first entry not yet delivered.
+	next    map[string]int
+	pending map[string][]RedisStreamEntry
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		streams: make(map[string][]RedisStreamEntry),
+		next:    make(map[string]int),
+		pending: make(map[string][]RedisStreamEntry),
+	}
+}
+
+func (r *fakeRedis) XGroupCreate(ctx context.Context, stream, group string) error {
+	return nil
+}
+
+func (r *fakeRedis) XAdd(ctx context.Context, stream string,
+	fields map[string]string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	id := fmt.Sprintf("%d-0", r.seq)
+	r.streams[stream] = append(r.streams[stream], RedisStreamEntry{ID: id, Fields: fields})
+	return id, nil
+}
+
+func (r *fakeRedis) XReadGroup(ctx context.Context, stream, group, consumer string,
+	pending bool, block time.Duration) ([]RedisStreamEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pending {
+		return append([]RedisStreamEntry(nil), r.pending[stream]...), nil
+	}
+	if r.next[stream] == len(r.streams[stream]) {
+		// don't block, Pop loops until ctx is done.
+		r.mu.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-time.After(10 * time.Millisecond):
+		}
+		r.mu.Lock()
+		return nil, nil
+	}
+	entry := r.streams[stream][r.next[stream]]
+	r.next[stream]++
+	r.pending[stream] = append(r.pending[stream], entry)
+	return []RedisStreamEntry{entry}, nil
+}
+
+func (r *fakeRedis) XAck(ctx context.Context, stream, group, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, entry := range r.pending[stream] {
+		if entry.ID == id {
+			r.pending[stream] = append(r.pending[stream][:i], r.pending[stream][i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// testOutboxRedelivery checks that entries are returned until acked.
+func testOutboxRedelivery(t *testing.T, outbox Outbox) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, msg := range []string{"a", "b"} {
+		if err := outbox.Push(ctx, "client", []byte(msg)); err != nil {
+			t.Fatalf("push failed: %s", err)
+		}
+	}
+	first, err := outbox.Pop(ctx, "client")
+	if err != nil || string(first.Data) != "a" {
+		t.Fatalf("unexpected entry %q %v", first.Data, err)
+	}
+	again, err := outbox.Pop(ctx, "client")
+	if err != nil || again.ID != first.ID {
+		t.Fatalf("expected unacked entry again, got %q %v", again.Data, err)
+	}
+	if err := outbox.Ack(ctx, "client", first.ID); err != nil {
+		t.Fatalf("ack failed: %s", err)
+	}
+	second, err := outbox.Pop(ctx, "client")
+	if err != nil || string(second.Data) != "b" {
+		t.Fatalf("unexpected entry %q %v", second.Data, err)
+	}
+	if err := outbox.Ack(ctx, "client", second.ID); err != nil {
+		t.Fatalf("ack failed: %s", err)
+	}
+
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := outbox.Pop(short, "client"); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout on empty outbox, got %v", err)
+	}
+}
+
+func TestMemoryOutbox(t *testing.T) {
+	testOutboxRedelivery(t, NewMemoryOutbox())
+}
+
+func TestRedisOutbox(t *testing.T) {
+	testOutboxRedelivery(t, NewRedisOutbox(newFakeRedis(), "outbox:"))
+}
+
+func TestRedisOutboxSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	outbox := NewRedisOutbox(redis, "outbox:")
+	if err := outbox.Push(ctx, "client", []byte("a")); err != nil {
+		t.Fatalf("push failed: %s", err)
+	}
+	if _, err := outbox.Pop(ctx, "client"); err != nil {
+		t.Fatalf("pop failed: %s", err)
+	}
+
+	// another instance picks up the entry popped but not acked.
+	entry, err := NewRedisOutbox(redis, "outbox:").Pop(ctx, "client")
+	if err != nil || string(entry.Data) != "a" {
+		t.Fatalf("unexpected entry %q %v", entry.Data, err)
+	}
+}
+
+func TestServeOutbox(t *testing.T) {
+	received := make(chan MsgInterface, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for {
+			msg := c.read()
+			if msg == nil {
+				return
+			}
+			received <- msg
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	outbox := NewMemoryOutbox()
+	if err := outbox.Push(ctx, "client", []byte(`{"type":"chat","data":{"content":"hi"}}`)); err != nil {
+		t.Fatalf("push failed: %s", err)
+	}
+	serveCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- ServeOutbox(serveCtx, sepp, outbox, "client") }()
+	select {
+	case msg := <-received:
+		if _, ok := msg.(*MsgChat); !ok {
+			t.Fatalf("unexpected message %T", msg)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for message")
+	}
+	// the entry is acked once the write is confirmed.
+	q := outbox.queue("client")
+	for {
+		outbox.mu.Lock()
+		n := len(q.unacked)
+		outbox.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("delivered entry not acked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	<-done
+}
+
+func TestServeOutboxNotConnected(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	outbox := NewMemoryOutbox()
+	if err := outbox.Push(ctx, "client", []byte(`{"type":"chat"}`)); err != nil {
+		t.Fatalf("push failed: %s", err)
+	}
+	if err := ServeOutbox(ctx, sepp, outbox, "client"); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got %v", err)
+	}
+	if entry, err := outbox.Pop(ctx, "client"); err != nil || entry.ID != "1" {
+		t.Fatalf("undelivered entry lost: %+v %v", entry, err)
+	}
+}
+
+func TestFlushConfirmsDroppedFrames(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	written := make(chan error, 1)
+	sepp.queue.add()
+	sepp.flush([]frame{{messageType: TextMessage, data: []byte("{}"),
+		written: written}}, &seqCounter{})
+	if err := <-written; !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected dropped frame to fail, got %v", err)
+	}
+}
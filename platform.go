@@ -0,0 +1,25 @@
+package gosepp
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// DefaultPlatformInfo returns the platform of the running binary, taken
+// from its build info. It is sent with call_start unless replaced by
+// WithPlatformInfo.
+func DefaultPlatformInfo() PlatformInfo {
+	info := PlatformInfo{Name: "gosepp", OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if bi, ok := debug.ReadBuildInfo(); ok && len(bi.Main.Path) > 0 {
+		info.Name = bi.Main.Path
+		info.Version = bi.Main.Version
+	}
+	return info
+}
+
+// WithPlatformInfo sets the platform sent with call_start.
+func WithPlatformInfo(info PlatformInfo) CallOption {
+	return func(c *Call) {
+		c.platformInfo = info
+	}
+}
@@ -0,0 +1,121 @@
+package gosepp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer kept for reuse, so a single huge
+// frame doesn't pin its memory.
+const maxPooledBuffer = 64 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// readerConn is implemented by connections which can stream a frame,
+// like *websocket.Conn.
+type readerConn interface {
+	NextReader() (messageType int, r io.Reader, err error)
+}
+
+// readMessage reads the next frame into a pooled buffer if conn supports
+// it. The data is only valid until release is called.
+func readMessage(conn Conn) (messageType int, data []byte, release func(), err error) {
+	rc, ok := conn.(readerConn)
+	if !ok {
+		messageType, data, err = conn.ReadMessage()
+		return messageType, data, func() {}, err
+	}
+	messageType, r, err := rc.NextReader()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	release = func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		release()
+		return 0, nil, nil, err
+	}
+	return messageType, buf.Bytes(), release, nil
+}
+
+// msgPools holds a *sync.Pool per message struct type.
+var msgPools sync.Map
+
+func msgPool(t reflect.Type) *sync.Pool {
+	if p, ok := msgPools.Load(t); ok {
+		return p.(*sync.Pool)
+	}
+	p, _ := msgPools.LoadOrStore(t, &sync.Pool{
+		New: func() interface{} { return reflect.New(t).Interface() },
+	})
+	return p.(*sync.Pool)
+}
+
+// WithPooledMessages decodes received messages into pooled structs.
+// Consumers of RcvCh must call ReleaseMsg once they are done with a
+// message, and handlers and subscribers must not keep messages beyond
+// that. Meant for gateways processing thousands of messages per second.
+func WithPooledMessages() GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.pooled = true
+	}
+}
+
+// ReleaseMsg returns a message decoded with WithPooledMessages to its
+// pool. msg must not be used afterwards. Messages which were not
+// pooled may be released as well.
+func ReleaseMsg(msg MsgInterface) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	msgPool(v.Elem().Type()).Put(msg)
+}
+
+type pooledKey struct {
+	version int
+	msgType string
+}
+
+// pooledTypes caches the struct type per version and message type, so
+// the factory isn't called for every message.
+var pooledTypes sync.Map
+
+func pooledType(version int, msgType string, factory func() MsgInterface) reflect.Type {
+	key := pooledKey{version, msgType}
+	if t, ok := pooledTypes.Load(key); ok {
+		return t.(reflect.Type)
+	}
+	t := reflect.TypeOf(factory()).Elem()
+	pooledTypes.Store(key, t)
+	return t
+}
+
+// decodePooled decodes data like DecodeMsgVersion into a pooled struct.
+func decodePooled(data []byte, version int) (MsgInterface, error) {
+	msgType, err := sniffType(data)
+	if err != nil {
+		return nil, err
+	}
+	factory, ok := msgFactory(version, msgType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMsgType, msgType)
+	}
+	msg := msgPool(pooledType(version, msgType, factory)).Get().(MsgInterface)
+	if err := unmarshalMsg(data, msg); err != nil {
+		ReleaseMsg(msg)
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,122 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestReleaseMsgZeroes(t *testing.T) {
+	msg, err := decodePooled(benchMemberlist, 1)
+	if err != nil {
+		t.Fatalf("decode failed: %s", err)
+	}
+	m := msg.(*MsgMemberlist)
+	ReleaseMsg(m)
+	if m.Type != "" || m.Data.CallID != "" || m.Data.Add != nil || m.Data.Del != nil {
+		t.Fatalf("released message not zeroed: %+v", m)
+	}
+
+	// fields absent in the next message must not leak from a reused
+	// struct.
+	msg, err = decodePooled([]byte(`{"type":"memberlist","data":{"add":[{"cid":"d"}]}}`), 1)
+	if err != nil {
+		t.Fatalf("decode failed: %s", err)
+	}
+	m = msg.(*MsgMemberlist)
+	if m.Data.CallID != "" || len(m.Data.Del) != 0 || len(m.Data.Add) != 1 {
+		t.Fatalf("stale fields in pooled message: %+v", m.Data)
+	}
+	ReleaseMsg(m)
+}
+
+func TestReleaseMsgReuses(t *testing.T) {
+	// sync.Pool may drop entries, e.g. with the race detector, so
+	// reuse is only expected eventually.
+	for i := 0; i < 100; i++ {
+		first, err := decodePooled(benchMemberlist, 1)
+		if err != nil {
+			t.Fatalf("decode failed: %s", err)
+		}
+		ReleaseMsg(first)
+		second, err := decodePooled(benchMemberlist, 1)
+		if err != nil {
+			t.Fatalf("decode failed: %s", err)
+		}
+		ReleaseMsg(second)
+		if first == second {
+			return
+		}
+	}
+	t.Fatalf("released messages are never reused")
+}
+
+func TestPooledReceiveKeepsDeliveredData(t *testing.T) {
+	// binary frames beyond the channel capacity are dropped.
+	const count = binaryBufferSize
+	content := strings.Repeat("f", 3000)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		b, _ := json.Marshal(NewChatMsg("conf", content))
+		for i := 0; i < count; i++ {
+			c.write(NewChatMsg("conf", fmt.Sprintf("chat-%d", i)))
+			frame, _ := BinaryFrame{Type: "bin",
+				Payload: []byte(fmt.Sprintf("payload-%d", i))}.encode()
+			c.WriteMessage(websocket.BinaryMessage, frame)
+		}
+		// a fragmented message, read into reused buffers.
+		for i := 0; i < 3; i++ {
+			end := (i + 1) * 1024
+			if i == 2 {
+				end = len(b)
+			}
+			c.write(MsgFragment{MsgBase: MsgBase{Type: MsgTypeFragment},
+				Data: MsgFragmentData{ID: "1", Index: i, Count: 3,
+					Payload: b[i*1024 : end]}})
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithPooledMessages())
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	// keep all messages until the end, so reused buffers would show.
+	var chats []*MsgChat
+	timeout := time.After(5 * time.Second)
+	for len(chats) < count+1 {
+		select {
+		case msg := <-sepp.RcvCh():
+			if chat, ok := msg.(*MsgChat); ok {
+				chats = append(chats, chat)
+			}
+		case <-timeout:
+			t.Fatalf("timeout, received %d messages", len(chats))
+		}
+	}
+	for i, chat := range chats[:count] {
+		if want := fmt.Sprintf("chat-%d", i); chat.Data.Content != want {
+			t.Fatalf("message %d corrupted: %q", i, chat.Data.Content)
+		}
+	}
+	if chats[count].Data.Content != content {
+		t.Fatalf("reassembled message corrupted")
+	}
+	for i := 0; i < count; i++ {
+		frame := <-sepp.BinaryCh()
+		if want := fmt.Sprintf("payload-%d", i); !bytes.Equal(frame.Payload, []byte(want)) {
+			t.Fatalf("binary frame %d corrupted: %q", i, frame.Payload)
+		}
+	}
+	for _, chat := range chats {
+		ReleaseMsg(chat)
+	}
+}
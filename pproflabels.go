@@ -0,0 +1,34 @@
+package gosepp
+
+import (
+	"context"
+	"net/url"
+	"runtime/pprof"
+)
+
+// WithPprofLabels adds the key value pairs to the pprof labels of the
+// goroutines of the connection, next to their role and endpoint. So
+// goroutine dumps tell which connection a goroutine belongs to.
+func WithPprofLabels(keyValues ...string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.pprofLabels = append(rtm.pprofLabels, keyValues...)
+	}
+}
+
+// labelGoroutine sets the pprof labels of the calling goroutine, which
+// acts as role of the connection. Goroutines started by it inherit the
+// labels.
+func (rtm *GoSepp) labelGoroutine(role string) {
+	labels := append([]string{"gosepp", role}, rtm.pprofLabels...)
+	if len(labels)%2 != 0 {
+		labels = labels[:len(labels)-1]
+	}
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(),
+		pprof.Labels(labels...)))
+}
+
+// endpointLabel returns u without user info and query, which may hold
+// credentials.
+func endpointLabel(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
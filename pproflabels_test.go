@@ -0,0 +1,46 @@
+package gosepp
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPprofLabels(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+
+	// the dispatcher may not be scheduled yet.
+	var b strings.Builder
+	for i := 0; i < 50; i++ {
+		b.Reset()
+		pprof.Lookup("goroutine").WriteTo(&b, 1)
+		if strings.Contains(b.String(), `"gosepp":"dispatcher"`) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, role := range []string{"sender", "receiver", "dispatcher"} {
+		if !strings.Contains(b.String(), `"gosepp":"`+role+`"`) {
+			t.Errorf("no goroutine labeled %s", role)
+		}
+	}
+	if !strings.Contains(b.String(), `"conf_id":"conf"`) ||
+		!strings.Contains(b.String(), `"endpoint":"`+srv.URL()) {
+		t.Errorf("missing labels:\n%s", b.String())
+	}
+}
@@ -0,0 +1,267 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PresenceChange reports a client going online or offline in a
+// conference.
+type PresenceChange struct {
+	ConfID   ConfID
+	ClientID ClientID
+	Online   bool
+}
+
+// Presence tracks which clients are online across a set of conferences,
+// e.g. for lobby UIs or routing decisions. Every conference is followed
+// by a Monitor. After a reconnect the members are resynchronized with the
+// next memberlist.
+type Presence struct {
+	logger  Logger
+	options []GoSeppOption
+
+	mu    sync.Mutex
+	rooms map[ConfID]*presenceRoom
+
+	subsMu     sync.Mutex
+	subs       map[*presenceSub]struct{}
+	subsClosed bool
+}
+
+type presenceRoom struct {
+	monitor        *Monitor
+	removeListener func()
+	online         map[ClientID]bool
+	// resync is set after a reconnect, as members may have left in the
+	// meantime.
+	resync bool
+}
+
+type presenceSub struct {
+	ch   chan PresenceChange
+	done chan struct{}
+	once sync.Once
+}
+
+// NewPresence returns a Presence connecting its monitors with options.
+func NewPresence(logger Logger, options ...GoSeppOption) *Presence {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	return &Presence{
+		logger:  logger,
+		options: options,
+		rooms:   make(map[ConfID]*presenceRoom),
+		subs:    make(map[*presenceSub]struct{}),
+	}
+}
+
+// Watch starts following the conference of callInfo.
+func (p *Presence) Watch(ctx context.Context, callInfo CallInfoInterface) error {
+	confID := ConfID(callInfo.GetConfID())
+	monitor, err := NewMonitor(callInfo, p.logger, p.options...)
+	if err != nil {
+		return err
+	}
+	room := &presenceRoom{monitor: monitor, online: make(map[ClientID]bool)}
+
+	p.mu.Lock()
+	if _, ok := p.rooms[confID]; ok {
+		p.mu.Unlock()
+		monitor.Close()
+		return fmt.Errorf("conference %s already watched", confID)
+	}
+	p.rooms[confID] = room
+	p.mu.Unlock()
+
+	monitor.SetMemberlistHandler(func(data MsgMemberlistData) {
+		p.apply(confID, data)
+	})
+	room.removeListener = monitor.sepp.addConnectListener(func(connected bool) {
+		if connected {
+			p.mu.Lock()
+			room.resync = true
+			p.mu.Unlock()
+		}
+	})
+	if err := monitor.Start(ctx); err != nil {
+		p.Unwatch(confID)
+		return err
+	}
+	return nil
+}
+
+// Unwatch stops following the conference confID. Its clients are
+// reported offline.
+func (p *Presence) Unwatch(confID ConfID) {
+	p.mu.Lock()
+	room, ok := p.rooms[confID]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.rooms, confID)
+	var changes []PresenceChange
+	for _, clientID := range sortedClientIDs(room.online) {
+		changes = append(changes, PresenceChange{ConfID: confID,
+			ClientID: clientID})
+	}
+	p.mu.Unlock()
+
+	if room.removeListener != nil {
+		room.removeListener()
+	}
+	room.monitor.Close()
+	p.emit(changes)
+}
+
+// apply updates the members of confID with a memberlist.
+func (p *Presence) apply(confID ConfID, data MsgMemberlistData) {
+	p.mu.Lock()
+	room, ok := p.rooms[confID]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	var changes []PresenceChange
+	if room.resync {
+		// the first memberlist after a reconnect lists all members.
+		room.resync = false
+		listed := make(map[ClientID]bool, len(data.Add))
+		for _, m := range data.Add {
+			listed[ClientID(m.ClientID)] = true
+		}
+		for _, clientID := range sortedClientIDs(room.online) {
+			if !listed[clientID] {
+				delete(room.online, clientID)
+				changes = append(changes, PresenceChange{ConfID: confID,
+					ClientID: clientID})
+			}
+		}
+	}
+	for _, m := range data.Add {
+		if clientID := ClientID(m.ClientID); !room.online[clientID] {
+			room.online[clientID] = true
+			changes = append(changes, PresenceChange{ConfID: confID,
+				ClientID: clientID, Online: true})
+		}
+	}
+	for _, id := range data.Del {
+		if clientID := ClientID(id); room.online[clientID] {
+			delete(room.online, clientID)
+			changes = append(changes, PresenceChange{ConfID: confID,
+				ClientID: clientID})
+		}
+	}
+	p.mu.Unlock()
+	p.emit(changes)
+}
+
+// Online returns the clients online in confID.
+func (p *Presence) Online(confID ConfID) []ClientID {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	room, ok := p.rooms[confID]
+	if !ok {
+		return nil
+	}
+	return sortedClientIDs(room.online)
+}
+
+// Conferences returns the watched conferences clientID is online in.
+func (p *Presence) Conferences(clientID ClientID) []ConfID {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var confIDs []ConfID
+	for confID, room := range p.rooms {
+		if room.online[clientID] {
+			confIDs = append(confIDs, confID)
+		}
+	}
+	sort.Slice(confIDs, func(i, j int) bool { return confIDs[i] < confIDs[j] })
+	return confIDs
+}
+
+// IsOnline reports whether clientID is online in any watched conference.
+func (p *Presence) IsOnline(clientID ClientID) bool {
+	return len(p.Conferences(clientID)) > 0
+}
+
+// Subscribe returns a channel receiving all presence changes. A slow
+// subscriber delays further updates. The channel is closed by calling
+// cancel, or when the Presence is closed.
+func (p *Presence) Subscribe() (<-chan PresenceChange, func()) {
+	sub := &presenceSub{
+		ch:   make(chan PresenceChange, subscriptionBufferSize),
+		done: make(chan struct{}),
+	}
+	p.subsMu.Lock()
+	defer p.subsMu.Unlock()
+	if p.subsClosed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	p.subs[sub] = struct{}{}
+
+	cancel := func() {
+		sub.once.Do(func() {
+			close(sub.done)
+			p.subsMu.Lock()
+			defer p.subsMu.Unlock()
+			if _, ok := p.subs[sub]; ok {
+				delete(p.subs, sub)
+				close(sub.ch)
+			}
+		})
+	}
+	return sub.ch, cancel
+}
+
+func (p *Presence) emit(changes []PresenceChange) {
+	if len(changes) == 0 {
+		return
+	}
+	p.subsMu.Lock()
+	defer p.subsMu.Unlock()
+	for sub := range p.subs {
+		for _, change := range changes {
+			select {
+			case sub.ch <- change:
+			case <-sub.done:
+			}
+		}
+	}
+}
+
+// Close stops following all conferences and closes the subscriptions.
+func (p *Presence) Close() {
+	p.mu.Lock()
+	confIDs := make([]ConfID, 0, len(p.rooms))
+	for confID := range p.rooms {
+		confIDs = append(confIDs, confID)
+	}
+	p.mu.Unlock()
+	for _, confID := range confIDs {
+		p.Unwatch(confID)
+	}
+
+	p.subsMu.Lock()
+	defer p.subsMu.Unlock()
+	for sub := range p.subs {
+		delete(p.subs, sub)
+		close(sub.ch)
+	}
+	p.subsClosed = true
+}
+
+func sortedClientIDs(set map[ClientID]bool) []ClientID {
+	ids := make([]ClientID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
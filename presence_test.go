@@ -0,0 +1,68 @@
+package gosepp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		m, ok := c.read().(*MsgMonitor)
+		if !ok {
+			return
+		}
+		base := MsgBase{Type: MsgTypeMemberlist, From: m.To, To: m.From}
+		c.write(MsgMemberlist{MsgBase: base, Data: MsgMemberlistData{
+			Add: []Member{{ClientID: "a"}, {ClientID: "b"}}}})
+		c.write(MsgMemberlist{MsgBase: base, Data: MsgMemberlistData{
+			Del: []string{"a"}}})
+		c.read()
+	})
+	defer srv.Close()
+
+	presence := NewPresence(nil)
+	defer presence.Close()
+	changes, cancel := presence.Subscribe()
+	defer cancel()
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelCtx()
+	if err := presence.Watch(ctx, &CallInfo{SigEndpoint: srv.URL(),
+		ClientID: "lobby", ConfID: "conf"}); err != nil {
+		t.Fatalf("watch failed: %s", err)
+	}
+
+	expected := []PresenceChange{
+		{ConfID: "conf", ClientID: "a", Online: true},
+		{ConfID: "conf", ClientID: "b", Online: true},
+		{ConfID: "conf", ClientID: "a"},
+	}
+	for _, want := range expected {
+		select {
+		case change := <-changes:
+			if change != want {
+				t.Fatalf("expected %#v, got %#v", want, change)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %#v", want)
+		}
+	}
+	if online := presence.Online("conf"); !reflect.DeepEqual(online, []ClientID{"b"}) {
+		t.Fatalf("unexpected online clients %v", online)
+	}
+	if !presence.IsOnline("b") || presence.IsOnline("a") {
+		t.Fatalf("unexpected presence of a or b")
+	}
+}
+
+func TestPresenceResync(t *testing.T) {
+	p := NewPresence(nil)
+	p.rooms["conf"] = &presenceRoom{online: map[ClientID]bool{"a": true, "b": true},
+		resync: true}
+	p.apply("conf", MsgMemberlistData{Add: []Member{{ClientID: "b"}, {ClientID: "c"}}})
+	if online := p.Online("conf"); !reflect.DeepEqual(online, []ClientID{"b", "c"}) {
+		t.Fatalf("unexpected online clients %v", online)
+	}
+}
@@ -0,0 +1,114 @@
+package gosepp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// qosWindow is the number of latest samples a QoSReport is computed
+// from.
+const qosWindow = 256
+
+// QoSReport summarizes the signaling quality of a connection over the
+// latest messages.
+type QoSReport struct {
+	// QueueP50 and QueueP95 are the delays between sending a message
+	// and writing it to the connection.
+	QueueP50 time.Duration
+	QueueP95 time.Duration
+	// RTTP50 and RTTP95 are the round-trip times measured by Echo and
+	// EchoProber.
+	RTTP50 time.Duration
+	RTTP95 time.Duration
+	// Received counts the numbered messages received and Lost the ones
+	// missing in their sequence. Requires WithSequenceNumbers.
+	Received uint64
+	Lost     uint64
+}
+
+// Loss returns the ratio of numbered messages which were lost.
+func (r QoSReport) Loss() float64 {
+	if r.Received+r.Lost == 0 {
+		return 0
+	}
+	return float64(r.Lost) / float64(r.Received+r.Lost)
+}
+
+// QoS returns the current signaling quality report.
+func (rtm *GoSepp) QoS() QoSReport {
+	return rtm.qos.report()
+}
+
+// qos collects the samples of a QoSReport.
+type qos struct {
+	mu       sync.Mutex
+	queue    samples
+	rtt      samples
+	received uint64
+	lost     uint64
+}
+
+func (q *qos) queued(d time.Duration) {
+	q.mu.Lock()
+	q.queue.add(d)
+	q.mu.Unlock()
+}
+
+func (q *qos) roundTrip(d time.Duration) {
+	q.mu.Lock()
+	q.rtt.add(d)
+	q.mu.Unlock()
+}
+
+// sequenced counts a received message and the lost messages before it.
+func (q *qos) sequenced(lost uint64) {
+	q.mu.Lock()
+	q.received++
+	q.lost += lost
+	q.mu.Unlock()
+}
+
+func (q *qos) report() QoSReport {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QoSReport{
+		QueueP50: q.queue.percentile(50),
+		QueueP95: q.queue.percentile(95),
+		RTTP50:   q.rtt.percentile(50),
+		RTTP95:   q.rtt.percentile(95),
+		Received: q.received,
+		Lost:     q.lost,
+	}
+}
+
+// samples holds the latest qosWindow durations.
+type samples struct {
+	d    [qosWindow]time.Duration
+	n    int
+	next int
+}
+
+func (s *samples) add(d time.Duration) {
+	s.d[s.next] = d
+	s.next = (s.next + 1) % qosWindow
+	if s.n < qosWindow {
+		s.n++
+	}
+}
+
+// percentile returns the p-th percentile using the nearest rank, or 0
+// without samples.
+func (s *samples) percentile(p int) time.Duration {
+	if s.n == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, s.n)
+	copy(sorted, s.d[:s.n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*s.n + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
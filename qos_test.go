@@ -0,0 +1,37 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQoSReport(t *testing.T) {
+	rtm := &GoSepp{logger: &silentLogger{}}
+	for i := 1; i <= 100; i++ {
+		rtm.qos.queued(time.Duration(i) * time.Millisecond)
+	}
+	rtm.qos.roundTrip(20 * time.Millisecond)
+	var sc seqChecker
+	for _, seq := range []uint64{1, 2, 4, 5} {
+		sc.check(rtm, addSequence([]byte(`{"type":"chat"}`), seq))
+	}
+
+	report := rtm.QoS()
+	if report.QueueP50 != 50*time.Millisecond || report.QueueP95 != 95*time.Millisecond {
+		t.Fatalf("unexpected queue delays %s, %s", report.QueueP50, report.QueueP95)
+	}
+	if report.RTTP50 != 20*time.Millisecond || report.RTTP95 != 20*time.Millisecond {
+		t.Fatalf("unexpected round-trip times %s, %s", report.RTTP50, report.RTTP95)
+	}
+	if report.Received != 4 || report.Lost != 1 || report.Loss() != 0.2 {
+		t.Fatalf("unexpected loss %+v", report)
+	}
+
+	// only the latest samples are kept.
+	for i := 0; i < qosWindow; i++ {
+		rtm.qos.queued(time.Second)
+	}
+	if report := rtm.QoS(); report.QueueP50 != time.Second {
+		t.Fatalf("old samples not dropped: %s", report.QueueP50)
+	}
+}
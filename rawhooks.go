@@ -0,0 +1,52 @@
+package gosepp
+
+// RawHook is called with the bytes of a frame as sent or received on
+// the wire, e.g. to verify checksums or capture traffic. data is only
+// valid during the call and must not be modified. Hooks run on the
+// sending and receiving goroutines, so they must not block.
+type RawHook func(messageType int, data []byte)
+
+type rawHookEntry struct {
+	hook RawHook
+}
+
+// OnRawReceive registers hook for every received frame, before it is
+// decoded. Batches and fragments are passed as received. The returned
+// function removes the hook.
+func (rtm *GoSepp) OnRawReceive(hook RawHook) func() {
+	return rtm.addRawHook(&rtm.rawReceiveHooks, hook)
+}
+
+// OnRawSend registers hook for every frame written to the connection,
+// after encoding, signing and batching. The returned function removes
+// the hook.
+func (rtm *GoSepp) OnRawSend(hook RawHook) func() {
+	return rtm.addRawHook(&rtm.rawSendHooks, hook)
+}
+
+func (rtm *GoSepp) addRawHook(hooks *[]*rawHookEntry, hook RawHook) func() {
+	entry := &rawHookEntry{hook: hook}
+	rtm.rawHooksMu.Lock()
+	defer rtm.rawHooksMu.Unlock()
+	*hooks = append(*hooks, entry)
+	return func() {
+		rtm.rawHooksMu.Lock()
+		defer rtm.rawHooksMu.Unlock()
+		for i, e := range *hooks {
+			if e == entry {
+				*hooks = append((*hooks)[:i:i], (*hooks)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// runRawHooks calls hooks with a frame.
+func (rtm *GoSepp) runRawHooks(hooks *[]*rawHookEntry, messageType int, data []byte) {
+	rtm.rawHooksMu.Lock()
+	entries := *hooks
+	rtm.rawHooksMu.Unlock()
+	for _, e := range entries {
+		e.hook(messageType, data)
+	}
+}
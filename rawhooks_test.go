@@ -0,0 +1,52 @@
+package gosepp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRawHooks(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.read()
+		c.write(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+			Data: MsgChatData{Content: "pong"}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	sent := make(chan string, 1)
+	received := make(chan string, 1)
+	sepp.OnRawSend(func(messageType int, data []byte) { sent <- string(data) })
+	remove := sepp.OnRawReceive(func(messageType int, data []byte) {
+		received <- string(data)
+	})
+	if ok := <-sepp.ConnectStatusCh(); !ok {
+		t.Fatalf("failed to connect")
+	}
+
+	if err := sepp.SendMsg(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+		Data: MsgChatData{Content: "ping"}}); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	for _, c := range []struct {
+		ch      chan string
+		content string
+	}{{sent, `"content":"ping"`}, {received, `"content":"pong"`}} {
+		select {
+		case data := <-c.ch:
+			if !strings.Contains(data, c.content) {
+				t.Fatalf("unexpected frame %s", data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", c.content)
+		}
+	}
+	remove()
+	<-sepp.RcvCh()
+}
@@ -0,0 +1,95 @@
+package gosepp
+
+import (
+	"fmt"
+	"time"
+)
+
+// reconnectDelay is the delay between connect attempts.
+const reconnectDelay = 2 * time.Second
+
+// ReconnectAttempt announces the next connect attempt after a failed
+// one.
+type ReconnectAttempt struct {
+	// Attempt is the number of the next attempt, starting at 2.
+	Attempt int
+	// Delay until the next attempt.
+	Delay time.Duration
+	// Err is why the previous attempt failed.
+	Err error
+}
+
+// SetReconnectHandler sets a handler called after every failed connect
+// attempt which is retried, e.g. to show "reconnecting in 2s". It is
+// called from the receiving goroutine and must not block.
+func (rtm *GoSepp) SetReconnectHandler(handler func(ReconnectAttempt)) {
+	rtm.mu.Lock()
+	rtm.reconnectHandler = handler
+	rtm.mu.Unlock()
+}
+
+// notifyReconnect announces the attempt following failures.
+func (rtm *GoSepp) notifyReconnect(failures int, err error) {
+	rtm.mu.Lock()
+	handler := rtm.reconnectHandler
+	rtm.mu.Unlock()
+	if handler != nil {
+		handler(ReconnectAttempt{Attempt: failures + 1, Delay: reconnectDelay, Err: err})
+	}
+}
+
+// WithMaxReconnects gives up connecting after attempts consecutive
+// failed connect attempts, or once connecting failed for budget. Zero
+// disables the respective limit. After giving up, the state changes to
+// ConnClosed, the receive channels are closed and Err returns the
+// reason.
+func WithMaxReconnects(attempts int, budget time.Duration) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.maxReconnects = attempts
+		rtm.reconnectBudget = budget
+	}
+}
+
+// Err returns why connecting was given up, or nil.
+func (rtm *GoSepp) Err() error {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.err
+}
+
+// reconnectTracker counts consecutive failed connect attempts.
+type reconnectTracker struct {
+	failures     int
+	failingSince time.Time
+}
+
+// failed records a failed attempt and returns the terminal error if
+// the limits of rtm are exceeded.
+func (t *reconnectTracker) failed(rtm *GoSepp, err error) error {
+	t.failures++
+	if t.failingSince.IsZero() {
+		t.failingSince = time.Now()
+	}
+	if rtm.maxReconnects > 0 && t.failures >= rtm.maxReconnects {
+		return fmt.Errorf("%w after %d attempts: %s", ErrReconnectFailed,
+			t.failures, err)
+	}
+	if rtm.reconnectBudget > 0 && time.Since(t.failingSince) >= rtm.reconnectBudget {
+		return fmt.Errorf("%w after %s: %s", ErrReconnectFailed,
+			rtm.reconnectBudget, err)
+	}
+	return nil
+}
+
+func (t *reconnectTracker) reset() {
+	*t = reconnectTracker{}
+}
+
+// giveUp stops connecting with err.
+func (rtm *GoSepp) giveUp(err error) {
+	rtm.logger.Error("Giving up connecting [%s].", err)
+	rtm.mu.Lock()
+	rtm.err = err
+	rtm.run = false
+	rtm.mu.Unlock()
+}
@@ -0,0 +1,151 @@
+package gosepp
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultReceiveBufferSize is the capacity of the receive buffer.
+const defaultReceiveBufferSize = 256
+
+// OverflowPolicy decides what happens to a received message if the
+// receive buffer is full, because RcvCh is not consumed.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock stops reading from the connection until the consumer
+	// catches up. No messages are lost.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered message.
+	OverflowDropOldest
+	// OverflowDropNewest drops the received message.
+	OverflowDropNewest
+	// OverflowDisconnect drops the received message and closes the
+	// connection, which is re-established.
+	OverflowDisconnect
+)
+
+// WithReceiveBuffer sets the capacity of the buffer between the
+// connection and RcvCh and what happens if it's full. Handlers and
+// subscriptions are called when the message is read, so they don't wait
+// for RcvCh consumers. Defaults to 256 messages and OverflowBlock.
+func WithReceiveBuffer(size int, policy OverflowPolicy) GoSeppOption {
+	return func(rtm *GoSepp) {
+		if size > 0 {
+			rtm.recvBufSize = size
+		}
+		rtm.overflowPolicy = policy
+	}
+}
+
+// recvRing buffers received messages until delivered to RcvCh.
+type recvRing struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []MsgInterface
+	head   int
+	n      int
+	policy OverflowPolicy
+	closed bool
+}
+
+func newRecvRing(size int, policy OverflowPolicy) *recvRing {
+	if size <= 0 {
+		size = defaultReceiveBufferSize
+	}
+	r := &recvRing{buf: make([]MsgInterface, size), policy: policy}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+// push adds msg to the buffer. It returns the message dropped to make
+// room, if any. ok is false if the policy demands to disconnect.
+func (r *recvRing) push(msg MsgInterface) (dropped MsgInterface, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.policy == OverflowBlock && r.n == len(r.buf) && !r.closed {
+		r.cond.Wait()
+	}
+	if r.closed {
+		return msg, true
+	}
+	if r.n == len(r.buf) {
+		switch r.policy {
+		case OverflowDropOldest:
+			dropped = r.buf[r.head]
+			r.head = (r.head + 1) % len(r.buf)
+			r.n--
+		case OverflowDisconnect:
+			return msg, false
+		default:
+			return msg, true
+		}
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = msg
+	r.n++
+	r.cond.Broadcast()
+	return dropped, true
+}
+
+// pop returns the oldest message, waiting for one. It returns false
+// once the buffer is closed and empty.
+func (r *recvRing) pop() (MsgInterface, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.n == 0 && !r.closed {
+		r.cond.Wait()
+	}
+	if r.n == 0 {
+		return nil, false
+	}
+	msg := r.buf[r.head]
+	r.buf[r.head] = nil
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	r.cond.Broadcast()
+	return msg, true
+}
+
+// close wakes up all waiters. Buffered messages can still be popped.
+func (r *recvRing) close() {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// deliver moves buffered messages to RcvCh until the buffer is closed
+// and drained, or ctx is done.
+func (rtm *GoSepp) deliver(ctx context.Context) {
+	defer rtm.deliverWaitGroup.Done()
+	for {
+		msg, ok := rtm.recvBuf.pop()
+		if !ok {
+			return
+		}
+		select {
+		case rtm.rcvCh <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// enqueue buffers msg for delivery to RcvCh, applying the overflow
+// policy.
+func (rtm *GoSepp) enqueue(msg MsgInterface) {
+	dropped, ok := rtm.recvBuf.push(msg)
+	if !ok {
+		dropped = msg
+		rtm.logger.Warn("Receive buffer full. Disconnecting.")
+		if wsClient := rtm.conn(); wsClient != nil {
+			wsClient.Close()
+		}
+	} else if dropped != nil {
+		rtm.logger.Warn("Receive buffer full. Dropping %s message.",
+			dropped.GetType())
+	}
+	if dropped != nil && rtm.pooled {
+		ReleaseMsg(dropped)
+	}
+}
@@ -0,0 +1,84 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReceiveBufferDropOldest(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for _, content := range []string{"1", "2", "3", "4", "done"} {
+			c.write(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+				Data: MsgChatData{Content: content}})
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil,
+		WithReceiveBuffer(2, OverflowDropOldest))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	done := make(chan struct{})
+	sepp.On(MsgTypeChat, func(msg MsgInterface) {
+		if msg.(*MsgChat).Data.Content == "done" {
+			close(done)
+		}
+	})
+
+	// handlers are called although RcvCh is not consumed.
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for handler")
+	}
+	// the buffer holds the latest two, older ones were dropped unless
+	// already taken by the deliverer.
+	var got []string
+	for len(got) == 0 || got[len(got)-1] != "done" {
+		select {
+		case msg := <-sepp.RcvCh():
+			got = append(got, msg.(*MsgChat).Data.Content)
+		case <-time.After(time.Second):
+			t.Fatalf("timeout, got %v", got)
+		}
+	}
+	if len(got) >= 5 || got[len(got)-2] != "4" {
+		t.Fatalf("unexpected messages %v", got)
+	}
+}
+
+func TestRecvRingPolicies(t *testing.T) {
+	msg := func(id string) MsgInterface { return &MsgChat{MsgBase: MsgBase{MsgID: id}} }
+
+	r := newRecvRing(1, OverflowDropNewest)
+	r.push(msg("a"))
+	if dropped, ok := r.push(msg("b")); !ok || dropped.GetMsgID() != "b" {
+		t.Fatalf("expected b to be dropped")
+	}
+	r = newRecvRing(1, OverflowDisconnect)
+	r.push(msg("a"))
+	if _, ok := r.push(msg("b")); ok {
+		t.Fatalf("expected disconnect")
+	}
+	r = newRecvRing(1, OverflowBlock)
+	r.push(msg("a"))
+	pushed := make(chan struct{})
+	go func() {
+		r.push(msg("b"))
+		close(pushed)
+	}()
+	if m, _ := r.pop(); m.GetMsgID() != "a" {
+		t.Fatalf("expected a")
+	}
+	<-pushed
+	r.close()
+	if m, ok := r.pop(); !ok || m.GetMsgID() != "b" {
+		t.Fatalf("expected b after close")
+	}
+	if _, ok := r.pop(); ok {
+		t.Fatalf("expected closed ring")
+	}
+}
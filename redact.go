@@ -0,0 +1,88 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces masked values.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the json keys whose values are always masked.
+var sensitiveKeys = map[string]bool{
+	"token":         true,
+	"auth_token":    true,
+	"authorization": true,
+	"password":      true,
+	"secret":        true,
+	"signature":     true,
+	SignatureField:  true,
+}
+
+var (
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	icePattern    = regexp.MustCompile(`(?m)^(a=ice-(?:ufrag|pwd):).*?(\r?)$`)
+)
+
+// WithFrameTrace logs every sent and received frame with Trace. Bearer
+// tokens and ICE credentials are masked, as done by RedactFrame. If
+// redactSDP is set, sdp bodies are masked entirely.
+func WithFrameTrace(redactSDP bool) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.frameTrace = true
+		rtm.redactSDP = redactSDP
+	}
+}
+
+// RedactFrame returns a copy of the json frame data safe for logging:
+// tokens, passwords and signatures are masked, and so are ICE
+// credentials within sdp bodies. If redactSDP is set, sdp bodies are
+// masked entirely. Frames which aren't json have bearer tokens masked.
+func RedactFrame(data []byte, redactSDP bool) []byte {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return bearerPattern.ReplaceAll(data, []byte("${1}"+redacted))
+	}
+	b, err := json.Marshal(redactValue(v, redactSDP))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return b
+}
+
+// RedactSDP returns sdp with the ICE credentials masked.
+func RedactSDP(sdp string) string {
+	return icePattern.ReplaceAllString(sdp, "${1}"+redacted+"${2}")
+}
+
+func redactValue(v interface{}, redactSDP bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			switch {
+			case sensitiveKeys[strings.ToLower(key)]:
+				value[key] = redacted
+			case key == "sdp":
+				if s, ok := field.(string); ok {
+					if redactSDP {
+						value[key] = redacted
+					} else {
+						value[key] = RedactSDP(s)
+					}
+					continue
+				}
+				value[key] = redactValue(field, redactSDP)
+			default:
+				value[key] = redactValue(field, redactSDP)
+			}
+		}
+	case []interface{}:
+		for i, field := range value {
+			value[i] = redactValue(field, redactSDP)
+		}
+	case string:
+		return bearerPattern.ReplaceAllString(value, "${1}"+redacted)
+	}
+	return v
+}
@@ -0,0 +1,36 @@
+package gosepp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactFrame(t *testing.T) {
+	frame := []byte(`{"type":"call_start","data":{"sdp":{"type":"offer",` +
+		`"sdp":"v=0\r\na=ice-ufrag:abcd\r\na=ice-pwd:secretpwd\r\n"},` +
+		`"token":"jwt","note":"Bearer abc.def"}}`)
+
+	got := string(RedactFrame(frame, false))
+	for _, leak := range []string{"abcd", "secretpwd", "jwt", "abc.def"} {
+		if strings.Contains(got, leak) {
+			t.Fatalf("%q not redacted in %s", leak, got)
+		}
+	}
+	if !strings.Contains(got, `v=0\r\na=ice-ufrag:[REDACTED]\r\n`) {
+		t.Fatalf("sdp mangled: %s", got)
+	}
+	if got := string(RedactFrame(frame, true)); strings.Contains(got, "v=0") {
+		t.Fatalf("sdp not redacted: %s", got)
+	}
+	if got := string(RedactFrame([]byte("Authorization: Bearer xyz"), false)); strings.Contains(got, "xyz") {
+		t.Fatalf("token not redacted: %s", got)
+	}
+
+	signed, err := SignMsg([]byte("key"), []byte(`{"type":"chat"}`))
+	if err != nil {
+		t.Fatalf("sign failed: %s", err)
+	}
+	if got := string(RedactFrame(signed, false)); !strings.Contains(got, redacted) {
+		t.Fatalf("signature not redacted: %s", got)
+	}
+}
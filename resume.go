@@ -0,0 +1,73 @@
+package gosepp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Headers of the connect request identifying the client session.
+const (
+	// SessionIDHeader carries the id generated for every connect.
+	SessionIDHeader = "Sepp-Session-Id"
+	// ResumeTokenHeader carries the latest resume token, so the server
+	// can re-associate a reconnecting client with its call state.
+	ResumeTokenHeader = "Sepp-Resume-Token"
+)
+
+// WithResumeToken sets the resume token sent on the first connect, e.g.
+// one persisted by a previous process. It is replaced by the tokens the
+// server sends.
+func WithResumeToken(token string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.resumeToken = token
+	}
+}
+
+// SessionID returns the id of the current connection, generated on
+// every connect and sent in the SessionIDHeader.
+func (rtm *GoSepp) SessionID() string {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.sessionID
+}
+
+// ResumeToken returns the latest resume token sent by the server.
+func (rtm *GoSepp) ResumeToken() string {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.resumeToken
+}
+
+func (rtm *GoSepp) setResumeToken(token string) {
+	rtm.mu.Lock()
+	rtm.resumeToken = token
+	rtm.mu.Unlock()
+}
+
+// applySession generates a new session id and adds it together with the
+// resume token to header.
+func (rtm *GoSepp) applySession(header http.Header) error {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return err
+	}
+	rtm.mu.Lock()
+	rtm.sessionID = sessionID
+	resumeToken := rtm.resumeToken
+	rtm.mu.Unlock()
+	header.Set(SessionIDHeader, sessionID)
+	if len(resumeToken) > 0 {
+		header.Set(ResumeTokenHeader, resumeToken)
+	}
+	return nil
+}
+
+// newSessionID returns a random session id.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
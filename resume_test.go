@@ -0,0 +1,56 @@
+package gosepp
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResumeToken(t *testing.T) {
+	var conns int32
+	headers := make(chan [2]string, 2)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		headers <- [2]string{c.header.Get(SessionIDHeader),
+			c.header.Get(ResumeTokenHeader)}
+		if atomic.AddInt32(&conns, 1) == 1 {
+			c.write(MsgResumeToken{MsgBase: MsgBase{Type: MsgTypeResumeToken},
+				Data: MsgResumeTokenData{Token: "resume"}})
+			// the chat is received once the token is processed.
+			c.write(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat}})
+			return // drop the connection
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil, WithResumeToken("initial"))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	// the resume token is handled internally.
+	if msg := <-sepp.RcvCh(); msg.GetType() != MsgTypeChat {
+		t.Fatalf("unexpected message %s", msg.GetType())
+	}
+	if sepp.ResumeToken() != "resume" {
+		t.Fatalf("unexpected resume token %q", sepp.ResumeToken())
+	}
+	var first, second [2]string
+	for _, h := range []*[2]string{&first, &second} {
+		select {
+		case *h = <-headers:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for connect")
+		}
+	}
+	if len(first[0]) == 0 || first[0] == second[0] {
+		t.Fatalf("expected new session ids, got %q and %q", first[0], second[0])
+	}
+	if first[1] != "initial" || second[1] != "resume" {
+		t.Fatalf("unexpected resume tokens %q and %q", first[1], second[1])
+	}
+	if sepp.SessionID() != second[0] {
+		t.Fatalf("unexpected session id %q", sepp.SessionID())
+	}
+}
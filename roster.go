@@ -0,0 +1,130 @@
+package gosepp
+
+import (
+	"sort"
+	"sync"
+)
+
+// rosterHandlers are the per event callbacks of a Roster.
+type rosterHandlers struct {
+	memberJoined func(Member)
+	memberLeft   func(clientID string)
+	mediaAdded   func(Media)
+	mediaRemoved func(Media)
+}
+
+func (h rosterHandlers) empty() bool {
+	return h.memberJoined == nil && h.memberLeft == nil &&
+		h.mediaAdded == nil && h.mediaRemoved == nil
+}
+
+// Roster tracks the members and media of a conference from memberlist
+// messages and turns them into per member events.
+type Roster struct {
+	handlers rosterHandlers
+
+	mu      sync.Mutex
+	members map[string]Member
+	media   map[string]Media
+}
+
+// NewRoster returns an empty roster.
+func NewRoster() *Roster {
+	return &Roster{
+		members: make(map[string]Member),
+		media:   make(map[string]Media),
+	}
+}
+
+// OnMemberJoined sets the handler called for every new member.
+func (r *Roster) OnMemberJoined(handler func(Member)) {
+	r.handlers.memberJoined = handler
+}
+
+// OnMemberLeft sets the handler called for every member which left.
+func (r *Roster) OnMemberLeft(handler func(clientID string)) {
+	r.handlers.memberLeft = handler
+}
+
+// OnMediaAdded sets the handler called for every new media.
+func (r *Roster) OnMediaAdded(handler func(Media)) {
+	r.handlers.mediaAdded = handler
+}
+
+// OnMediaRemoved sets the handler called for every media which is no
+// longer listed.
+func (r *Roster) OnMediaRemoved(handler func(Media)) {
+	r.handlers.mediaRemoved = handler
+}
+
+// Apply updates the roster with a memberlist and calls the handlers.
+// The media of a memberlist is the complete list of current media.
+func (r *Roster) Apply(data MsgMemberlistData) {
+	r.mu.Lock()
+	var joined []Member
+	var left []string
+	for _, m := range data.Add {
+		if _, ok := r.members[m.ClientID]; !ok {
+			joined = append(joined, m)
+		}
+		r.members[m.ClientID] = m
+	}
+	for _, clientID := range data.Del {
+		if _, ok := r.members[clientID]; ok {
+			delete(r.members, clientID)
+			left = append(left, clientID)
+		}
+	}
+
+	var added, removed []Media
+	current := make(map[string]Media, len(data.Media))
+	for _, m := range data.Media {
+		current[m.MediaID] = m
+		if _, ok := r.media[m.MediaID]; !ok {
+			added = append(added, m)
+		}
+	}
+	for id, m := range r.media {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, m)
+		}
+	}
+	r.media = current
+	r.mu.Unlock()
+
+	// call the handlers unlocked, so they can query the roster.
+	for _, m := range joined {
+		if r.handlers.memberJoined != nil {
+			r.handlers.memberJoined(m)
+		}
+	}
+	for _, clientID := range left {
+		if r.handlers.memberLeft != nil {
+			r.handlers.memberLeft(clientID)
+		}
+	}
+	for _, m := range added {
+		if r.handlers.mediaAdded != nil {
+			r.handlers.mediaAdded(m)
+		}
+	}
+	for _, m := range removed {
+		if r.handlers.mediaRemoved != nil {
+			r.handlers.mediaRemoved(m)
+		}
+	}
+}
+
+// Members returns the current members ordered by client-id.
+func (r *Roster) Members() []Member {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	members := make([]Member, 0, len(r.members))
+	for _, m := range r.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ClientID < members[j].ClientID
+	})
+	return members
+}
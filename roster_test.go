@@ -0,0 +1,27 @@
+package gosepp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRosterEvents(t *testing.T) {
+	var events []string
+	r := NewRoster()
+	r.OnMemberJoined(func(m Member) { events = append(events, "join "+m.ClientID) })
+	r.OnMemberLeft(func(clientID string) { events = append(events, "left "+clientID) })
+	r.OnMediaAdded(func(m Media) { events = append(events, "media+ "+m.MediaID) })
+	r.OnMediaRemoved(func(m Media) { events = append(events, "media- "+m.MediaID) })
+
+	r.Apply(MsgMemberlistData{Add: []Member{{ClientID: "a"}, {ClientID: "b"}},
+		Media: []Media{{MediaID: "m1"}}})
+	r.Apply(MsgMemberlistData{Add: []Member{{ClientID: "a"}}, Del: []string{"b", "x"}})
+
+	want := []string{"join a", "join b", "media+ m1", "left b", "media- m1"}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if members := r.Members(); len(members) != 1 || members[0].ClientID != "a" {
+		t.Fatalf("unexpected members %v", members)
+	}
+}
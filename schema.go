@@ -0,0 +1,141 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaDraft is the JSON Schema dialect emitted by JSONSchema.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timestampType = reflect.TypeOf(Timestamp{})
+	bytesType     = reflect.TypeOf([]byte(nil))
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) of all registered
+// message types, derived from the message structs. Every message is
+// a definition named after its struct; the root schema accepts any of
+// them, discriminated by the type property.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGen{defs: make(map[string]interface{})}
+	types := make([]string, 0, len(SeppMsgTypes))
+	for msgType := range SeppMsgTypes {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+
+	oneOf := make([]interface{}, 0, len(types))
+	for _, msgType := range types {
+		t := reflect.TypeOf(SeppMsgTypes[msgType]()).Elem()
+		schema := g.object(t)
+		schema["properties"].(map[string]interface{})["type"] =
+			map[string]interface{}{"const": msgType}
+		g.defs[t.Name()] = schema
+		oneOf = append(oneOf, ref(t.Name()))
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema": schemaDraft,
+		"title":   "sepp message",
+		"oneOf":   oneOf,
+		"$defs":   g.defs,
+	}, "", "  ")
+}
+
+type schemaGen struct {
+	defs map[string]interface{}
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+// schema returns the schema of values of t. Named structs are added
+// to the definitions and referenced.
+func (g *schemaGen) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timestampType:
+		return map[string]interface{}{
+			"type":        []string{"string", "number"},
+			"description": "RFC 3339 time, or unix time in seconds or milliseconds",
+		}
+	case t == bytesType:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok && len(t.Name()) > 0 {
+			// placeholder against recursion.
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.object(t)
+		}
+		if len(t.Name()) == 0 {
+			return g.object(t)
+		}
+		return ref(t.Name())
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object",
+			"additionalProperties": g.schema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// object returns the object schema of struct t. Embedded structs are
+// inlined like encoding/json does. Fields without omitempty are
+// required.
+func (g *schemaGen) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	g.fields(t, properties, &required)
+	sort.Strings(required)
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func (g *schemaGen) fields(t reflect.Type, properties map[string]interface{},
+	required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		if field.Anonymous && len(tag[0]) == 0 && field.Type.Kind() == reflect.Struct {
+			g.fields(field.Type, properties, required)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := tag[0]
+		if len(name) == 0 {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		omitempty := false
+		for _, opt := range tag[1:] {
+			omitempty = omitempty || opt == "omitempty"
+		}
+		if !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
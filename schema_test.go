@@ -0,0 +1,41 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	b, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	var schema struct {
+		OneOf []interface{} `json:"oneOf"`
+		Defs  map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("invalid schema: %s", err)
+	}
+	if len(schema.OneOf) != len(SeppMsgTypes) {
+		t.Fatalf("expected %d messages, got %d", len(SeppMsgTypes), len(schema.OneOf))
+	}
+	// every field of the fixtures must be described.
+	fixture, err := os.ReadFile(filepath.Join("testdata", "fixtures", "chat.json"))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	var chat struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(fixture, &chat)
+	for key := range chat.Data {
+		if _, ok := schema.Defs["MsgChatData"].Properties[key]; !ok {
+			t.Errorf("chat data field %s missing in schema", key)
+		}
+	}
+}
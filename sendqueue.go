@@ -0,0 +1,107 @@
+package gosepp
+
+import (
+	"context"
+	"sync"
+)
+
+// sendQueue counts the frames which were queued but not yet written.
+type sendQueue struct {
+	mu      sync.Mutex
+	pending int
+	// drained is closed once pending drops to zero.
+	drained chan struct{}
+	closed  bool
+	// highWater and onHighWater are set by WithSendHighWaterMark.
+	highWater   int
+	onHighWater func(pending int)
+}
+
+// WithSendHighWaterMark calls fn whenever the number of pending messages
+// rises above n, so applications can apply their own backpressure. fn is
+// called on the goroutine of the SendMsg call queuing the message, before
+// it's queued, so it delays that call and must not block.
+func WithSendHighWaterMark(n int, fn func(pending int)) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.queue.highWater = n
+		rtm.queue.onHighWater = fn
+	}
+}
+
+func (o *sendQueue) add() {
+	o.mu.Lock()
+	o.pending++
+	pending := o.pending
+	exceeded := o.onHighWater != nil && pending == o.highWater+1
+	o.mu.Unlock()
+	if exceeded {
+		o.onHighWater(pending)
+	}
+}
+
+func (o *sendQueue) done(n int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending -= n
+	if o.pending <= 0 {
+		o.pending = 0
+		o.wake()
+	}
+}
+
+// close releases all waiters, once the sender stopped.
+func (o *sendQueue) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.closed = true
+	o.wake()
+}
+
+func (o *sendQueue) wake() {
+	if o.drained != nil {
+		close(o.drained)
+		o.drained = nil
+	}
+}
+
+// PendingSendCount returns the number of messages and binary frames
+// queued, but not yet written to the connection.
+func (rtm *GoSepp) PendingSendCount() int {
+	rtm.queue.mu.Lock()
+	defer rtm.queue.mu.Unlock()
+	return rtm.queue.pending
+}
+
+// Drain blocks until all messages queued so far were written to the
+// connection. Messages queued while there is no connection are dropped,
+// which counts as written. Returns ErrNotRunning if the GoSepp is stopped
+// with messages pending.
+func (rtm *GoSepp) Drain(ctx context.Context) error {
+	o := &rtm.queue
+	o.mu.Lock()
+	if o.pending == 0 {
+		o.mu.Unlock()
+		return nil
+	}
+	if o.closed {
+		o.mu.Unlock()
+		return ErrNotRunning
+	}
+	if o.drained == nil {
+		o.drained = make(chan struct{})
+	}
+	drained := o.drained
+	o.mu.Unlock()
+
+	select {
+	case <-drained:
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.closed && o.pending > 0 {
+			return ErrNotRunning
+		}
+		return nil
+	case <-ctx.Done():
+		return ctxError(ctx, "drain")
+	}
+}
@@ -0,0 +1,66 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for c.read() != nil {
+		}
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	for i := 0; i < 10; i++ {
+		if err := sepp.SendMsg(NewChatMsg("conf", "hi")); err != nil {
+			t.Fatalf("send failed: %s", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sepp.Drain(ctx); err != nil {
+		t.Fatalf("drain failed: %s", err)
+	}
+	if n := sepp.PendingSendCount(); n != 0 {
+		t.Fatalf("expected no pending messages, got %d", n)
+	}
+}
+
+func TestSendHighWaterMark(t *testing.T) {
+	var calls []int
+	q := sendQueue{highWater: 2,
+		onHighWater: func(pending int) { calls = append(calls, pending) }}
+	for i := 0; i < 4; i++ {
+		q.add()
+	}
+	q.done(3)
+	q.add()
+	q.add()
+	if len(calls) != 2 || calls[0] != 3 || calls[1] != 3 {
+		t.Fatalf("unexpected callbacks %v", calls)
+	}
+}
+
+func TestSendMsgCtxNotConnected(t *testing.T) {
+	sepp, err := NewGoSepp("ws://127.0.0.1:1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sepp.SendMsgCtx(ctx, NewChatMsg("conf", "hi")); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package gosepp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// typedMsg is a pointer to a message struct embedding MsgBase.
+type typedMsg[T any] interface {
+	*T
+	MsgInterface
+	setType(string)
+}
+
+func (msg *MsgBase) setType(msgType string) {
+	msg.Type = msgType
+}
+
+// MsgTypeOf returns the type string registered for the message struct T.
+func MsgTypeOf[T any]() (string, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	names := msgTypesOf(t)
+	if len(names) != 1 {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedMsgType, t.Name())
+	}
+	return names[0], nil
+}
+
+// SendTyped stamps msg with the type string registered for its struct
+// and sends it, so the type can't disagree with the struct sent.
+//
+//	gosepp.SendTyped(sepp, &gosepp.MsgChat{Data: data})
+func SendTyped[T any, PT typedMsg[T]](sepp *GoSepp, msg PT) error {
+	msgType, err := MsgTypeOf[T]()
+	if err != nil {
+		return err
+	}
+	msg.setType(msgType)
+	return sepp.SendMsg(msg)
+}
@@ -0,0 +1,16 @@
+package gosepp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMsgTypeOf(t *testing.T) {
+	if msgType, err := MsgTypeOf[MsgSdpUpdate](); err != nil || msgType != MsgTypeSdpUpdate {
+		t.Fatalf("unexpected %q, %v", msgType, err)
+	}
+	type unknownMsg struct{ MsgBase }
+	if _, err := MsgTypeOf[unknownMsg](); !errors.Is(err, ErrUnsupportedMsgType) {
+		t.Fatalf("expected ErrUnsupportedMsgType, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// SequenceField is the json field carrying the sequence number.
+const SequenceField = "seq"
+
+// WithSequenceNumbers numbers outgoing messages per connection, starting
+// at 1, and checks the numbers of received messages. onGap is called
+// with the expected and the received number if messages apparently were
+// lost. Received messages without sequence number are not checked.
+func WithSequenceNumbers(onGap func(expected, received uint64)) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.sequence = true
+		rtm.onSequenceGap = onGap
+	}
+}
+
+// addSequence inserts the sequence number as first field of the json
+// object data.
+func addSequence(data []byte, seq uint64) []byte {
+	if len(data) < 2 || data[0] != '{' {
+		return data
+	}
+	b := make([]byte, 0, len(data)+24)
+	b = append(b, `{"`+SequenceField+`":`...)
+	b = strconv.AppendUint(b, seq, 10)
+	if data[1] != '}' {
+		b = append(b, ',')
+	}
+	return append(b, data[1:]...)
+}
+
+// seqCounter numbers the messages sent on a connection.
+type seqCounter struct {
+	seq  uint64
+	conn Conn
+}
+
+// next returns the number of the next message sent on conn.
+func (sc *seqCounter) next(conn Conn) uint64 {
+	if conn != sc.conn {
+		sc.seq, sc.conn = 0, conn
+	}
+	sc.seq++
+	return sc.seq
+}
+
+// seqChecker detects gaps in the sequence numbers of a connection.
+type seqChecker struct {
+	expected uint64
+}
+
+func (sc *seqChecker) check(rtm *GoSepp, data []byte) {
+	var numbered struct {
+		Seq *uint64 `json:"seq"`
+	}
+	if err := json.Unmarshal(data, &numbered); err != nil || numbered.Seq == nil {
+		return
+	}
+	seq := *numbered.Seq
+	if sc.expected == 0 {
+		// the first message of the connection.
+		sc.expected = 1
+	}
+	if seq >= sc.expected {
+		rtm.qos.sequenced(seq - sc.expected)
+	}
+	switch {
+	case seq > sc.expected:
+		rtm.logger.Warn("Sequence gap. Expected %d, received %d.", sc.expected, seq)
+		if rtm.onSequenceGap != nil {
+			rtm.onSequenceGap(sc.expected, seq)
+		}
+	case seq < sc.expected:
+		rtm.logger.Warn("Out of order message %d. Expected %d.", seq, sc.expected)
+		return
+	}
+	sc.expected = seq + 1
+}
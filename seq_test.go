@@ -0,0 +1,25 @@
+package gosepp
+
+import (
+	"testing"
+)
+
+func TestSequenceGapDetection(t *testing.T) {
+	type gap struct{ expected, received uint64 }
+	var gaps []gap
+	rtm := &GoSepp{logger: &silentLogger{}, onSequenceGap: func(expected, received uint64) {
+		gaps = append(gaps, gap{expected, received})
+	}}
+
+	if got := string(addSequence([]byte(`{}`), 7)); got != `{"seq":7}` {
+		t.Fatalf("unexpected %s", got)
+	}
+	var sc seqChecker
+	for _, seq := range []uint64{1, 2, 4, 3, 5} {
+		sc.check(rtm, addSequence([]byte(`{"type":"chat"}`), seq))
+	}
+	sc.check(rtm, []byte(`{"type":"chat"}`))
+	if len(gaps) != 1 || gaps[0] != (gap{3, 4}) {
+		t.Fatalf("unexpected gaps %v", gaps)
+	}
+}
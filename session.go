@@ -0,0 +1,801 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CallState describes the state of a call session.
+type CallState int
+
+// Call session states
+const (
+	CallStateActive CallState = iota
+	CallStateTerminated
+)
+
+func (s CallState) String() string {
+	switch s {
+	case CallStateActive:
+		return "active"
+	case CallStateTerminated:
+		return "terminated"
+	default:
+		return fmt.Sprintf("CallState(%d)", int(s))
+	}
+}
+
+// TerminationReason tells why a call session ended.
+type TerminationReason int
+
+// Termination reasons
+const (
+	// TerminatedRemotely is set if the remote end ended the call.
+	TerminatedRemotely TerminationReason = iota
+	// TerminatedLocally is set if Terminate was called.
+	TerminatedLocally
+	// TerminatedIdle is set if the call was idle, see WithIdleTerminate.
+	TerminatedIdle
+	// TerminatedMaxDuration is set if the call exceeded its maximum
+	// duration, see WithMaxCallDuration.
+	TerminatedMaxDuration
+	// TerminatedWatchdog is set if the dispatcher was stuck, see
+	// WithDispatchWatchdog.
+	TerminatedWatchdog
+)
+
+func (r TerminationReason) String() string {
+	switch r {
+	case TerminatedRemotely:
+		return "remote"
+	case TerminatedLocally:
+		return "local"
+	case TerminatedIdle:
+		return "idle"
+	case TerminatedMaxDuration:
+		return "max-duration"
+	case TerminatedWatchdog:
+		return "watchdog"
+	default:
+		return fmt.Sprintf("TerminationReason(%d)", int(r))
+	}
+}
+
+// defaultTypingTimeout is the inactivity after which typing stops.
+const defaultTypingTimeout = 3 * time.Second
+
+// callHandlers bundles the handlers invoked by the dispatcher
+// of a call session.
+type callHandlers struct {
+	termination func()
+	// terminationReason is called along with termination.
+	terminationReason func(TerminationReason)
+	sdpUpdate         func(Sdp)
+	memberlist        func(MsgMemberlistData)
+	sourceUpdate      func(MsgSourceUpdateData)
+	transfer          func(target string)
+	hold              func(onHold bool)
+	err               func(error)
+	chatReceipt       func(MsgChatReceiptData)
+	typing            func(clientID string, on bool)
+	sourceDiff        func(SourceUpdateDiff)
+	roster            rosterHandlers
+}
+
+// CallSession is a single established call. It is created by
+// Call.StartSession or IncomingCall.Accept and stays valid until
+// the call is terminated.
+type CallSession struct {
+	// sepp is the signaling connection, guarded by mu as it's replaced
+	// by migrations.
+	sepp *GoSepp
+	// inbox delivers the received messages of this call. Only accessed
+	// by the dispatcher.
+	inbox <-chan MsgInterface
+	// migrations hands a new connection to the dispatcher.
+	migrations chan sessionMigration
+	// dispatchDone is closed once the dispatcher returned.
+	dispatchDone chan struct{}
+	// removeListener stops resuming on reconnects of sepp.
+	removeListener func()
+	// from and to are the headers used for messages of this call.
+	from     string
+	to       string
+	callID   CallID
+	handlers callHandlers
+	cancel   context.CancelFunc
+	// termCh is closed once the call is terminated.
+	termCh   chan struct{}
+	termOnce sync.Once
+	logger   Logger
+	// onDone is called when the dispatcher has stopped.
+	onDone func()
+	// autoResume replays the call state after a reconnect.
+	autoResume bool
+	// typingTimeout stops typing after inactivity.
+	typingTimeout time.Duration
+	// idleTimeout terminates the call once idle, if set.
+	idleTimeout   time.Duration
+	idleCondition IdleCondition
+	// maxDuration terminates the call once exceeded, if set.
+	maxDuration time.Duration
+	// watchdog reports a stuck dispatcher, if set.
+	watchdog *watchdog
+	// lastSources is the previous source update, used for diffs.
+	lastSources MsgSourceUpdateData
+	// roster derives member events from memberlists.
+	roster *Roster
+	// early holds messages received during call setup, which are
+	// dispatched first.
+	early []MsgInterface
+	// initialMembers are the members listed during call setup.
+	initialMembers []Member
+
+	mu    sync.Mutex
+	state CallState
+	// reason is set once a termination was requested locally.
+	reason    *TerminationReason
+	onHold    bool
+	videoOff  bool
+	localSdp  Sdp
+	remoteSdp Sdp
+	// typingTimer is set while typing.
+	typingTimer *time.Timer
+}
+
+// CallSnapshot is the state of a call session as known to the client.
+type CallSnapshot struct {
+	CallID    CallID
+	State     CallState
+	LocalSdp  Sdp
+	RemoteSdp Sdp
+	VideoOff  bool
+	OnHold    bool
+}
+
+func newCallSession(sepp *GoSepp, inbox <-chan MsgInterface, from, to string,
+	callID CallID, localSdp, remoteSdp Sdp, handlers callHandlers,
+	logger Logger) *CallSession {
+	var roster *Roster
+	if !handlers.roster.empty() {
+		roster = NewRoster()
+		roster.handlers = handlers.roster
+	}
+	return &CallSession{
+		sepp:         sepp,
+		inbox:        inbox,
+		from:         from,
+		to:           to,
+		callID:       callID,
+		localSdp:     localSdp,
+		remoteSdp:    remoteSdp,
+		handlers:     handlers,
+		termCh:       make(chan struct{}),
+		migrations:   make(chan sessionMigration),
+		dispatchDone: make(chan struct{}),
+		logger:       logger,
+		state:        CallStateActive,
+		roster:       roster,
+
+		typingTimeout: defaultTypingTimeout,
+	}
+}
+
+// ID returns the call-id of this session.
+func (s *CallSession) ID() CallID {
+	return s.callID
+}
+
+// InitialMembers returns the members of the conference as listed by
+// the memberlists received during call setup, ordered by client-id.
+// Later changes are delivered to the memberlist handlers.
+func (s *CallSession) InitialMembers() []Member {
+	return s.initialMembers
+}
+
+// setEarly sets the messages received during call setup.
+func (s *CallSession) setEarly(early []MsgInterface) {
+	s.early = early
+	roster := NewRoster()
+	for _, msg := range early {
+		if m, ok := msg.(*MsgMemberlist); ok {
+			roster.Apply(m.Data)
+		}
+	}
+	s.initialMembers = roster.Members()
+}
+
+// RemoteSdp returns the latest sdp sent by the remote end.
+func (s *CallSession) RemoteSdp() Sdp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remoteSdp
+}
+
+// Snapshot returns the current state of the session.
+func (s *CallSession) Snapshot() CallSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return CallSnapshot{
+		CallID:    s.callID,
+		State:     s.state,
+		LocalSdp:  s.localSdp,
+		RemoteSdp: s.remoteSdp,
+		VideoOff:  s.videoOff,
+		OnHold:    s.onHold,
+	}
+}
+
+// State returns the current state of this session.
+func (s *CallSession) State() CallState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+func (s *CallSession) setState(state CallState) {
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+}
+
+// OnHold reports whether the call is currently on hold, either
+// by the local or the remote end.
+func (s *CallSession) OnHold() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.onHold
+}
+
+func (s *CallSession) setOnHold(onHold bool) {
+	s.mu.Lock()
+	s.onHold = onHold
+	s.mu.Unlock()
+}
+
+func (s *CallSession) active() bool {
+	return s.State() == CallStateActive
+}
+
+// start runs the dispatcher of this session as goroutine.
+func (s *CallSession) start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.removeListener = s.listenReconnects(s.sepp)
+	if s.watchdog != nil {
+		go s.watch(ctx, s.watchdog)
+	}
+	go func() {
+		s.conn().labelGoroutine("dispatcher")
+		defer close(s.dispatchDone)
+		defer s.watchdog.stop()
+		// the listener is replaced by migrations.
+		defer func() { s.removeListener() }()
+		s.dispatch(ctx)
+	}()
+}
+
+// listenReconnects resumes the call after reconnects of sepp, if
+// enabled. The returned function removes the listener.
+func (s *CallSession) listenReconnects(sepp *GoSepp) func() {
+	if !s.autoResume {
+		return func() {}
+	}
+	return sepp.addConnectListener(func(connected bool) {
+		// the session starts on an established connection, so
+		// every connect seen here is a reconnect.
+		if connected {
+			s.resume()
+		}
+	})
+}
+
+// conn returns the signaling connection of the session.
+func (s *CallSession) conn() *GoSepp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sepp
+}
+
+// sessionMigration is a connection the call was resumed on.
+type sessionMigration struct {
+	sepp  *GoSepp
+	inbox <-chan MsgInterface
+}
+
+// migrate switches the dispatcher to sepp and inbox. The previous
+// connection is no longer used once migrate returns.
+func (s *CallSession) migrate(ctx context.Context, sepp *GoSepp,
+	inbox <-chan MsgInterface) error {
+	select {
+	case s.migrations <- sessionMigration{sepp: sepp, inbox: inbox}:
+		return nil
+	case <-s.dispatchDone:
+		return ErrNoActiveCall
+	case <-ctx.Done():
+		return ctxError(ctx, "migrate")
+	}
+}
+
+// resume replays the call state after a reconnect, so the server view
+// matches the client.
+func (s *CallSession) resume() {
+	snapshot := s.Snapshot()
+	if snapshot.State != CallStateActive {
+		return
+	}
+	s.logger.Info("Resuming call %s.", s.callID)
+	if err := s.conn().SendMsg(MsgCallResume{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallResume,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgCallResumeData{
+			CallID: string(s.callID),
+			Sdp:    snapshot.LocalSdp},
+	}); err != nil {
+		s.logger.Warn("Failed to resume call [%s].", err)
+		return
+	}
+	if snapshot.VideoOff {
+		if err := s.TurnOffVideo(context.Background(), true); err != nil {
+			s.logger.Warn("Failed to restore video mute [%s].", err)
+		}
+	}
+}
+
+func (s *CallSession) setRemoteSdp(sdp Sdp) {
+	s.mu.Lock()
+	s.remoteSdp = sdp
+	s.mu.Unlock()
+}
+
+func (s *CallSession) dispatch(ctx context.Context) {
+	if s.onDone != nil {
+		defer s.onDone()
+	}
+	var idle *idleTimer
+	if s.idleTimeout > 0 {
+		idle = newIdleTimer(s.idleTimeout, s.idleCondition, s.from)
+		defer idle.stop()
+	}
+	var maxDuration <-chan time.Time
+	if s.maxDuration > 0 {
+		timer := time.NewTimer(s.maxDuration)
+		defer timer.Stop()
+		maxDuration = timer.C
+	}
+	for _, msg := range s.early {
+		idle.observe(msg)
+		if s.handleWatched(msg) {
+			return
+		}
+	}
+	s.early = nil
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-idle.C():
+			// the call_terminated reply ends the session.
+			s.logger.Info("Call idle for %s. Terminating.", s.idleTimeout)
+			if err := s.sendTerminate(TerminatedIdle); err != nil {
+				s.logger.Warn("Failed to terminate idle call: %s", err)
+			}
+		case <-maxDuration:
+			s.logger.Info("Call exceeded %s. Terminating.", s.maxDuration)
+			if err := s.sendTerminate(TerminatedMaxDuration); err != nil {
+				s.logger.Warn("Failed to terminate call: %s", err)
+			}
+		case m := <-s.migrations:
+			s.removeListener()
+			s.mu.Lock()
+			s.sepp = m.sepp
+			s.mu.Unlock()
+			s.inbox = m.inbox
+			s.removeListener = s.listenReconnects(m.sepp)
+		case msg, ok := <-s.inbox:
+			if !ok {
+				s.logger.Info("Channel closed. Stopping dispatch")
+				return
+			}
+			if ctx.Err() != nil {
+				// torn down while the message was pending.
+				return
+			}
+			idle.observe(msg)
+			if s.handleWatched(msg) {
+				return
+			}
+		}
+	}
+}
+
+// handleWatched calls handle, keeping the watchdog informed.
+func (s *CallSession) handleWatched(msg MsgInterface) bool {
+	s.watchdog.busy()
+	defer s.watchdog.idle()
+	return s.handle(msg)
+}
+
+// handle dispatches msg to the handlers. It returns true once the call
+// is over.
+func (s *CallSession) handle(msg MsgInterface) bool {
+	switch m := msg.(type) {
+	case *MsgCallTerminate:
+		// The remote end hung up. Confirm the termination.
+		if err := s.conn().SendMsg(MsgCallTerminated{
+			MsgBase: MsgBase{
+				Type: MsgTypeCallTerminated,
+				From: s.from,
+				To:   s.to,
+			},
+			Data: MsgCallTerminatedData{
+				CallID: string(s.callID)},
+		}); err != nil {
+			s.logger.Warn("Failed to confirm termination: %s", err)
+		}
+		s.terminated()
+		return true
+	case *MsgCallTerminated:
+		s.terminated()
+		// The session is over. Stop consuming, so a
+		// following session can take over.
+		return true
+	case *MsgSdpUpdate:
+		s.setRemoteSdp(m.Data.Sdp)
+		if s.handlers.sdpUpdate != nil {
+			s.handlers.sdpUpdate(m.Data.Sdp)
+		}
+	case *MsgCallResumed:
+		if len(m.Data.Sdp.Sdp) > 0 {
+			s.setRemoteSdp(m.Data.Sdp)
+			if s.handlers.sdpUpdate != nil {
+				s.handlers.sdpUpdate(m.Data.Sdp)
+			}
+		}
+	case *MsgMemberlist:
+		if s.handlers.memberlist != nil {
+			s.handlers.memberlist(m.Data)
+		}
+		if s.roster != nil {
+			s.roster.Apply(m.Data)
+		}
+	case *MsgSourceUpdate:
+		if s.handlers.sourceUpdate != nil {
+			s.handlers.sourceUpdate(m.Data)
+		}
+		if s.handlers.sourceDiff != nil {
+			diff := DiffSourceUpdate(s.lastSources, m.Data)
+			s.lastSources = m.Data
+			if !diff.Empty() {
+				s.handlers.sourceDiff(diff)
+			}
+		}
+	case *MsgCallHold:
+		s.setOnHold(m.Data.On)
+		if s.handlers.hold != nil {
+			s.handlers.hold(m.Data.On)
+		}
+	case *MsgCallTransfer:
+		if s.handlers.transfer != nil {
+			s.handlers.transfer(m.Data.Target)
+		}
+	case *MsgTyping:
+		if s.handlers.typing != nil {
+			s.handlers.typing(m.Data.ClientID, m.Data.On)
+		}
+	case *MsgChatReceipt:
+		if s.handlers.chatReceipt != nil {
+			s.handlers.chatReceipt(m.Data)
+		}
+	case *MsgError:
+		s.logger.Warn("Server error %d: %s", m.Data.Code, m.Data.Reason)
+		if s.handlers.err != nil {
+			s.handlers.err(serverError(m))
+		}
+	}
+	return false
+}
+
+// terminated ends the session. Only the first call has an effect, so the
+// handlers are called once.
+func (s *CallSession) terminated() {
+	s.termOnce.Do(func() {
+		s.setState(CallStateTerminated)
+		close(s.termCh)
+		if s.handlers.termination != nil {
+			s.handlers.termination()
+		}
+		if s.handlers.terminationReason != nil {
+			s.handlers.terminationReason(s.TerminationReason())
+		}
+	})
+}
+
+// Done returns a channel which is closed once the call is terminated.
+func (s *CallSession) Done() <-chan struct{} {
+	return s.termCh
+}
+
+// TerminationReason returns why the call ended. Only meaningful once
+// the session is terminated.
+func (s *CallSession) TerminationReason() TerminationReason {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reason == nil {
+		return TerminatedRemotely
+	}
+	return *s.reason
+}
+
+// sendTerminate requests the termination of the call for reason. The
+// first requested reason is kept.
+func (s *CallSession) sendTerminate(reason TerminationReason) error {
+	s.mu.Lock()
+	if s.reason == nil {
+		s.reason = &reason
+	}
+	s.mu.Unlock()
+	if err := s.conn().SendMsg(MsgCallTerminate{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallTerminate,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgCallTerminateData{
+			CallID: string(s.callID)},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Terminate the call and wait until the remote end
+// confirmed the termination.
+func (s *CallSession) Terminate(ctx context.Context) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.sendTerminate(TerminatedLocally); err != nil {
+		return err
+	}
+
+	// wait for terminated
+	select {
+	case <-ctx.Done():
+		return ctxError(ctx, "wait for terminated")
+	case <-s.termCh:
+	}
+
+	return nil
+}
+
+// UpdateSDP sends and sdp update to the remote end.
+func (s *CallSession) UpdateSDP(ctx context.Context, sdp Sdp) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgSdpUpdate{
+		MsgBase: MsgBase{
+			Type: MsgTypeSdpUpdate,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgSdpUpdateData{
+			CallID: string(s.callID),
+			Sdp:    sdp},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	s.mu.Lock()
+	s.localSdp = sdp
+	s.mu.Unlock()
+	return nil
+}
+
+// TurnOffVideo mutes or unmute video
+func (s *CallSession) TurnOffVideo(ctx context.Context, off bool) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgMuteVideo{
+		MsgBase: MsgBase{
+			Type: MsgTypeMuteVideo,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgMuteVideoData{
+			CallID: string(s.callID),
+			On:     off},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	s.mu.Lock()
+	s.videoOff = off
+	s.mu.Unlock()
+	return nil
+}
+
+// UpdateDisplayName changes the name and avatar shown to the other
+// participants. Fails with ErrUnsupportedMsgType if the server doesn't
+// support display_name_update.
+func (s *CallSession) UpdateDisplayName(ctx context.Context, name, avatarURL string) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if !s.conn().Supports(MsgTypeDisplayNameUpdate) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMsgType, MsgTypeDisplayNameUpdate)
+	}
+	if err := s.conn().SendMsg(MsgDisplayNameUpdate{
+		MsgBase: MsgBase{
+			Type: MsgTypeDisplayNameUpdate,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgDisplayNameUpdateData{
+			CallID:      string(s.callID),
+			DisplayName: name,
+			AvatarURL:   avatarURL},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Hold puts the call on hold.
+func (s *CallSession) Hold(ctx context.Context) error {
+	return s.sendHold(true)
+}
+
+// Unhold resumes a call previously put on hold.
+func (s *CallSession) Unhold(ctx context.Context) error {
+	return s.sendHold(false)
+}
+
+func (s *CallSession) sendHold(on bool) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgCallHold{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallHold,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgCallHoldData{
+			CallID: string(s.callID),
+			On:     on},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	s.setOnHold(on)
+	return nil
+}
+
+// Transfer the call to the conference target.
+func (s *CallSession) Transfer(ctx context.Context, target string) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgCallTransfer{
+		MsgBase: MsgBase{
+			Type: MsgTypeCallTransfer,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgCallTransferData{
+			CallID: string(s.callID),
+			Target: target},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// MarkChatDelivered sends a delivery receipt for the chat message chatID.
+func (s *CallSession) MarkChatDelivered(ctx context.Context, chatID string) error {
+	return s.sendChatReceipt(chatID, ChatReceiptDelivered)
+}
+
+// MarkChatRead sends a read receipt for the chat message chatID.
+func (s *CallSession) MarkChatRead(ctx context.Context, chatID string) error {
+	return s.sendChatReceipt(chatID, ChatReceiptRead)
+}
+
+func (s *CallSession) sendChatReceipt(chatID, status string) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgChatReceipt{
+		MsgBase: MsgBase{
+			Type: MsgTypeChatReceipt,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgChatReceiptData{
+			CallID:   string(s.callID),
+			ID:       chatID,
+			ClientID: s.from,
+			Status:   status},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Typing signals that the user is typing. Call it on every keystroke:
+// typing-start is only sent on the first call, typing-stop is sent
+// automatically after the typing timeout without further calls.
+func (s *CallSession) Typing(ctx context.Context) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	s.mu.Lock()
+	if s.typingTimer != nil {
+		s.typingTimer.Reset(s.typingTimeout)
+		s.mu.Unlock()
+		return nil
+	}
+	timer := time.AfterFunc(s.typingTimeout, func() {
+		if err := s.StopTyping(context.Background()); err != nil &&
+			err != ErrNoActiveCall {
+			s.logger.Warn("Failed to stop typing [%s].", err)
+		}
+	})
+	s.typingTimer = timer
+	s.mu.Unlock()
+	if err := s.sendTyping(true); err != nil {
+		// not typing, so the next call sends typing-start again.
+		s.mu.Lock()
+		if s.typingTimer == timer {
+			timer.Stop()
+			s.typingTimer = nil
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// StopTyping sends typing-stop, e.g. when the chat message was sent.
+func (s *CallSession) StopTyping(ctx context.Context) error {
+	s.mu.Lock()
+	if s.typingTimer == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.typingTimer.Stop()
+	s.typingTimer = nil
+	s.mu.Unlock()
+	return s.sendTyping(false)
+}
+
+func (s *CallSession) sendTyping(on bool) error {
+	if !s.active() {
+		return ErrNoActiveCall
+	}
+	if err := s.conn().SendMsg(MsgTyping{
+		MsgBase: MsgBase{
+			Type: MsgTypeTyping,
+			From: s.from,
+			To:   s.to,
+		},
+		Data: MsgTypingData{
+			CallID:   string(s.callID),
+			ClientID: s.from,
+			On:       on},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// close stops the dispatcher without terminating the call.
+func (s *CallSession) close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
@@ -0,0 +1,82 @@
+package gosepp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// SignatureField is the json field carrying the message signature.
+const SignatureField = "sig"
+
+// ErrInvalidSignature is returned if a message signature is missing or
+// does not match.
+var ErrInvalidSignature = errors.New("invalid message signature")
+
+// WithMessageSigning signs every outgoing message with key and drops
+// received messages without a valid signature. The signature is an
+// HMAC-SHA256 over the canonicalized json, see SignMsg.
+func WithMessageSigning(key []byte) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.signingKey = key
+	}
+}
+
+// canonicalize decodes a json object and returns it without signature,
+// along with its canonical encoding: object keys sorted and no
+// insignificant whitespace.
+func canonicalize(data []byte) (map[string]interface{}, []byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, nil, err
+	}
+	delete(obj, SignatureField)
+	canonical, err := json.Marshal(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, canonical, nil
+}
+
+func signature(key, canonical []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(canonical)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignMsg returns the json encoded message data with the signature
+// added in the SignatureField.
+func SignMsg(key, data []byte) ([]byte, error) {
+	obj, canonical, err := canonicalize(data)
+	if err != nil {
+		return nil, err
+	}
+	obj[SignatureField] = signature(key, canonical)
+	return json.Marshal(obj)
+}
+
+// VerifyMsg checks the signature of the json encoded message data.
+func VerifyMsg(key, data []byte) error {
+	var signed struct {
+		Sig string `json:"sig"`
+	}
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return err
+	}
+	if len(signed.Sig) == 0 {
+		return ErrInvalidSignature
+	}
+	_, canonical, err := canonicalize(data)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signed.Sig), []byte(signature(key, canonical))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSignAndVerifyMsg(t *testing.T) {
+	key := []byte("secret")
+	signed, err := SignMsg(key, []byte(`{"type":"chat","data":{"content":"hi","n":1.50}}`))
+	if err != nil {
+		t.Fatalf("sign failed: %s", err)
+	}
+	if err := VerifyMsg(key, signed); err != nil {
+		t.Fatalf("verify failed: %s", err)
+	}
+
+	// key order and whitespace don't matter
+	var sig struct {
+		Sig string `json:"sig"`
+	}
+	if err := json.Unmarshal(signed, &sig); err != nil {
+		t.Fatalf("unmarshal failed: %s", err)
+	}
+	reordered := []byte(`{ "sig": "` + sig.Sig +
+		`", "data": {"n":1.50, "content":"hi"}, "type":"chat" }`)
+	if err := VerifyMsg(key, reordered); err != nil {
+		t.Fatalf("verify of reordered message failed: %s", err)
+	}
+
+	tampered := bytes.Replace(signed, []byte("hi"), []byte("ho"), 1)
+	if err := VerifyMsg(key, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := VerifyMsg(key, []byte(`{"type":"chat"}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for unsigned message, got %v", err)
+	}
+}
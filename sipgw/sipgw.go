@@ -0,0 +1,298 @@
+// Package sipgw maps the sepp call lifecycle to SIP semantics, for
+// building SIP to eyeson gateways.
+//
+// The package does not implement SIP itself. An adapter around the SIP
+// stack of choice implements Stack and Dialog, and the gateway translates
+//
+//	call_start      <-> INVITE
+//	call_accepted   <-> 200 OK
+//	call_rejected   <-> 4xx-6xx final response
+//	call_terminate  <-> BYE
+//	sdp_update      <-> re-INVITE
+//
+// A re-INVITE always carries an offer. It's forwarded as sdp_update of
+// type offer, and the sdp_update of type answer sent in reply is
+// returned for the 200 OK. Likewise an sdp_update offer is sent as
+// re-INVITE and answered with the sdp of its 200 OK.
+package sipgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+)
+
+// Dialog is an established SIP dialog.
+type Dialog interface {
+	// ReInvite sends an updated session description offer within the
+	// dialog and returns the answer of the 2xx final response.
+	ReInvite(ctx context.Context, offer string) (string, error)
+	// Bye terminates the dialog.
+	Bye(ctx context.Context) error
+}
+
+// Stack places outgoing SIP calls.
+type Stack interface {
+	// Invite sends an INVITE with the sdp offer to uri. On a 2xx final
+	// response the established dialog and the sdp answer are returned,
+	// on any other final response a *StatusError.
+	Invite(ctx context.Context, uri string, offer string) (Dialog, string, error)
+}
+
+// StatusError is a non-2xx final SIP response.
+type StatusError struct {
+	Code   int
+	Reason string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("sip status %d %s", e.Code, e.Reason)
+}
+
+// errReInviteInProgress is returned by ReInvite while the answer to a
+// previous re-INVITE is pending.
+var errReInviteInProgress = errors.New("re-INVITE in progress")
+
+// transactionTimeout bounds the SIP requests sent for sepp events, like
+// timer B of an INVITE transaction.
+const transactionTimeout = 32 * time.Second
+
+// Default codes used when no specific mapping exists.
+const (
+	StatusServerInternalError = 500
+	StatusDecline             = 603
+)
+
+// StatusFromRejectCode maps a sepp reject code to a SIP final response
+// code. Reject codes in the SIP failure range are passed through.
+func StatusFromRejectCode(rejectCode int) int {
+	if rejectCode >= 400 && rejectCode < 700 {
+		return rejectCode
+	}
+	return StatusDecline
+}
+
+// RejectCodeFromStatus maps a SIP final response code to a sepp reject
+// code.
+func RejectCodeFromStatus(status int) int {
+	if status >= 400 && status < 700 {
+		return status
+	}
+	return StatusServerInternalError
+}
+
+// Leg ties a sepp call session to a SIP dialog. Terminating one side
+// terminates the other.
+type Leg struct {
+	session *gosepp.CallSession
+	dialog  Dialog
+	logger  gosepp.Logger
+	// hangup ensures only the first side hanging up is propagated.
+	hangup sync.Once
+
+	mu sync.Mutex
+	// answer receives the sepp answer to a forwarded re-INVITE, while
+	// one is pending.
+	answer chan string
+}
+
+func newLeg(logger gosepp.Logger) *Leg {
+	if logger == nil {
+		logger = &nopLogger{}
+	}
+	return &Leg{logger: logger}
+}
+
+// Session returns the sepp side of the leg.
+func (l *Leg) Session() *gosepp.CallSession {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.session
+}
+
+func (l *Leg) setSession(session *gosepp.CallSession) {
+	l.mu.Lock()
+	l.session = session
+	l.mu.Unlock()
+}
+
+// Dialog returns the SIP side of the leg.
+func (l *Leg) Dialog() Dialog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dialog
+}
+
+// Bye must be called when the SIP side sent a BYE. It terminates the
+// sepp call.
+func (l *Leg) Bye(ctx context.Context) error {
+	var err error
+	l.hangup.Do(func() {
+		err = l.Session().Terminate(ctx)
+	})
+	return err
+}
+
+// ReInvite must be called when the SIP side sent a re-INVITE. The offer
+// is forwarded as sdp_update and the answer for the 200 OK is returned.
+func (l *Leg) ReInvite(ctx context.Context, offer string) (string, error) {
+	answer := make(chan string, 1)
+	l.mu.Lock()
+	if l.answer != nil {
+		l.mu.Unlock()
+		return "", errReInviteInProgress
+	}
+	l.answer = answer
+	session := l.session
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.answer = nil
+		l.mu.Unlock()
+	}()
+
+	if err := session.UpdateSDP(ctx, gosepp.Sdp{SdpType: "offer", Sdp: offer}); err != nil {
+		return "", err
+	}
+	select {
+	case sdp := <-answer:
+		return sdp, nil
+	case <-session.Done():
+		return "", gosepp.ErrNoActiveCall
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// onSeppTerminated sends a BYE if the sepp side hung up first. It's
+// sent in the background, so the call's handlers aren't blocked.
+func (l *Leg) onSeppTerminated() {
+	l.hangup.Do(func() {
+		dialog := l.Dialog()
+		if dialog == nil {
+			// ToSIP sends the BYE once the SIP call is established.
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), transactionTimeout)
+			defer cancel()
+			if err := dialog.Bye(ctx); err != nil {
+				l.logger.Warn("Failed to send BYE [%s].", err)
+			}
+		}()
+	})
+}
+
+// onSeppSdpUpdate forwards a sdp_update offer as re-INVITE and replies
+// with its answer, in the background so the call's handlers aren't
+// blocked. An answer completes a pending ReInvite.
+func (l *Leg) onSeppSdpUpdate(sdp gosepp.Sdp) {
+	l.mu.Lock()
+	session, pending := l.session, l.answer
+	l.mu.Unlock()
+	if sdp.SdpType != "offer" {
+		if pending == nil {
+			l.logger.Warn("Dropping unexpected %s sdp.", sdp.SdpType)
+			return
+		}
+		select {
+		case pending <- sdp.Sdp:
+		default:
+		}
+		return
+	}
+	if session == nil {
+		l.logger.Warn("Dropping sdp offer received during call setup.")
+		return
+	}
+	dialog := l.Dialog()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionTimeout)
+		defer cancel()
+		answer, err := dialog.ReInvite(ctx, sdp.Sdp)
+		if err != nil {
+			l.logger.Warn("Failed to send re-INVITE [%s].", err)
+			return
+		}
+		if err := session.UpdateSDP(ctx,
+			gosepp.Sdp{SdpType: "answer", Sdp: answer}); err != nil {
+			l.logger.Warn("Failed to send sdp answer [%s].", err)
+		}
+	}()
+}
+
+// ToSIP places a SIP call to uri for an incoming sepp call. The sepp
+// call is accepted with the SIP answer, or rejected with the mapped
+// status if the SIP call fails.
+func ToSIP(ctx context.Context, ic *gosepp.IncomingCall, stack Stack,
+	uri string, logger gosepp.Logger) (*Leg, error) {
+	l := newLeg(logger)
+	ic.SetTerminatedHandler(l.onSeppTerminated)
+	ic.SetSDPUpdateHandler(l.onSeppSdpUpdate)
+
+	dialog, answer, err := stack.Invite(ctx, uri, ic.Data().Sdp.Sdp)
+	if err != nil {
+		code := StatusServerInternalError
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			code = RejectCodeFromStatus(statusErr.Code)
+		}
+		if rejectErr := ic.Reject(ctx, code); rejectErr != nil {
+			l.logger.Warn("Failed to reject call [%s].", rejectErr)
+		}
+		return nil, err
+	}
+	l.mu.Lock()
+	l.dialog = dialog
+	l.mu.Unlock()
+
+	session, err := ic.Accept(ctx, gosepp.Sdp{SdpType: "answer", Sdp: answer})
+	if err != nil {
+		if byeErr := dialog.Bye(ctx); byeErr != nil {
+			l.logger.Warn("Failed to send BYE [%s].", byeErr)
+		}
+		return nil, err
+	}
+	l.setSession(session)
+	return l, nil
+}
+
+// FromSIP starts a sepp call for an incoming SIP INVITE with the sdp
+// offer. On success the sdp answer for the 200 OK is returned. A
+// *StatusError carrying the final response to send is returned if the
+// sepp call was rejected.
+//
+// FromSIP installs its own terminated and sdp-update handlers on call.
+func FromSIP(ctx context.Context, call *gosepp.Call, dialog Dialog,
+	offer, displayname string, logger gosepp.Logger) (*Leg, string, error) {
+	l := newLeg(logger)
+	l.dialog = dialog
+	call.SetTerminatedHandler(l.onSeppTerminated)
+	call.SetSDPUpdateHandler(l.onSeppSdpUpdate)
+
+	session, err := call.StartSession(ctx,
+		gosepp.Sdp{SdpType: "offer", Sdp: offer}, displayname)
+	if err != nil {
+		var rejected *gosepp.CallRejectedError
+		if errors.As(err, &rejected) {
+			return nil, "", &StatusError{
+				Code: StatusFromRejectCode(rejected.RejectCode)}
+		}
+		return nil, "", &StatusError{Code: StatusServerInternalError,
+			Reason: err.Error()}
+	}
+	l.setSession(session)
+	return l, session.RemoteSdp().Sdp, nil
+}
+
+type nopLogger struct{}
+
+func (nl *nopLogger) Error(format string, v ...interface{}) {}
+func (nl *nopLogger) Warn(format string, v ...interface{})  {}
+func (nl *nopLogger) Info(format string, v ...interface{})  {}
+func (nl *nopLogger) Debug(format string, v ...interface{}) {}
+func (nl *nopLogger) Trace(format string, v ...interface{}) {}
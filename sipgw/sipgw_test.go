@@ -0,0 +1,387 @@
+package sipgw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+	"github.com/gorilla/websocket"
+)
+
+func TestStatusMapping(t *testing.T) {
+	tests := []struct {
+		rejectCode int
+		status     int
+	}{
+		{486, 486},
+		{404, 404},
+		{0, StatusDecline},
+		{200, StatusDecline},
+	}
+	for _, tt := range tests {
+		if got := StatusFromRejectCode(tt.rejectCode); got != tt.status {
+			t.Errorf("StatusFromRejectCode(%d) = %d, want %d", tt.rejectCode, got, tt.status)
+		}
+	}
+	if got := RejectCodeFromStatus(302); got != StatusServerInternalError {
+		t.Errorf("RejectCodeFromStatus(302) = %d", got)
+	}
+}
+
+// relay forwards every frame received on one connection to all other
+// connections, so a caller and an answerer can talk.
+type relay struct {
+	upgrader websocket.Upgrader
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+}
+
+func (r *relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	conn, err := r.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r.mu.Lock()
+	r.conns = append(r.conns, conn)
+	r.mu.Unlock()
+	for {
+		mt, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		for _, other := range r.conns {
+			if other != conn {
+				other.WriteMessage(mt, data)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+type fakeDialog struct {
+	answer  string
+	offers  chan string
+	byeSent chan struct{}
+	// stall, if set, blocks re-INVITEs until closed.
+	stall chan struct{}
+}
+
+func newFakeDialog(answer string) *fakeDialog {
+	return &fakeDialog{answer: answer, offers: make(chan string, 1),
+		byeSent: make(chan struct{})}
+}
+
+func (d *fakeDialog) ReInvite(ctx context.Context, offer string) (string, error) {
+	d.offers <- offer
+	if d.stall != nil {
+		select {
+		case <-d.stall:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return d.answer, nil
+}
+
+func (d *fakeDialog) Bye(ctx context.Context) error {
+	close(d.byeSent)
+	return nil
+}
+
+type fakeStack struct {
+	dialog *fakeDialog
+	answer string
+	err    error
+
+	mu    sync.Mutex
+	offer string
+}
+
+func (s *fakeStack) Invite(ctx context.Context, uri string, offer string) (Dialog, string, error) {
+	s.mu.Lock()
+	s.offer = offer
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, "", s.err
+	}
+	return s.dialog, s.answer, nil
+}
+
+// gateway starts a relay with an answerer, whose incoming calls are
+// passed to ToSIP, and returns a caller.
+func gateway(t *testing.T, stack Stack) (*gosepp.Call, <-chan *Leg) {
+	srv := httptest.NewServer(&relay{})
+	t.Cleanup(srv.Close)
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	sepp, err := gosepp.NewGoSepp(endpoint, "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	t.Cleanup(sepp.Stop)
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	answerer := gosepp.NewAnswerer(sepp, nil)
+	legs := make(chan *Leg, 1)
+	go func() {
+		ic, ok := <-answerer.IncomingCh()
+		if !ok {
+			return
+		}
+		leg, err := ToSIP(context.Background(), ic, stack, "sip:bob@example.com", nil)
+		if err == nil {
+			legs <- leg
+		}
+	}()
+
+	call, err := gosepp.NewCall(&gosepp.CallInfo{SigEndpoint: endpoint,
+		ClientID: "client", ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	t.Cleanup(call.Close)
+	return call, legs
+}
+
+func waitLeg(t *testing.T, legs <-chan *Leg) *Leg {
+	select {
+	case leg := <-legs:
+		return leg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for leg")
+	}
+	return nil
+}
+
+func TestToSIPAcceptAndBye(t *testing.T) {
+	dialog := newFakeDialog("")
+	stack := &fakeStack{dialog: dialog, answer: "sip-answer"}
+	call, legs := gateway(t, stack)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, gosepp.Sdp{SdpType: "offer", Sdp: "sepp-offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	stack.mu.Lock()
+	offer := stack.offer
+	stack.mu.Unlock()
+	if offer != "sepp-offer" || session.RemoteSdp().Sdp != "sip-answer" {
+		t.Fatalf("unexpected sdps %q %q", offer, session.RemoteSdp().Sdp)
+	}
+	leg := waitLeg(t, legs)
+
+	// BYE from the SIP side terminates the sepp call.
+	if err := leg.Bye(ctx); err != nil {
+		t.Fatalf("bye failed: %s", err)
+	}
+	select {
+	case <-session.Done():
+	case <-ctx.Done():
+		t.Fatalf("sepp call not terminated")
+	}
+	select {
+	case <-dialog.byeSent:
+		t.Fatalf("BYE echoed to the SIP side")
+	default:
+	}
+}
+
+func TestToSIPSeppHangup(t *testing.T) {
+	dialog := newFakeDialog("")
+	call, legs := gateway(t, &fakeStack{dialog: dialog, answer: "sip-answer"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, gosepp.Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	waitLeg(t, legs)
+	if err := session.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	select {
+	case <-dialog.byeSent:
+	case <-ctx.Done():
+		t.Fatalf("no BYE sent")
+	}
+}
+
+func TestToSIPReject(t *testing.T) {
+	call, _ := gateway(t, &fakeStack{err: &StatusError{Code: 486, Reason: "Busy Here"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := call.StartSession(ctx, gosepp.Sdp{SdpType: "offer"}, "bot")
+	var rejected *gosepp.CallRejectedError
+	if !errors.As(err, &rejected) || rejected.RejectCode != 486 {
+		t.Fatalf("expected reject code 486, got %v", err)
+	}
+}
+
+func TestToSIPReInvite(t *testing.T) {
+	dialog := newFakeDialog("sip-answer-2")
+	call, legs := gateway(t, &fakeStack{dialog: dialog, answer: "sip-answer"})
+	updates := make(chan gosepp.Sdp, 1)
+	call.SetSDPUpdateHandler(func(sdp gosepp.Sdp) { updates <- sdp })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, gosepp.Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	leg := waitLeg(t, legs)
+
+	// sepp offer -> re-INVITE, answered with the 200 OK sdp.
+	if err := session.UpdateSDP(ctx, gosepp.Sdp{SdpType: "offer", Sdp: "sepp-offer-2"}); err != nil {
+		t.Fatalf("update failed: %s", err)
+	}
+	if offer := <-dialog.offers; offer != "sepp-offer-2" {
+		t.Fatalf("unexpected re-INVITE offer %q", offer)
+	}
+	select {
+	case sdp := <-updates:
+		if sdp.SdpType != "answer" || sdp.Sdp != "sip-answer-2" {
+			t.Fatalf("unexpected answer %+v", sdp)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for answer")
+	}
+
+	// re-INVITE -> sepp offer, whose answer goes into the 200 OK.
+	answers := make(chan string, 1)
+	go func() {
+		answer, err := leg.ReInvite(ctx, "sip-offer-3")
+		if err != nil {
+			t.Errorf("re-INVITE failed: %s", err)
+		}
+		answers <- answer
+	}()
+	select {
+	case sdp := <-updates:
+		if sdp.SdpType != "offer" || sdp.Sdp != "sip-offer-3" {
+			t.Fatalf("unexpected offer %+v", sdp)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for offer")
+	}
+	if err := session.UpdateSDP(ctx, gosepp.Sdp{SdpType: "answer", Sdp: "sepp-answer-3"}); err != nil {
+		t.Fatalf("update failed: %s", err)
+	}
+	if answer := <-answers; answer != "sepp-answer-3" {
+		t.Fatalf("unexpected 200 OK answer %q", answer)
+	}
+}
+
+func TestToSIPStalledReInvite(t *testing.T) {
+	dialog := newFakeDialog("sip-answer-2")
+	dialog.stall = make(chan struct{})
+	defer close(dialog.stall)
+	call, legs := gateway(t, &fakeStack{dialog: dialog, answer: "sip-answer"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := call.StartSession(ctx, gosepp.Sdp{SdpType: "offer"}, "bot")
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	waitLeg(t, legs)
+	if err := session.UpdateSDP(ctx, gosepp.Sdp{SdpType: "offer", Sdp: "sepp-offer-2"}); err != nil {
+		t.Fatalf("update failed: %s", err)
+	}
+	<-dialog.offers
+
+	// the pending re-INVITE doesn't hold up the hangup.
+	if err := session.Terminate(ctx); err != nil {
+		t.Fatalf("terminate failed: %s", err)
+	}
+	select {
+	case <-dialog.byeSent:
+	case <-ctx.Done():
+		t.Fatalf("no BYE sent")
+	}
+}
+
+func TestFromSIP(t *testing.T) {
+	srv := httptest.NewServer(&relay{})
+	defer srv.Close()
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	sepp, err := gosepp.NewGoSepp(endpoint, "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	answerer := gosepp.NewAnswerer(sepp, nil)
+	go func() {
+		ic := <-answerer.IncomingCh()
+		ic.Accept(context.Background(), gosepp.Sdp{SdpType: "answer", Sdp: "sepp-answer"})
+	}()
+
+	call, err := gosepp.NewCall(&gosepp.CallInfo{SigEndpoint: endpoint,
+		ClientID: "client", ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, answer, err := FromSIP(ctx, call, newFakeDialog(""), "sip-offer", "alice", nil)
+	if err != nil {
+		t.Fatalf("FromSIP failed: %s", err)
+	}
+	if answer != "sepp-answer" {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+}
+
+func TestFromSIPReject(t *testing.T) {
+	srv := httptest.NewServer(&relay{})
+	defer srv.Close()
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	sepp, err := gosepp.NewGoSepp(endpoint, "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	answerer := gosepp.NewAnswerer(sepp, nil)
+	go func() {
+		ic := <-answerer.IncomingCh()
+		ic.Reject(context.Background(), 486)
+	}()
+
+	call, err := gosepp.NewCall(&gosepp.CallInfo{SigEndpoint: endpoint,
+		ClientID: "client", ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err = FromSIP(ctx, call, newFakeDialog(""), "sip-offer", "alice", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 486 {
+		t.Fatalf("expected status 486, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var errSniff = errors.New("can't sniff message type")
+
+// sniffType returns the top-level "type" field of the json object data
+// without decoding the rest, so a message is decoded in a single pass.
+// Like encoding/json, keys match case-insensitively and the last of
+// duplicate keys wins. It falls back to encoding/json for input it can't
+// scan, e.g. escaped keys.
+func sniffType(data []byte) (string, error) {
+	if msgType, err := scanType(data); err == nil {
+		return msgType, nil
+	}
+	var msgBase MsgBase
+	if err := json.Unmarshal(data, &msgBase); err != nil {
+		return "", err
+	}
+	return msgBase.Type, nil
+}
+
+func scanType(data []byte) (string, error) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return "", errSniff
+	}
+	i++
+	var msgType []byte
+	for {
+		i = skipSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return "", errSniff
+		}
+		key, next, ok := scanPlainString(data, i)
+		if !ok {
+			return "", errSniff
+		}
+		i = skipSpace(data, next)
+		if i >= len(data) || data[i] != ':' {
+			return "", errSniff
+		}
+		i = skipSpace(data, i+1)
+		if bytes.EqualFold(key, []byte("type")) && (i >= len(data) || data[i] != 'n') {
+			value, next, ok := scanPlainString(data, i)
+			if !ok {
+				return "", errSniff
+			}
+			msgType, i = value, next
+		} else {
+			// skip other values. A null type keeps the previous one, as
+			// with encoding/json.
+			if i = skipValue(data, i); i < 0 {
+				return "", errSniff
+			}
+		}
+		i = skipSpace(data, i)
+		if i >= len(data) {
+			return "", errSniff
+		}
+		switch data[i] {
+		case ',':
+			i++
+		case '}':
+			return string(msgType), nil
+		default:
+			return "", errSniff
+		}
+	}
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanPlainString returns the string starting at data[i] if it has no
+// escape sequences, and the index following it.
+func scanPlainString(data []byte, i int) ([]byte, int, bool) {
+	if i >= len(data) || data[i] != '"' {
+		return nil, 0, false
+	}
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			return nil, 0, false
+		case '"':
+			return data[i+1 : j], j + 1, true
+		}
+	}
+	return nil, 0, false
+}
+
+// skipValue returns the index following the json value at data[i], or
+// -1 if it is malformed.
+func skipValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch data[i] {
+	case '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1
+			}
+		}
+		return -1
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			case '"':
+				if j = skipValue(data, j); j < 0 {
+					return -1
+				}
+				j--
+			}
+		}
+		return -1
+	default:
+		// number, true, false or null
+		j := i
+		for j < len(data) {
+			switch data[j] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return j
+			}
+			j++
+		}
+		return j
+	}
+}
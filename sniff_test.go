@@ -0,0 +1,65 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSniffType(t *testing.T) {
+	for data, want := range map[string]string{
+		`{"type":"chat"}`: "chat",
+		` { "data" : {"type":"nested", "a":[1,"}",{"b":null}]}, "n": -1.5e3, "ok": true, "type" : "memberlist" }`: "memberlist",
+		`{"s":"escaped \" quote","type":"chat"}`: "chat",
+		`{"ty\u0070e":"chat"}`:                   "chat",
+		`{"type":"ch\u0061t"}`:                   "chat",
+		`{"data":{}}`:                            "",
+		// the last of duplicate keys wins, as with encoding/json.
+		`{"type":"chat","type":"call_terminate"}`: "call_terminate",
+		`{"type":"chat","type":null}`:             "chat",
+		`{"type":"chat","ty\u0070e":"ping"}`:      "ping",
+		// keys match case-insensitively.
+		`{"Type":"chat","data":{}}`:     "chat",
+		`{"type":"chat","Type":"ping"}`: "ping",
+		`{"TYPE":"ping","type":null}`:   "ping",
+	} {
+		got, err := sniffType([]byte(data))
+		if err != nil || got != want {
+			t.Fatalf("sniffType(%s) = %q, %v", data, got, err)
+		}
+		var msgBase MsgBase
+		if err := json.Unmarshal([]byte(data), &msgBase); err != nil || msgBase.Type != want {
+			t.Fatalf("encoding/json disagrees on %s: %q", data, msgBase.Type)
+		}
+	}
+	if msg, err := DecodeMsg([]byte(`{"Type":"chat","data":{}}`)); err != nil ||
+		msg.GetType() != MsgTypeChat {
+		t.Fatalf("failed to decode capitalized type key: %v", err)
+	}
+	if _, err := sniffType([]byte(`[1]`)); err == nil {
+		t.Fatalf("expected error for non-object")
+	}
+}
+
+var benchMemberlist = []byte(`{"type":"memberlist","msg_id":"1","from":"conf","to":"client",` +
+	`"data":{"call_id":"call","count":3,"add":[{"cid":"a","p":"web"},{"cid":"b"}],` +
+	`"del":["c"],"media":[{"mid":"m","playid":"p"}]}}`)
+
+func BenchmarkDecodeMsg(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeMsg(benchMemberlist); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeMsgPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg, err := decodePooled(benchMemberlist, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ReleaseMsg(msg)
+	}
+}
@@ -0,0 +1,152 @@
+package gosepp
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSoak repeatedly starts and terminates calls and forces reconnects
+// while tracking goroutine and heap growth. It runs for SOAK_DURATION,
+// e.g. SOAK_DURATION=10m go test -run TestSoak -timeout 0.
+func TestSoak(t *testing.T) {
+	duration, err := time.ParseDuration(os.Getenv("SOAK_DURATION"))
+	if err != nil {
+		t.Skip("Skip cause env SOAK_DURATION not set")
+		return
+	}
+
+	var kicker connKicker
+	srv := newFakeServer(t, func(c *fakeConn) {
+		defer kicker.add(c)()
+		acceptCalls(c)
+	})
+	defer srv.Close()
+
+	baseGoroutines := runtime.NumGoroutine()
+	var baseHeap uint64
+	deadline := time.Now().Add(duration)
+	for cycle := 1; time.Now().Before(deadline); cycle++ {
+		soakCycle(t, srv.URL(), &kicker)
+		if t.Failed() {
+			return
+		}
+		// let the first cycles warm up caches and pools.
+		if cycle == 10 {
+			baseHeap = heapInUse()
+		}
+		if cycle%1000 == 0 {
+			t.Logf("cycle %d: %d goroutines, %d bytes heap", cycle,
+				runtime.NumGoroutine(), heapInUse())
+		}
+	}
+
+	// all clients are closed, so only the server may be left.
+	if !waitGoroutines(baseGoroutines, 5*time.Second) {
+		var b strings.Builder
+		pprof.Lookup("goroutine").WriteTo(&b, 1)
+		t.Fatalf("goroutines leaked: %d, expected %d\n%s",
+			runtime.NumGoroutine(), baseGoroutines, b.String())
+	}
+	if heap := heapInUse(); baseHeap > 0 && heap > 2*baseHeap+4<<20 {
+		t.Fatalf("heap grew from %d to %d bytes", baseHeap, heap)
+	}
+}
+
+// soakCycle runs a call with two sessions and a reconnect in between.
+func soakCycle(t *testing.T, endpoint string, kicker *connKicker) {
+	call, err := NewCall(&CallInfo{SigEndpoint: endpoint, ClientID: "client",
+		ConfID: "conf"}, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	connected := make(chan struct{}, 2)
+	call.sepp.SetConnStateHandler(func(state ConnState) {
+		if state == ConnConnected {
+			connected <- struct{}{}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		session, err := call.StartSession(ctx, Sdp{SdpType: "offer", Sdp: "offer-sdp"}, "bot")
+		if err != nil {
+			t.Errorf("start failed: %s", err)
+			return
+		}
+		if err := session.Terminate(ctx); err != nil {
+			t.Errorf("terminate failed: %s", err)
+			return
+		}
+		if i > 0 {
+			break
+		}
+		// drop the state of the initial connect.
+		select {
+		case <-connected:
+		default:
+		}
+		kicker.kick()
+		select {
+		case <-connected:
+		case <-ctx.Done():
+			t.Errorf("timeout waiting for reconnect")
+			return
+		}
+	}
+}
+
+// connKicker closes the server side of all connections to force
+// reconnects.
+type connKicker struct {
+	mu    sync.Mutex
+	conns map[*fakeConn]bool
+}
+
+func (k *connKicker) add(c *fakeConn) (remove func()) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.conns == nil {
+		k.conns = make(map[*fakeConn]bool)
+	}
+	k.conns[c] = true
+	return func() {
+		k.mu.Lock()
+		delete(k.conns, c)
+		k.mu.Unlock()
+	}
+}
+
+func (k *connKicker) kick() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for c := range k.conns {
+		c.Close()
+	}
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// waitGoroutines waits until at most n goroutines are running.
+func waitGoroutines(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package gosepp
+
+// SourceMove describes a source which changed its podium position.
+type SourceMove struct {
+	Source string
+	From   int
+	To     int
+}
+
+// SourceUpdateDiff is the difference between two source updates.
+type SourceUpdateDiff struct {
+	Added   []string
+	Removed []string
+	Moved   []SourceMove
+
+	LayoutChanged bool
+	Layout        int
+
+	PresenterChanged bool
+	// PresenterSrc is the new presenter source, nil if there is none.
+	PresenterSrc *int
+}
+
+// Empty reports whether nothing changed.
+func (d SourceUpdateDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0 &&
+		!d.LayoutChanged && !d.PresenterChanged
+}
+
+// DiffSourceUpdate computes the changes from prev to next. Sources are
+// identified by their entries in Sources, their position is the index.
+func DiffSourceUpdate(prev, next MsgSourceUpdateData) SourceUpdateDiff {
+	var d SourceUpdateDiff
+	prevPos := make(map[string]int, len(prev.Sources))
+	for i, src := range prev.Sources {
+		prevPos[src] = i
+	}
+	nextPos := make(map[string]int, len(next.Sources))
+	for i, src := range next.Sources {
+		nextPos[src] = i
+		from, ok := prevPos[src]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, src)
+		case from != i:
+			d.Moved = append(d.Moved, SourceMove{Source: src, From: from, To: i})
+		}
+	}
+	for _, src := range prev.Sources {
+		if _, ok := nextPos[src]; !ok {
+			d.Removed = append(d.Removed, src)
+		}
+	}
+
+	if prev.Layout != next.Layout {
+		d.LayoutChanged = true
+		d.Layout = next.Layout
+	}
+	if !equalIntPtr(prev.PresenterSrc, next.PresenterSrc) {
+		d.PresenterChanged = true
+		if next.PresenterSrc != nil {
+			p := *next.PresenterSrc
+			d.PresenterSrc = &p
+		}
+	}
+	return d
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
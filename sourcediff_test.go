@@ -0,0 +1,26 @@
+package gosepp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSourceUpdate(t *testing.T) {
+	psrc := 0
+	prev := MsgSourceUpdateData{Sources: []string{"a", "b", "c"}, Layout: 1}
+	next := MsgSourceUpdateData{Sources: []string{"b", "a", "d"}, Layout: 1,
+		PresenterSrc: &psrc}
+
+	d := DiffSourceUpdate(prev, next)
+	if !reflect.DeepEqual(d.Added, []string{"d"}) ||
+		!reflect.DeepEqual(d.Removed, []string{"c"}) ||
+		!reflect.DeepEqual(d.Moved, []SourceMove{{"b", 1, 0}, {"a", 0, 1}}) {
+		t.Fatalf("unexpected diff %+v", d)
+	}
+	if d.LayoutChanged || !d.PresenterChanged || *d.PresenterSrc != 0 {
+		t.Fatalf("unexpected layout or presenter change %+v", d)
+	}
+	if !DiffSourceUpdate(next, next).Empty() {
+		t.Fatalf("expected empty diff")
+	}
+}
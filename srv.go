@@ -0,0 +1,74 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SRVDiscovery discovers signaling endpoints via DNS SRV records, e.g.
+// _sepp._tcp.example.com. Records are ordered by priority and, within
+// the same priority, randomized by weight (RFC 2782).
+type SRVDiscovery struct {
+	// Service and Proto default to "sepp" and "tcp".
+	Service string
+	Proto   string
+	Domain  string
+	// Scheme and Path of the endpoint urls default to "wss" and "/call".
+	Scheme string
+	Path   string
+	// Resolver defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+// WithSRVDiscovery resolves the endpoints from the SRV records
+// _sepp._tcp.<domain> before every connect attempt.
+func WithSRVDiscovery(domain string) GoSeppOption {
+	d := &SRVDiscovery{Domain: domain}
+	return WithEndpointResolver(d.Resolve)
+}
+
+// Resolve looks up the SRV records and returns the endpoint urls in
+// order of preference. It implements EndpointResolver.
+func (d *SRVDiscovery) Resolve(ctx context.Context) ([]string, error) {
+	service, proto := d.Service, d.Proto
+	if len(service) == 0 {
+		service = "sepp"
+	}
+	if len(proto) == 0 {
+		proto = "tcp"
+	}
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	// LookupSRV sorts by priority and randomizes by weight.
+	_, records, err := resolver.LookupSRV(ctx, service, proto, d.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return d.urls(records), nil
+}
+
+func (d *SRVDiscovery) urls(records []*net.SRV) []string {
+	scheme, path := d.Scheme, d.Path
+	if len(scheme) == 0 {
+		scheme = "wss"
+	}
+	if len(path) == 0 {
+		path = "/call"
+	}
+	urls := make([]string, 0, len(records))
+	for _, r := range records {
+		// a target of "." means the service is not available
+		target := strings.TrimSuffix(r.Target, ".")
+		if len(target) == 0 {
+			continue
+		}
+		urls = append(urls, fmt.Sprintf("%s://%s%s",
+			scheme, net.JoinHostPort(target, fmt.Sprint(r.Port)), path))
+	}
+	return urls
+}
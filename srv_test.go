@@ -0,0 +1,23 @@
+package gosepp
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSRVDiscoveryURLs(t *testing.T) {
+	d := &SRVDiscovery{Domain: "example.com"}
+	urls := d.urls([]*net.SRV{
+		{Target: "sig1.example.com.", Port: 443, Priority: 10},
+		{Target: ".", Port: 0},
+		{Target: "sig2.example.com.", Port: 8443, Priority: 20},
+	})
+	expected := []string{
+		"wss://sig1.example.com:443/call",
+		"wss://sig2.example.com:8443/call",
+	}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("got %v, want %v", urls, expected)
+	}
+}
@@ -0,0 +1,54 @@
+package gosepp
+
+import (
+	"reflect"
+)
+
+// WithMsgStamping fills in the type, from, to and msg_id headers of
+// messages sent with Call.SendMsg if they are left empty. The type is
+// looked up from the message struct, from and to are the client-id and
+// conf-id of the call. The msg_id is created by the IDGenerator of the
+// GoSepp, or random if none is configured.
+func WithMsgStamping() CallOption {
+	return func(c *Call) {
+		c.stamping = true
+	}
+}
+
+// SendMsg sends a message over the signaling connection of this call.
+func (c *Call) SendMsg(msg interface{}) error {
+	if c.stamping {
+		msg = c.stamp(msg)
+	}
+	sepp, _ := c.conn()
+	return sepp.SendMsg(msg)
+}
+
+// stamp returns a copy of msg with empty headers filled in.
+func (c *Call) stamp(msg interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(msg))
+	if v.Kind() != reflect.Struct || !v.FieldByName("MsgBase").IsValid() {
+		return msg
+	}
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	base := cp.Elem().FieldByName("MsgBase").Addr().Interface().(*MsgBase)
+
+	if len(base.Type) == 0 {
+		if names := msgTypesOf(v.Type()); len(names) == 1 {
+			base.Type = names[0]
+		}
+	}
+	if len(base.From) == 0 {
+		base.From = string(c.clientID)
+	}
+	if len(base.To) == 0 {
+		base.To = string(c.confID)
+	}
+	if sepp, _ := c.conn(); len(base.MsgID) == 0 && sepp.idGenerator == nil {
+		if id, err := newCallID(); err == nil {
+			base.MsgID = string(id)
+		}
+	}
+	return cp.Interface()
+}
@@ -0,0 +1,111 @@
+package gosepp
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// binaryFrameType is the key of binary frames in Stats.
+const binaryFrameType = "binary"
+
+// Stats is a snapshot of the connection counters of a GoSepp.
+type Stats struct {
+	BytesSent     uint64
+	BytesReceived uint64
+	// MsgsSent and MsgsReceived count messages by type. Binary frames
+	// are counted as "binary".
+	MsgsSent     map[string]uint64
+	MsgsReceived map[string]uint64
+	// Reconnects counts the connections established after the first.
+	Reconnects  uint64
+	LastConnect time.Time
+	Connected   bool
+	State       ConnState
+	QoS         QoSReport
+}
+
+// stats collects the counters reported by Stats.
+type stats struct {
+	mu       sync.Mutex
+	s        Stats
+	connects uint64
+}
+
+func (st *stats) sent(n int) {
+	st.mu.Lock()
+	st.s.BytesSent += uint64(n)
+	st.mu.Unlock()
+}
+
+func (st *stats) received(n int) {
+	st.mu.Lock()
+	st.s.BytesReceived += uint64(n)
+	st.mu.Unlock()
+}
+
+func (st *stats) msg(dir Direction, msgType string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	counts := &st.s.MsgsReceived
+	if dir == DirectionOut {
+		counts = &st.s.MsgsSent
+	}
+	if *counts == nil {
+		*counts = make(map[string]uint64)
+	}
+	(*counts)[msgType]++
+}
+
+func (st *stats) connected() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.connects > 0 {
+		st.s.Reconnects++
+	}
+	st.connects++
+	st.s.LastConnect = time.Now()
+}
+
+func (st *stats) snapshot() Stats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s := st.s
+	s.MsgsSent = copyCounts(st.s.MsgsSent)
+	s.MsgsReceived = copyCounts(st.s.MsgsReceived)
+	return s
+}
+
+func copyCounts(counts map[string]uint64) map[string]uint64 {
+	c := make(map[string]uint64, len(counts))
+	for k, v := range counts {
+		c[k] = v
+	}
+	return c
+}
+
+// Stats returns a snapshot of the connection counters.
+func (rtm *GoSepp) Stats() Stats {
+	s := rtm.stats.snapshot()
+	s.Connected = rtm.isConnected()
+	s.State = rtm.State()
+	s.QoS = rtm.QoS()
+	return s
+}
+
+// PublishStats publishes Stats with expvar under name. Like
+// expvar.Publish, it panics if name is already in use.
+func (rtm *GoSepp) PublishStats(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return rtm.Stats()
+	}))
+}
+
+// countMsg counts a message of data in dir.
+func (rtm *GoSepp) countMsg(dir Direction, data []byte) {
+	msgType, err := sniffType(data)
+	if err != nil {
+		msgType = "invalid"
+	}
+	rtm.stats.msg(dir, msgType)
+}
@@ -0,0 +1,39 @@
+package gosepp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.read()
+		c.write(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+			Data: MsgChatData{Content: "hi"}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+	if err := sepp.SendMsg(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+		Data: MsgChatData{Content: "hello"}}); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case <-sepp.RcvCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	stats := sepp.Stats()
+	if !stats.Connected || stats.MsgsSent[MsgTypeChat] != 1 ||
+		stats.MsgsReceived[MsgTypeChat] != 1 || stats.BytesSent == 0 ||
+		stats.BytesReceived == 0 || stats.LastConnect.IsZero() || stats.Reconnects != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,57 @@
+package gosepp
+
+import (
+	"crypto/tls"
+)
+
+// strictCipherSuites are the FIPS 140 approved TLS 1.2 cipher suites.
+var strictCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// strictCurves are the FIPS 140 approved key exchange curves.
+var strictCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+
+// WithStrictTLS enforces a strict TLS profile on the websocket
+// connections: TLS 1.2 or newer, AES-GCM cipher suites with ECDHE on
+// the NIST curves P-256 and P-384 only. NewGoSepp fails with
+// ErrInsecureTLS if the TLS config skips certificate verification,
+// which rules out WithAIAFetching. The TLS 1.3 suites are chosen by the
+// Go runtime; build with a FIPS validated toolchain to restrict them.
+func WithStrictTLS() GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.strictTLS = true
+	}
+}
+
+// TLSStrict applies the profile of WithStrictTLS to a config built
+// by NewTLSConfig.
+func TLSStrict() TLSOption {
+	return func(b *tlsBuilder) {
+		b.strict = true
+	}
+}
+
+// strictTLSConfig returns a copy of config restricted to the strict
+// profile.
+func strictTLSConfig(config *tls.Config) (*tls.Config, error) {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.InsecureSkipVerify {
+		return nil, ErrInsecureTLS
+	}
+	c := config.Clone()
+	if c.MinVersion < tls.VersionTLS12 {
+		c.MinVersion = tls.VersionTLS12
+	}
+	if c.MaxVersion != 0 && c.MaxVersion < tls.VersionTLS12 {
+		return nil, ErrInsecureTLS
+	}
+	c.CipherSuites = strictCipherSuites
+	c.CurvePreferences = strictCurves
+	return c, nil
+}
@@ -0,0 +1,60 @@
+package gosepp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubprotocolJSON is the websocket subprotocol of sepp messages encoded
+// as JSON, the only wire format implemented.
+const SubprotocolJSON = "sepp.v1.json"
+
+// subprotocolCodecs are the subprotocols with an implemented wire
+// format.
+var subprotocolCodecs = map[string]bool{
+	SubprotocolJSON: true,
+}
+
+// WithSubprotocols offers protocols in order of preference with the
+// Sec-WebSocket-Protocol header on dial. The selection of the server is
+// returned by Subprotocol. Connecting fails if the server selects a
+// protocol which wasn't offered, or one without codec, as messages are
+// only encoded as JSON.
+func WithSubprotocols(protocols ...string) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.subprotocols = protocols
+	}
+}
+
+// Subprotocol returns the subprotocol selected by the server for the
+// current connection, or an empty string if none was selected.
+func (rtm *GoSepp) Subprotocol() string {
+	rtm.mu.Lock()
+	defer rtm.mu.Unlock()
+	return rtm.subprotocol
+}
+
+// negotiatedSubprotocol returns the protocol selected on c, verifying it
+// was offered and has a codec. Transports which don't negotiate select
+// none.
+func (rtm *GoSepp) negotiatedSubprotocol(c Conn) (string, error) {
+	sc, ok := c.(interface{ Subprotocol() string })
+	if !ok || len(sc.Subprotocol()) == 0 {
+		return "", nil
+	}
+	selected := sc.Subprotocol()
+	if !subprotocolCodecs[selected] {
+		return "", fmt.Errorf("server selected subprotocol %q without codec", selected)
+	}
+	for _, protocol := range rtm.subprotocols {
+		if protocol == selected {
+			return selected, nil
+		}
+	}
+	return "", fmt.Errorf("server selected unsupported subprotocol %q", selected)
+}
+
+// subprotocolHeader returns the Sec-WebSocket-Protocol header value.
+func (rtm *GoSepp) subprotocolHeader() string {
+	return strings.Join(rtm.subprotocols, ", ")
+}
@@ -0,0 +1,60 @@
+package gosepp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSubprotocol(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"sepp.v1.msgpack", SubprotocolJSON}}
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+			c.ReadMessage()
+		}))
+	defer srv.Close()
+
+	sepp, err := NewGoSepp("ws"+strings.TrimPrefix(srv.URL, "http"), "", nil, nil,
+		WithSubprotocols(SubprotocolJSON, "sepp.v2.json"))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+	if got := sepp.Subprotocol(); got != SubprotocolJSON {
+		t.Fatalf("expected %q, got %q", SubprotocolJSON, got)
+	}
+}
+
+func TestSubprotocolWithoutCodec(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"sepp.v1.msgpack", SubprotocolJSON}}
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+			c.ReadMessage()
+		}))
+	defer srv.Close()
+
+	// offered, but messages can't be encoded as msgpack.
+	sepp, err := NewGoSepp("ws"+strings.TrimPrefix(srv.URL, "http"), "", nil, nil,
+		WithSubprotocols("sepp.v1.msgpack", SubprotocolJSON))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	if connected := <-sepp.ConnectStatusCh(); connected {
+		t.Fatalf("connected with subprotocol %q", sepp.Subprotocol())
+	}
+}
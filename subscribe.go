@@ -0,0 +1,155 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MsgFilter selects messages of a subscription.
+type MsgFilter func(msg MsgInterface) bool
+
+// subscriptionBufferSize is the capacity of a subscription channel.
+const subscriptionBufferSize = 16
+
+type subscription struct {
+	filter MsgFilter
+	ch     chan MsgInterface
+	once   sync.Once
+}
+
+// Subscribe returns a channel receiving a copy of every received message
+// matching filter. A nil filter matches all messages. Subscriptions don't
+// drain the global channel: all messages are still delivered to RcvCh,
+// which must be consumed as before.
+//
+// Messages are delivered while reading from the connection, so a
+// subscriber never delays it: if the channel holds 16 unconsumed
+// messages, further matching messages are dropped with a warning. The
+// channel is closed by calling cancel, or when the GoSepp is stopped.
+func (rtm *GoSepp) Subscribe(filter MsgFilter) (<-chan MsgInterface, func()) {
+	sub := &subscription{
+		filter: filter,
+		ch:     make(chan MsgInterface, subscriptionBufferSize),
+	}
+
+	rtm.subsMu.Lock()
+	defer rtm.subsMu.Unlock()
+	if rtm.subsClosed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if rtm.subs == nil {
+		rtm.subs = make(map[*subscription]struct{})
+	}
+	rtm.subs[sub] = struct{}{}
+
+	cancel := func() {
+		sub.once.Do(func() {
+			rtm.subsMu.Lock()
+			defer rtm.subsMu.Unlock()
+			if _, ok := rtm.subs[sub]; ok {
+				delete(rtm.subs, sub)
+				close(sub.ch)
+			}
+		})
+	}
+	return sub.ch, cancel
+}
+
+// publish delivers msg to all matching subscriptions without blocking.
+func (rtm *GoSepp) publish(msg MsgInterface) {
+	rtm.subsMu.Lock()
+	defer rtm.subsMu.Unlock()
+	for sub := range rtm.subs {
+		if sub.filter != nil && !sub.filter(msg) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			rtm.logger.Warn("Dropping %s message, subscription is full.",
+				msg.GetType())
+		}
+	}
+}
+
+// closeSubscriptions closes all subscription channels. Called when the
+// receiver stops.
+func (rtm *GoSepp) closeSubscriptions() {
+	rtm.subsMu.Lock()
+	defer rtm.subsMu.Unlock()
+	for sub := range rtm.subs {
+		delete(rtm.subs, sub)
+		close(sub.ch)
+	}
+	rtm.subsClosed = true
+}
+
+// FilterCallID matches messages of the call callID.
+func FilterCallID(callID CallID) MsgFilter {
+	return func(msg MsgInterface) bool {
+		return CallIDOf(msg) == callID
+	}
+}
+
+// FilterTypes matches messages of the given types.
+func FilterTypes(types ...string) MsgFilter {
+	return func(msg MsgInterface) bool {
+		for _, t := range types {
+			if msg.GetType() == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// filterErrorRef matches error messages referencing msgID.
+func filterErrorRef(msgID string) MsgFilter {
+	return func(msg MsgInterface) bool {
+		m, ok := msg.(*MsgError)
+		return ok && len(msgID) > 0 && m.Data.RefMsgID == msgID
+	}
+}
+
+// SendAndWait sends msg and blocks until the first received message of
+// one of the expectedTypes arrives, which is returned. The reply is
+// delivered to RcvCh and other subscriptions as well.
+// If msg carries a msg_id and the server answers with an error message
+// referencing it, a *ServerError is returned.
+func (rtm *GoSepp) SendAndWait(ctx context.Context, msg interface{},
+	expectedTypes ...string) (MsgInterface, error) {
+	return rtm.sendAndWaitFor(ctx, msg, FilterTypes(expectedTypes...))
+}
+
+// sendAndWaitFor sends msg and returns the first received message
+// matching isExpected.
+func (rtm *GoSepp) sendAndWaitFor(ctx context.Context, msg interface{},
+	isExpected MsgFilter) (MsgInterface, error) {
+	// assign the msg_id upfront, so errors referencing it are matched.
+	msg = rtm.assignMsgID(msg)
+	isError := filterErrorRef(msgIDOf(msg))
+	// subscribe before sending, so the reply can't be missed.
+	ch, cancel := rtm.Subscribe(func(m MsgInterface) bool {
+		return isExpected(m) || isError(m)
+	})
+	defer cancel()
+
+	if err := rtm.SendMsg(msg); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	select {
+	case reply, ok := <-ch:
+		if !ok {
+			return nil, ErrConnectionClosed
+		}
+		if m, ok := reply.(*MsgError); ok && !isExpected(reply) {
+			return nil, serverError(m)
+		}
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctxError(ctx, "wait for reply")
+	}
+}
@@ -0,0 +1,156 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSubscribeFilteredCopies(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		c.write(MsgRecording{MsgBase: MsgBase{Type: MsgTypeRecording},
+			Data: MsgRecordingData{CallID: "a"}})
+		c.write(MsgMemberlist{MsgBase: MsgBase{Type: MsgTypeMemberlist},
+			Data: MsgMemberlistData{CallID: "b"}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	ch, cancel := sepp.Subscribe(FilterCallID("b"))
+	defer cancel()
+
+	for _, typ := range []string{MsgTypeRecording, MsgTypeMemberlist} {
+		select {
+		case msg := <-sepp.RcvCh():
+			if msg.GetType() != typ {
+				t.Fatalf("expected %s on RcvCh, got %s", typ, msg.GetType())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", typ)
+		}
+	}
+	select {
+	case msg := <-ch:
+		if msg.GetType() != MsgTypeMemberlist {
+			t.Fatalf("unexpected %s on subscription", msg.GetType())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for subscription")
+	}
+
+	sepp.Stop()
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected closed subscription")
+	}
+}
+
+func TestSubscribeStalledSubscriber(t *testing.T) {
+	const count = 2 * subscriptionBufferSize
+	srv := newFakeServer(t, func(c *fakeConn) {
+		for i := 0; i < count; i++ {
+			c.write(NewChatMsg("conf", fmt.Sprintf("chat-%d", i)))
+		}
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	stalled, cancelStalled := sepp.Subscribe(nil)
+	defer cancelStalled()
+	last, cancelLast := sepp.Subscribe(func(msg MsgInterface) bool {
+		chat, ok := msg.(*MsgChat)
+		return ok && chat.Data.Content == fmt.Sprintf("chat-%d", count-1)
+	})
+	defer cancelLast()
+
+	// neither RcvCh nor other subscriptions wait for the stalled one.
+	for i := 0; i < count; i++ {
+		select {
+		case <-sepp.RcvCh():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for message %d", i)
+		}
+	}
+	select {
+	case <-last:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for subscription")
+	}
+	if n := len(stalled); n != subscriptionBufferSize {
+		t.Fatalf("expected %d buffered messages, got %d", subscriptionBufferSize, n)
+	}
+}
+
+func TestSendAndWait(t *testing.T) {
+	srv := newFakeServer(t, acceptCalls)
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		// keep the global channel drained
+		for range sepp.RcvCh() {
+		}
+	}()
+	reply, err := sepp.SendAndWait(ctx, MsgCallStart{
+		MsgBase: MsgBase{Type: MsgTypeCallStart, From: "client", To: "conf"},
+	}, MsgTypeCallAccepted, MsgTypeCallRejected)
+	if err != nil {
+		t.Fatalf("send and wait failed: %s", err)
+	}
+	if reply.GetType() != MsgTypeCallAccepted {
+		t.Fatalf("unexpected reply %s", reply.GetType())
+	}
+}
+
+func TestSendAndWaitServerError(t *testing.T) {
+	srv := newFakeServer(t, func(c *fakeConn) {
+		m := c.read()
+		if m == nil {
+			return
+		}
+		c.write(MsgError{MsgBase: MsgBase{Type: MsgTypeError},
+			Data: MsgErrorData{Code: 404, Reason: "unknown conf",
+				RefMsgID: m.GetMsgID()}})
+		c.read()
+	})
+	defer srv.Close()
+
+	sepp, err := NewGoSepp(srv.URL(), "", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+	<-sepp.ConnectStatusCh()
+	go func() {
+		for range sepp.RcvCh() {
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = sepp.SendAndWait(ctx, MsgCallStart{
+		MsgBase: MsgBase{Type: MsgTypeCallStart, MsgID: "m1"},
+	}, MsgTypeCallAccepted)
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.Code != 404 {
+		t.Fatalf("expected server error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package gosepp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timestampLayouts are the formats accepted for string timestamps.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Timestamp is a time sent by the server. It accepts RFC 3339 strings,
+// with or without zone, and unix timestamps in seconds or milliseconds,
+// either as number or string. It is encoded as RFC 3339 string, or as
+// empty string if zero.
+type Timestamp struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler.
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	if ts.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(ts.UTC().Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	t, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	ts.Time = t
+	return nil
+}
+
+// ParseTimestamp parses s in one of the formats accepted by Timestamp.
+// An empty string returns the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		// values beyond 1e11 can't be seconds (year 5138), so they
+		// are milliseconds.
+		if n > 1e11 {
+			n /= 1000
+		}
+		sec := int64(n)
+		return time.Unix(sec, int64((n-float64(sec))*1e9)).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
@@ -0,0 +1,36 @@
+package gosepp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampFormats(t *testing.T) {
+	want := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, ts := range []string{
+		`"2021-03-04T05:06:07Z"`,
+		`"2021-03-04T06:06:07+01:00"`,
+		`"2021-03-04 05:06:07"`,
+		`1614834367`,
+		`1614834367000`,
+		`"1614834367"`,
+	} {
+		var chat MsgChatData
+		if err := json.Unmarshal([]byte(`{"ts":`+ts+`}`), &chat); err != nil {
+			t.Fatalf("failed to parse %s: %s", ts, err)
+		}
+		if !chat.Timestamp.Equal(want) {
+			t.Fatalf("parsed %s as %s", ts, chat.Timestamp)
+		}
+	}
+
+	b, err := json.Marshal(MsgChatData{Timestamp: Timestamp{want}})
+	if err != nil {
+		t.Fatalf("marshal failed: %s", err)
+	}
+	var chat MsgChatData
+	if err := json.Unmarshal(b, &chat); err != nil || !chat.Timestamp.Equal(want) {
+		t.Fatalf("round trip failed: %s", b)
+	}
+}
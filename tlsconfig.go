@@ -0,0 +1,168 @@
+package gosepp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// TLSOption configures the tls.Config built by NewTLSConfig.
+type TLSOption func(*tlsBuilder)
+
+type tlsBuilder struct {
+	config      *tls.Config
+	systemRoots bool
+	rootPEMs    [][]byte
+	strict      bool
+	err         error
+}
+
+func (b *tlsBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// NewTLSConfig returns a client TLS configuration. TLS 1.2 is the
+// minimum version unless set with TLSMinVersion.
+func NewTLSConfig(options ...TLSOption) (*tls.Config, error) {
+	b := &tlsBuilder{config: &tls.Config{MinVersion: tls.VersionTLS12}}
+	for _, opt := range options {
+		opt(b)
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.systemRoots || len(b.rootPEMs) > 0 {
+		pool := x509.NewCertPool()
+		if b.systemRoots {
+			var err error
+			if pool, err = x509.SystemCertPool(); err != nil {
+				return nil, err
+			}
+		}
+		for _, rootPEM := range b.rootPEMs {
+			if !pool.AppendCertsFromPEM(rootPEM) {
+				return nil, ErrInvalidCACert
+			}
+		}
+		b.config.RootCAs = pool
+	}
+	if b.strict {
+		return strictTLSConfig(b.config)
+	}
+	return b.config, nil
+}
+
+// TLSMinVersion sets the minimum TLS version, e.g. tls.VersionTLS13.
+func TLSMinVersion(version uint16) TLSOption {
+	return func(b *tlsBuilder) {
+		b.config.MinVersion = version
+	}
+}
+
+// TLSCipherSuites restricts the TLS 1.2 cipher suites. TLS 1.3 suites
+// are not configurable.
+func TLSCipherSuites(suites ...uint16) TLSOption {
+	return func(b *tlsBuilder) {
+		b.config.CipherSuites = suites
+	}
+}
+
+// TLSALPN sets the protocols offered with ALPN.
+func TLSALPN(protocols ...string) TLSOption {
+	return func(b *tlsBuilder) {
+		b.config.NextProtos = protocols
+	}
+}
+
+// TLSClientCert loads the client certificate from PEM files.
+func TLSClientCert(certFile, keyFile string) TLSOption {
+	return func(b *tlsBuilder) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		b.config.Certificates = append(b.config.Certificates, cert)
+	}
+}
+
+// TLSClientCertPEM sets the client certificate from PEM encoded bytes.
+func TLSClientCertPEM(certPEM, keyPEM []byte) TLSOption {
+	return func(b *tlsBuilder) {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		b.config.Certificates = append(b.config.Certificates, cert)
+	}
+}
+
+// TLSClientCertFS loads the client certificate from PEM files in fsys,
+// e.g. an embed.FS.
+func TLSClientCertFS(fsys fs.FS, certFile, keyFile string) TLSOption {
+	return func(b *tlsBuilder) {
+		certPEM, err := fs.ReadFile(fsys, certFile)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		keyPEM, err := fs.ReadFile(fsys, keyFile)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		TLSClientCertPEM(certPEM, keyPEM)(b)
+	}
+}
+
+// TLSSystemRoots trusts the system roots, in addition to the roots
+// added with the TLSRootCA options.
+func TLSSystemRoots() TLSOption {
+	return func(b *tlsBuilder) {
+		b.systemRoots = true
+	}
+}
+
+// TLSRootCAFile trusts the PEM encoded CA certificates in caFile.
+func TLSRootCAFile(caFile string) TLSOption {
+	return func(b *tlsBuilder) {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		b.rootPEMs = append(b.rootPEMs, caPEM)
+	}
+}
+
+// TLSRootCAPEM trusts the PEM encoded CA certificates.
+func TLSRootCAPEM(caPEM []byte) TLSOption {
+	return func(b *tlsBuilder) {
+		b.rootPEMs = append(b.rootPEMs, caPEM)
+	}
+}
+
+// TLSRootCAFS trusts the PEM encoded CA certificates in caFile of fsys.
+func TLSRootCAFS(fsys fs.FS, caFile string) TLSOption {
+	return func(b *tlsBuilder) {
+		caPEM, err := fs.ReadFile(fsys, caFile)
+		if err != nil {
+			b.fail(fmt.Errorf("failed to read CA file: %w", err))
+			return
+		}
+		b.rootPEMs = append(b.rootPEMs, caPEM)
+	}
+}
+
+// TLSInsecureSkipVerify disables the verification of server
+// certificates. Only use it for testing.
+func TLSInsecureSkipVerify() TLSOption {
+	return func(b *tlsBuilder) {
+		b.config.InsecureSkipVerify = true
+	}
+}
@@ -0,0 +1,79 @@
+package gosepp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	cert, key := testCert(t, "client", nil, nil, true, "")
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	fsys := fstest.MapFS{
+		"cert.pem": {Data: certPEM},
+		"key.pem":  {Data: keyPEM},
+	}
+
+	config, err := NewTLSConfig(
+		TLSMinVersion(tls.VersionTLS13),
+		TLSALPN("http/1.1"),
+		TLSClientCertFS(fsys, "cert.pem", "key.pem"),
+		TLSRootCAPEM(certPEM))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	if config.MinVersion != tls.VersionTLS13 || len(config.NextProtos) != 1 ||
+		len(config.Certificates) != 1 || config.RootCAs == nil {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	if _, err := NewTLSConfig(TLSRootCAPEM([]byte("garbage"))); !errors.Is(err, ErrInvalidCACert) {
+		t.Fatalf("expected ErrInvalidCACert, got %v", err)
+	}
+	if _, err := NewTLSConfig(TLSClientCertFS(fsys, "missing.pem", "key.pem")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{"cert.pem": certPEM, "key.pem": keyPEM} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("failed: %s", err)
+		}
+	}
+	config, err = CreateTLSConfig(filepath.Join(dir, "cert.pem"),
+		filepath.Join(dir, "key.pem"), filepath.Join(dir, "cert.pem"), false, false)
+	if err != nil {
+		t.Fatalf("CreateTLSConfig failed: %s", err)
+	}
+	if config.RootCAs == nil || len(config.Certificates) != 1 {
+		t.Fatalf("unexpected config %+v", config)
+	}
+}
+
+func TestStrictTLS(t *testing.T) {
+	config, err := NewTLSConfig(TLSMinVersion(tls.VersionTLS10), TLSStrict())
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	if config.MinVersion != tls.VersionTLS12 || len(config.CipherSuites) != len(strictCipherSuites) {
+		t.Fatalf("unexpected config %+v", config)
+	}
+	if _, err := NewTLSConfig(TLSInsecureSkipVerify(), TLSStrict()); !errors.Is(err, ErrInsecureTLS) {
+		t.Fatalf("expected ErrInsecureTLS, got %v", err)
+	}
+	_, err = NewGoSepp("wss://127.0.0.1:1", "", &tls.Config{InsecureSkipVerify: true},
+		nil, WithStrictTLS())
+	if !errors.Is(err, ErrInsecureTLS) {
+		t.Fatalf("expected ErrInsecureTLS, got %v", err)
+	}
+}
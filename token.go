@@ -0,0 +1,118 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// tokenRetryInterval is the delay before retrying a failed refresh.
+const tokenRetryInterval = 5 * time.Second
+
+// TokenProvider returns a fresh auth token.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts a function to a TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithTokenProvider refreshes the auth token margin before the exp claim
+// of the current JWT, avoiding disconnects by the server mid-call. The
+// new token is applied with UpdateAuthToken. If the server doesn't
+// support re-authentication, the client reconnects with the new token.
+// Expired tokens are also refreshed before reconnecting, and when the
+// server sends auth_expired.
+func WithTokenProvider(provider TokenProvider, margin time.Duration) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.tokenProvider = provider
+		rtm.tokenMargin = margin
+	}
+}
+
+// tokenExpiry returns the exp claim of the JWT token. The signature is
+// not verified.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(exp*float64(time.Second))), true
+}
+
+// tokenExpiring reports whether the current token must be refreshed.
+func (rtm *GoSepp) tokenExpiring() bool {
+	exp, ok := tokenExpiry(rtm.token())
+	return ok && time.Until(exp) <= rtm.tokenMargin
+}
+
+// refreshToken fetches a new token from the provider.
+func (rtm *GoSepp) refreshToken(ctx context.Context) error {
+	token, err := rtm.tokenProvider.Token(ctx)
+	if err != nil {
+		return err
+	}
+	rtm.mu.Lock()
+	rtm.authToken = token
+	rtm.mu.Unlock()
+	return nil
+}
+
+// refreshTokens refreshes the token before it expires until ctx is
+// done.
+func (rtm *GoSepp) refreshTokens(ctx context.Context) {
+	for {
+		exp, ok := tokenExpiry(rtm.token())
+		if !ok {
+			rtm.logger.Warn("Auth token has no expiry. Not refreshing.")
+			return
+		}
+		rtm.sleep(ctx, time.Until(exp)-rtm.tokenMargin)
+		if ctx.Err() != nil {
+			return
+		}
+		token, err := rtm.tokenProvider.Token(ctx)
+		if err != nil {
+			rtm.logger.Warn("Failed to refresh auth token [%s].", err)
+			rtm.sleep(ctx, tokenRetryInterval)
+			continue
+		}
+		if newExp, ok := tokenExpiry(token); ok && !newExp.After(exp) {
+			rtm.logger.Warn("Refreshed auth token expires at %s. Retrying.", newExp)
+			rtm.sleep(ctx, tokenRetryInterval)
+			continue
+		}
+		reauth := rtm.Supports(MsgTypeAuth)
+		if err := rtm.UpdateAuthToken(token); err != nil {
+			rtm.logger.Warn("Failed to re-authenticate [%s].", err)
+			reauth = false
+		}
+		if !reauth {
+			// reconnect with the new token.
+			if wsClient := rtm.conn(); wsClient != nil {
+				wsClient.Close()
+			}
+		}
+	}
+}
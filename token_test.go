@@ -0,0 +1,57 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testToken returns an unsigned JWT expiring at exp.
+func testToken(exp time.Time) string {
+	payload := fmt.Sprintf(`{"exp":%d}`, exp.Unix())
+	return "e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	if got, ok := tokenExpiry(testToken(exp)); !ok || !got.Equal(exp) {
+		t.Fatalf("expected %s, got %s", exp, got)
+	}
+	if _, ok := tokenExpiry("opaque-token"); ok {
+		t.Fatalf("expected no expiry of opaque token")
+	}
+}
+
+func TestTokenProviderReconnect(t *testing.T) {
+	tokens := make(chan string, 4)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		tokens <- c.header.Get("Authorization")
+		c.read()
+	})
+	defer srv.Close()
+
+	initial := testToken(time.Now().Add(3 * time.Second))
+	refreshed := testToken(time.Now().Add(time.Hour))
+	provider := TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return refreshed, nil
+	})
+	sepp, err := NewGoSepp(srv.URL(), initial, nil, nil,
+		WithTokenProvider(provider, time.Second))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	for _, want := range []string{initial, refreshed} {
+		select {
+		case token := <-tokens:
+			if token != "Bearer "+want {
+				t.Fatalf("expected %q, got %q", want, token)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %q", want)
+		}
+	}
+}
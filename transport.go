@@ -0,0 +1,174 @@
+package gosepp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Message types of a Conn. They match the websocket frame types.
+const (
+	TextMessage   = websocket.TextMessage
+	BinaryMessage = websocket.BinaryMessage
+	PingMessage   = websocket.PingMessage
+	PongMessage   = websocket.PongMessage
+)
+
+// Conn is a message based connection established by a Transport.
+// *websocket.Conn implements it. Transports without keepalive frames
+// may ignore written PingMessages.
+type Conn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// Transport establishes connections to a signaling endpoint.
+type Transport interface {
+	Dial(ctx context.Context, u *url.URL, header http.Header) (Conn, error)
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[string]Transport{}
+)
+
+// RegisterTransport makes t available for endpoint urls with the given
+// scheme. The schemes ws and wss use websockets, unless overridden.
+func RegisterTransport(scheme string, t Transport) {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	transports[scheme] = t
+}
+
+func lookupTransport(scheme string) (Transport, bool) {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	t, ok := transports[scheme]
+	return t, ok
+}
+
+// WithTransport uses t for all endpoints, regardless of their scheme.
+func WithTransport(t Transport) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.transport = t
+	}
+}
+
+// transportFor returns the transport to dial u with.
+func (rtm *GoSepp) transportFor(u *url.URL) (Transport, error) {
+	if rtm.transport != nil {
+		return rtm.transport, nil
+	}
+	if t, ok := lookupTransport(u.Scheme); ok {
+		return t, nil
+	}
+	switch u.Scheme {
+	case "ws", "wss":
+		return &websocketTransport{dialer: rtm.wsDialer}, nil
+	}
+	return nil, fmt.Errorf("no transport for scheme %q", u.Scheme)
+}
+
+// websocketTransport is the default transport.
+type websocketTransport struct {
+	dialer *websocket.Dialer
+}
+
+func (t *websocketTransport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (Conn, error) {
+	c, _, err := t.dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// StreamDialer opens a reliable, ordered byte stream to an endpoint,
+// e.g. a TCP connection. The dialer is responsible for transmitting
+// header, e.g. the auth token. WebTransport (HTTP/3) is provided by the
+// separate module github.com/eyeson-team/gosepp/v3/webtransport, so
+// only its users depend on a QUIC implementation.
+type StreamDialer func(ctx context.Context, u *url.URL,
+	header http.Header) (io.ReadWriteCloser, error)
+
+// NewStreamTransport returns a Transport sending messages over byte
+// streams opened by dial. Messages are framed with a one byte message
+// type and a four byte big-endian length. This framing is specific to
+// gosepp, so the server has to accept the streams with NewStreamConn,
+// e.g. via Listener.ServeConn. Register it for the scheme of the stream
+// based endpoints, e.g.
+//
+//	gosepp.RegisterTransport("sepp+tcp", gosepp.NewStreamTransport(
+//		func(ctx context.Context, u *url.URL, _ http.Header) (io.ReadWriteCloser, error) {
+//			var d net.Dialer
+//			return d.DialContext(ctx, "tcp", u.Host)
+//		}))
+func NewStreamTransport(dial StreamDialer) Transport {
+	return &streamTransport{dial: dial}
+}
+
+type streamTransport struct {
+	dial StreamDialer
+}
+
+func (t *streamTransport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (Conn, error) {
+	rwc, err := t.dial(ctx, u, header)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamConn(rwc), nil
+}
+
+// maxStreamFrameSize limits the frames read from a stream.
+const maxStreamFrameSize = 16 << 20
+
+// NewStreamConn returns a Conn framing messages on rwc. Servers accepting
+// stream based connections pass it to Listener.ServeConn.
+func NewStreamConn(rwc io.ReadWriteCloser) Conn {
+	return &streamConn{rwc: rwc}
+}
+
+type streamConn struct {
+	rwc     io.ReadWriteCloser
+	writeMu sync.Mutex
+}
+
+func (c *streamConn) ReadMessage() (int, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(c.rwc, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxStreamFrameSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(c.rwc, data); err != nil {
+		return 0, nil, err
+	}
+	return int(header[0]), data, nil
+}
+
+func (c *streamConn) WriteMessage(messageType int, data []byte) error {
+	frame := make([]byte, 5+len(data))
+	frame[0] = byte(messageType)
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	copy(frame[5:], data)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.rwc.Write(frame)
+	return err
+}
+
+func (c *streamConn) Close() error {
+	return c.rwc.Close()
+}
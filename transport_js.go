@@ -0,0 +1,165 @@
+//go:build js && wasm
+// +build js,wasm
+
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall/js"
+)
+
+// In the browser, websockets are established with the WebSocket API, as
+// raw sockets are not available.
+func init() {
+	RegisterTransport("ws", &browserTransport{})
+	RegisterTransport("wss", &browserTransport{})
+}
+
+// browserTransport uses the WebSocket API of the javascript host.
+// Browsers don't allow to set request headers, so the auth token must be
+// passed in the endpoint url, see WithAuthQuery.
+type browserTransport struct{}
+
+// errBrowserAuth is returned by Dial if the auth token would be dropped.
+var errBrowserAuth = errors.New(
+	"browser websockets can't send auth headers, use WithAuthQuery")
+
+type browserMsg struct {
+	messageType int
+	data        []byte
+}
+
+type browserConn struct {
+	ws    js.Value
+	funcs []js.Func
+
+	mu     sync.Mutex
+	queue  []browserMsg
+	notify chan struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (t *browserTransport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (Conn, error) {
+	if header.Get("Authorization") != "" || header.Get("Cookie") != "" {
+		return nil, errBrowserAuth
+	}
+	ws := js.Global().Get("WebSocket").New(u.String())
+	ws.Set("binaryType", "arraybuffer")
+
+	c := &browserConn{
+		ws:     ws,
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+	opened := make(chan struct{})
+	failed := make(chan struct{})
+	var once sync.Once
+
+	// Callbacks run on the javascript event loop and must not block.
+	onOpen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		once.Do(func() { close(opened) })
+		return nil
+	})
+	onError := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		once.Do(func() { close(failed) })
+		return nil
+	})
+	onMessage := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		c.enqueue(args[0].Get("data"))
+		return nil
+	})
+	onClose := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		once.Do(func() { close(failed) })
+		c.closeOnce.Do(func() { close(c.closed) })
+		return nil
+	})
+	c.funcs = []js.Func{onOpen, onError, onMessage, onClose}
+	ws.Set("onopen", onOpen)
+	ws.Set("onerror", onError)
+	ws.Set("onmessage", onMessage)
+	ws.Set("onclose", onClose)
+
+	select {
+	case <-opened:
+		return c, nil
+	case <-failed:
+		c.Close()
+		return nil, errors.New("websocket connection failed")
+	case <-ctx.Done():
+		c.Close()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *browserConn) enqueue(data js.Value) {
+	var msg browserMsg
+	if data.Type() == js.TypeString {
+		msg = browserMsg{TextMessage, []byte(data.String())}
+	} else {
+		array := js.Global().Get("Uint8Array").New(data)
+		buf := make([]byte, array.Get("length").Int())
+		js.CopyBytesToGo(buf, array)
+		msg = browserMsg{BinaryMessage, buf}
+	}
+	c.mu.Lock()
+	c.queue = append(c.queue, msg)
+	c.mu.Unlock()
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *browserConn) ReadMessage() (int, []byte, error) {
+	for {
+		c.mu.Lock()
+		if len(c.queue) > 0 {
+			msg := c.queue[0]
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+			return msg.messageType, msg.data, nil
+		}
+		c.mu.Unlock()
+		select {
+		case <-c.notify:
+		case <-c.closed:
+			return 0, nil, ErrConnectionClosed
+		}
+	}
+}
+
+func (c *browserConn) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-c.closed:
+		return ErrConnectionClosed
+	default:
+	}
+	switch messageType {
+	case TextMessage:
+		c.ws.Call("send", string(data))
+	case BinaryMessage:
+		array := js.Global().Get("Uint8Array").New(len(data))
+		js.CopyBytesToJS(array, data)
+		c.ws.Call("send", array)
+	default:
+		// pings are handled by the browser
+	}
+	return nil
+}
+
+func (c *browserConn) Close() error {
+	c.ws.Call("close")
+	c.closeOnce.Do(func() { close(c.closed) })
+	for _, f := range c.funcs {
+		f.Release()
+	}
+	c.funcs = nil
+	return nil
+}
@@ -0,0 +1,52 @@
+package gosepp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestStreamTransport(t *testing.T) {
+	listener := NewListener(nil)
+	transport := NewStreamTransport(func(ctx context.Context, u *url.URL,
+		header http.Header) (io.ReadWriteCloser, error) {
+		client, server := net.Pipe()
+		go listener.ServeConn(ctx, NewStreamConn(server))
+		return client, nil
+	})
+
+	sepp, err := NewGoSepp("https://sepp.invalid/call", "", nil, nil,
+		WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	var accepted *GoSepp
+	select {
+	case accepted = <-listener.AcceptCh():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for connection")
+	}
+	defer accepted.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+
+	if err := sepp.SendMsg(MsgChat{MsgBase: MsgBase{Type: MsgTypeChat},
+		Data: MsgChatData{Content: "hello"}}); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case msg := <-accepted.RcvCh():
+		if chat, ok := msg.(*MsgChat); !ok || chat.Data.Content != "hello" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for message")
+	}
+}
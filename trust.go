@@ -0,0 +1,190 @@
+package gosepp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAIADepth limits the intermediates fetched for one chain.
+	maxAIADepth = 4
+	// maxAIACertSize limits the size of fetched certificates.
+	maxAIACertSize = 1 << 20
+)
+
+// LoadTrustStore returns the system trust store extended by the PEM
+// encoded certificates in caFiles. On Windows and macOS the pool is
+// verified by the platform, so roots installed by enterprise policy,
+// e.g. of TLS-intercepting proxies, are trusted as well. If the system
+// store is unavailable, only caFiles are trusted.
+func LoadTrustStore(caFiles ...string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, caFile := range caFiles {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCACert, caFile)
+		}
+	}
+	return pool, nil
+}
+
+// WithAIAFetching returns a copy of config which fetches intermediate
+// certificates missing from the chain presented by the server from the
+// issuer urls of the certificates (Authority Information Access), as
+// the Windows platform verifier does. Misconfigured corporate proxies
+// often only send their leaf certificate. client defaults to
+// http.DefaultClient.
+//
+// The chain is verified for config.ServerName, or else the server name
+// sent with the handshake. TLS doesn't send IP addresses as server name,
+// so set config.ServerName to the address for IP endpoints, otherwise
+// the handshake fails.
+func WithAIAFetching(config *tls.Config, client *http.Client) *tls.Config {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	f := &aiaFetcher{client: client, cache: make(map[string]*x509.Certificate)}
+	roots := config.RootCAs
+	verify := config.VerifyConnection
+	c := config.Clone()
+	// the chain is verified by VerifyConnection instead.
+	c.InsecureSkipVerify = true
+	c.VerifyConnection = func(cs tls.ConnectionState) error {
+		serverName := c.ServerName
+		if serverName == "" {
+			serverName = cs.ServerName
+		}
+		if serverName == "" {
+			return errAIAServerName
+		}
+		var now time.Time
+		if c.Time != nil {
+			now = c.Time()
+		}
+		if err := f.verify(cs.PeerCertificates, serverName, roots, now); err != nil {
+			return err
+		}
+		if verify != nil {
+			return verify(cs)
+		}
+		return nil
+	}
+	return c
+}
+
+// errAIAServerName is returned if there is no name to verify the chain
+// for.
+var errAIAServerName = errors.New("no server name to verify, set ServerName")
+
+// aiaFetcher verifies chains, completing them with fetched
+// intermediates.
+type aiaFetcher struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*x509.Certificate
+}
+
+// verify checks certs for serverName, a host name or IP address, at
+// now, the current time if zero.
+func (f *aiaFetcher) verify(certs []*x509.Certificate, serverName string,
+	roots *x509.CertPool, now time.Time) error {
+	if len(certs) == 0 {
+		return errors.New("no peer certificates")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		CurrentTime:   now,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	issuer := certs[len(certs)-1]
+	for depth := 0; depth < maxAIADepth; depth++ {
+		var unknown x509.UnknownAuthorityError
+		if err == nil || !errors.As(err, &unknown) {
+			return err
+		}
+		next, fetchErr := f.fetchIssuer(issuer)
+		if fetchErr != nil {
+			return fmt.Errorf("%w (fetching intermediate: %s)", err, fetchErr)
+		}
+		if next == nil {
+			return err
+		}
+		opts.Intermediates.AddCert(next)
+		issuer = next
+		_, err = certs[0].Verify(opts)
+	}
+	return err
+}
+
+// fetchIssuer returns the issuer of cert from its AIA urls, or nil if
+// it has none.
+func (f *aiaFetcher) fetchIssuer(cert *x509.Certificate) (*x509.Certificate, error) {
+	var lastErr error
+	for _, u := range cert.IssuingCertificateURL {
+		f.mu.Lock()
+		issuer, ok := f.cache[u]
+		f.mu.Unlock()
+		if ok {
+			return issuer, nil
+		}
+		issuer, err := f.fetch(u)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		f.mu.Lock()
+		f.cache[u] = issuer
+		f.mu.Unlock()
+		return issuer, nil
+	}
+	return nil, lastErr
+}
+
+func (f *aiaFetcher) fetch(u string) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAIACertSize))
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	return x509.ParseCertificate(data)
+}
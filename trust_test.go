@@ -0,0 +1,125 @@
+package gosepp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testCert(t *testing.T, cn string, parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey, ca bool, aia string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  ca,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(cn); ip != nil && !ca {
+		template.IPAddresses = []net.IP{ip}
+	} else if !ca {
+		template.DNSNames = []string{cn}
+	}
+	if len(aia) > 0 {
+		template.IssuingCertificateURL = []string{aia}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	return cert, key
+}
+
+func TestAIAFetching(t *testing.T) {
+	root, rootKey := testCert(t, "root", nil, nil, true, "")
+	intermediate, intermediateKey := testCert(t, "intermediate", root, rootKey, true, "")
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write(intermediate.Raw)
+		}))
+	defer srv.Close()
+	leaf, _ := testCert(t, "sepp.example.com", intermediate, intermediateKey,
+		false, srv.URL+"/intermediate.cer")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	f := &aiaFetcher{client: srv.Client(), cache: make(map[string]*x509.Certificate)}
+	if err := f.verify([]*x509.Certificate{leaf}, "sepp.example.com", roots,
+		time.Time{}); err != nil {
+		t.Fatalf("verify failed: %s", err)
+	}
+	if err := f.verify([]*x509.Certificate{leaf}, "other.example.com", roots,
+		time.Time{}); err == nil {
+		t.Fatal("expected hostname mismatch")
+	}
+	if err := f.verify([]*x509.Certificate{leaf}, "sepp.example.com",
+		x509.NewCertPool(), time.Time{}); err == nil {
+		t.Fatal("expected unknown authority")
+	}
+	if err := f.verify([]*x509.Certificate{leaf}, "sepp.example.com", roots,
+		time.Now().Add(24*time.Hour)); err == nil {
+		t.Fatal("expected expired certificate")
+	}
+}
+
+func TestAIAFetchingIPEndpoint(t *testing.T) {
+	root, rootKey := testCert(t, "root", nil, nil, true, "")
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	tests := []struct {
+		name       string
+		certFor    string
+		serverName string
+		valid      bool
+	}{
+		{"matching ip", "127.0.0.1", "127.0.0.1", true},
+		{"other ip", "10.9.9.9", "127.0.0.1", false},
+		// tls.Dial doesn't send the ip, so there is nothing to check.
+		{"no server name", "127.0.0.1", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf, key := testCert(t, tt.certFor, root, rootKey, false, "")
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {}))
+			srv.TLS = &tls.Config{Certificates: []tls.Certificate{
+				{Certificate: [][]byte{leaf.Raw}, PrivateKey: key}}}
+			srv.StartTLS()
+			defer srv.Close()
+
+			config := WithAIAFetching(&tls.Config{RootCAs: roots,
+				ServerName: tt.serverName}, srv.Client())
+			conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), config)
+			if err == nil {
+				conn.Close()
+			}
+			if (err == nil) != tt.valid {
+				t.Fatalf("unexpected handshake result %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package gosepp
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ValidationError describes why an outgoing message is invalid.
+type ValidationError struct {
+	MsgType string
+	Field   string
+	Reason  string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Field) == 0 {
+		return fmt.Sprintf("invalid %s message: %s", e.MsgType, e.Reason)
+	}
+	return fmt.Sprintf("invalid %s message: %s %s", e.MsgType, e.Field, e.Reason)
+}
+
+// callIDRequired lists the message types which must carry a call-id.
+var callIDRequired = map[string]bool{
+	MsgTypeCallAccepted:   true,
+	MsgTypeSdpUpdate:      true,
+	MsgTypeCallTerminate:  true,
+	MsgTypeCallTerminated: true,
+	MsgTypeCallResume:     true,
+	MsgTypeCallResumed:    true,
+	MsgTypeMuteVideo:      true,
+	MsgTypeCallHold:       true,
+	MsgTypeCallTransfer:   true,
+}
+
+var (
+	msgTypeNamesOnce sync.Once
+	msgTypeNames     map[reflect.Type][]string
+)
+
+// msgTypesOf returns the type strings registered for the struct type t.
+func msgTypesOf(t reflect.Type) []string {
+	msgTypeNamesOnce.Do(func() {
+		msgTypeNames = make(map[reflect.Type][]string)
+		add := func(name string, factory func() MsgInterface) {
+			st := reflect.TypeOf(factory()).Elem()
+			msgTypeNames[st] = append(msgTypeNames[st], name)
+		}
+		for name, factory := range SeppMsgTypes {
+			add(name, factory)
+		}
+		for _, types := range versionedMsgTypes {
+			for name, factory := range types {
+				add(name, factory)
+			}
+		}
+	})
+	return msgTypeNames[t]
+}
+
+// WithValidation checks every outgoing message with ValidateMsg. Invalid
+// messages are not sent and SendMsg returns a *ValidationError.
+func WithValidation() GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.validate = true
+	}
+}
+
+// ValidateMsg checks that the type string of msg matches its struct,
+// that call control messages carry a call-id, and that sdps are set.
+// Messages of types not registered are only checked for a type.
+func ValidateMsg(msg interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(msg))
+	if v.Kind() != reflect.Struct {
+		return &ValidationError{Reason: fmt.Sprintf("unsupported %T", msg)}
+	}
+	typeField := v.FieldByName("Type")
+	if !typeField.IsValid() || typeField.Kind() != reflect.String {
+		return &ValidationError{Reason: fmt.Sprintf("%T has no type", msg)}
+	}
+	msgType := typeField.String()
+	if len(msgType) == 0 {
+		return &ValidationError{Field: "type", Reason: "is empty"}
+	}
+	if names := msgTypesOf(v.Type()); len(names) > 0 && !contains(names, msgType) {
+		return &ValidationError{MsgType: msgType, Field: "type",
+			Reason: fmt.Sprintf("does not match %s", v.Type().Name())}
+	}
+
+	data := v.FieldByName("Data")
+	if !data.IsValid() || data.Kind() != reflect.Struct {
+		return nil
+	}
+	if callIDRequired[msgType] {
+		if callID := data.FieldByName("CallID"); callID.IsValid() && callID.Len() == 0 {
+			return &ValidationError{MsgType: msgType, Field: "call_id", Reason: "is empty"}
+		}
+	}
+	if sdp := data.FieldByName("Sdp"); sdp.IsValid() {
+		if sdp, ok := sdp.Interface().(Sdp); ok {
+			if err := validateSdp(msgType, sdp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateSdp(msgType string, sdp Sdp) error {
+	switch msgType {
+	case MsgTypeCallStart, MsgTypeCallAccepted, MsgTypeSdpUpdate:
+		if len(sdp.Sdp) == 0 {
+			return &ValidationError{MsgType: msgType, Field: "sdp", Reason: "is empty"}
+		}
+	}
+	if sdp.SdpType == "offer" && len(sdp.Sdp) == 0 {
+		return &ValidationError{MsgType: msgType, Field: "sdp", Reason: "of offer is empty"}
+	}
+	if len(sdp.SdpType) > 0 && sdp.SdpType != "offer" && sdp.SdpType != "answer" {
+		return &ValidationError{MsgType: msgType, Field: "sdp.type",
+			Reason: fmt.Sprintf("%q is neither offer nor answer", sdp.SdpType)}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
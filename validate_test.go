@@ -0,0 +1,34 @@
+package gosepp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMsg(t *testing.T) {
+	for _, tc := range []struct {
+		msg   interface{}
+		field string
+	}{
+		{MsgCallStart{MsgBase: MsgBase{Type: MsgTypeCallStart},
+			Data: MsgCallStartData{Sdp: Sdp{SdpType: "offer", Sdp: "v=0"}}}, ""},
+		{&MsgChat{MsgBase: MsgBase{Type: MsgTypeChat}}, ""},
+		{MsgChat{MsgBase: MsgBase{Type: MsgTypeSdpUpdate}}, "type"},
+		{MsgChat{}, "type"},
+		{MsgSdpUpdate{MsgBase: MsgBase{Type: MsgTypeSdpUpdate},
+			Data: MsgSdpUpdateData{Sdp: Sdp{SdpType: "offer", Sdp: "v=0"}}}, "call_id"},
+		{MsgCallStart{MsgBase: MsgBase{Type: MsgTypeCallStart},
+			Data: MsgCallStartData{Sdp: Sdp{SdpType: "offer"}}}, "sdp"},
+		{MsgCallStart{MsgBase: MsgBase{Type: MsgTypeCallStart},
+			Data: MsgCallStartData{Sdp: Sdp{SdpType: "pranswer", Sdp: "v=0"}}}, "sdp.type"},
+	} {
+		err := ValidateMsg(tc.msg)
+		var verr *ValidationError
+		switch {
+		case len(tc.field) == 0 && err != nil:
+			t.Fatalf("unexpected error for %+v: %s", tc.msg, err)
+		case len(tc.field) > 0 && (!errors.As(err, &verr) || verr.Field != tc.field):
+			t.Fatalf("expected invalid %s for %+v, got %v", tc.field, tc.msg, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gosepp
+
+import (
+	"fmt"
+)
+
+// versionedMsgTypes holds the factories of messages whose schema changed
+// in later protocol versions, by version and message type.
+var versionedMsgTypes = map[int]map[string]func() MsgInterface{}
+
+// RegisterMsgVersion registers the factory used to decode messages of
+// msgType from protocol version onwards. Messages without a versioned
+// factory are decoded using SeppMsgTypes. Must be called before any
+// GoSepp is created, e.g. in init.
+func RegisterMsgVersion(version int, msgType string, factory func() MsgInterface) {
+	types, ok := versionedMsgTypes[version]
+	if !ok {
+		types = make(map[string]func() MsgInterface)
+		versionedMsgTypes[version] = types
+	}
+	types[msgType] = factory
+}
+
+// msgFactory returns the factory for msgType in the given protocol
+// version, picking the latest schema not newer than version.
+func msgFactory(version int, msgType string) (func() MsgInterface, bool) {
+	best := 0
+	var factory func() MsgInterface
+	for v, types := range versionedMsgTypes {
+		if v > version || v <= best {
+			continue
+		}
+		if f, ok := types[msgType]; ok {
+			best, factory = v, f
+		}
+	}
+	if factory != nil {
+		return factory, true
+	}
+	factory, ok := SeppMsgTypes[msgType]
+	return factory, ok
+}
+
+// DecodeMsgVersion decodes a json encoded message using the schema of
+// the given protocol version.
+func DecodeMsgVersion(data []byte, version int) (MsgInterface, error) {
+	msgType, err := sniffType(data)
+	if err != nil {
+		return nil, err
+	}
+	msgInitFunc, ok := msgFactory(version, msgType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMsgType, msgType)
+	}
+	msg := msgInitFunc()
+	if err := unmarshalMsg(data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// WithProtocolVersion sets the protocol version advertised in the hello
+// message. Defaults to ProtocolVersion. Use it together with
+// RegisterMsgVersion to talk to servers of a newer protocol version.
+func WithProtocolVersion(version int) GoSeppOption {
+	return func(rtm *GoSepp) {
+		rtm.protocolVersion = version
+	}
+}
+
+// ProtocolVersion returns the protocol version active on the current
+// connection, which is the lower of the local and the remote version.
+// Without a hello from the remote end, version 1 is assumed.
+func (rtm *GoSepp) ProtocolVersion() int {
+	caps, ok := rtm.Capabilities()
+	if !ok || caps.ProtocolVersion < 1 {
+		return 1
+	}
+	if caps.ProtocolVersion < rtm.protocolVersion {
+		return caps.ProtocolVersion
+	}
+	return rtm.protocolVersion
+}
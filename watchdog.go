@@ -0,0 +1,114 @@
+package gosepp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// WithDispatchWatchdog reports a stuck dispatcher to the error handler
+// with ErrDispatcherStuck, so deadlocked bots fail loudly. The
+// dispatcher counts as stuck if a handler hasn't returned within
+// timeout, or if it stopped while the call is still active. With
+// teardown the call is terminated as well, with reason
+// TerminatedWatchdog.
+func WithDispatchWatchdog(timeout time.Duration, teardown bool) CallOption {
+	return func(c *Call) {
+		c.watchdogTimeout = timeout
+		c.watchdogTeardown = teardown
+	}
+}
+
+// watchdog tracks the heartbeat of a dispatcher.
+type watchdog struct {
+	timeout  time.Duration
+	teardown bool
+	// busySince is the unix nano time the running handler was called,
+	// zero while waiting for messages.
+	busySince int64
+	// stopped is closed once the dispatcher returned.
+	stopped chan struct{}
+}
+
+func newWatchdog(timeout time.Duration, teardown bool) *watchdog {
+	return &watchdog{
+		timeout:  timeout,
+		teardown: teardown,
+		stopped:  make(chan struct{}),
+	}
+}
+
+// busy marks the start of a handler call. A nil watchdog is a no-op.
+func (w *watchdog) busy() {
+	if w != nil {
+		atomic.StoreInt64(&w.busySince, time.Now().UnixNano())
+	}
+}
+
+// idle marks the end of a handler call.
+func (w *watchdog) idle() {
+	if w != nil {
+		atomic.StoreInt64(&w.busySince, 0)
+	}
+}
+
+// stop marks the dispatcher as returned.
+func (w *watchdog) stop() {
+	if w != nil {
+		close(w.stopped)
+	}
+}
+
+// busyFor returns how long the running handler takes, zero if none.
+func (w *watchdog) busyFor() time.Duration {
+	since := atomic.LoadInt64(&w.busySince)
+	if since == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, since))
+}
+
+// watch checks the heartbeat of the dispatcher until ctx is done.
+func (s *CallSession) watch(ctx context.Context, w *watchdog) {
+	ticker := time.NewTicker(w.timeout / 4)
+	defer ticker.Stop()
+	reported := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopped:
+			if ctx.Err() == nil && s.active() {
+				s.stuck(w, fmt.Errorf("%w: dispatcher stopped", ErrDispatcherStuck))
+			}
+			return
+		case <-ticker.C:
+			busy := w.busyFor()
+			if busy > w.timeout && !reported {
+				reported = true
+				s.stuck(w, fmt.Errorf("%w: handler busy for %s",
+					ErrDispatcherStuck, busy.Round(time.Millisecond)))
+			} else if busy == 0 {
+				reported = false
+			}
+		}
+	}
+}
+
+// stuck reports err and tears the call down, if enabled.
+func (s *CallSession) stuck(w *watchdog, err error) {
+	s.logger.Error("Dispatcher of call %s stuck: %s", s.callID, err)
+	if s.handlers.err != nil {
+		s.handlers.err(err)
+	}
+	if !w.teardown || !s.active() {
+		return
+	}
+	if err := s.sendTerminate(TerminatedWatchdog); err != nil {
+		s.logger.Warn("Failed to terminate call: %s", err)
+	}
+	// the dispatcher can't handle the reply, so end the session here.
+	s.cancel()
+	s.terminated()
+}
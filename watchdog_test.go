@@ -0,0 +1,71 @@
+package gosepp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDispatchWatchdog(t *testing.T) {
+	terminates := make(chan *MsgCallTerminate, 1)
+	srv := newFakeServer(t, func(c *fakeConn) {
+		start := c.read().(*MsgCallStart)
+		c.write(MsgCallAccepted{
+			MsgBase: MsgBase{Type: MsgTypeCallAccepted, From: start.To, To: start.From},
+			Data:    MsgCallAcceptedData{CallID: "call", Sdp: Sdp{SdpType: "answer"}},
+		})
+		c.write(MsgMemberlist{MsgBase: MsgBase{Type: MsgTypeMemberlist},
+			Data: MsgMemberlistData{Add: []Member{{ClientID: "other"}}}})
+		for {
+			switch m := c.read().(type) {
+			case nil:
+				return
+			case *MsgCallTerminate:
+				terminates <- m
+			}
+		}
+	})
+	defer srv.Close()
+
+	call, err := NewCall(&CallInfo{SigEndpoint: srv.URL(), ClientID: "client",
+		ConfID: "conf"}, nil, WithDispatchWatchdog(100*time.Millisecond, true))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer call.Close()
+	release := make(chan struct{})
+	defer close(release)
+	call.SetMemberlistHandler(func(MsgMemberlistData) { <-release })
+	errs := make(chan error, 1)
+	call.SetErrorHandler(func(err error) { errs <- err })
+	reasons := make(chan TerminationReason, 1)
+	call.SetTerminationReasonHandler(func(r TerminationReason) { reasons <- r })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := call.StartSession(ctx, Sdp{SdpType: "offer"}, "bot"); err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrDispatcherStuck) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for watchdog")
+	}
+	select {
+	case r := <-reasons:
+		if r != TerminatedWatchdog {
+			t.Fatalf("unexpected reason %s", r)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for teardown")
+	}
+	select {
+	case <-terminates:
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for call_terminate")
+	}
+}
@@ -0,0 +1,164 @@
+package gosepp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 of the
+// request body, prefixed with "sha256=".
+const WebhookSignatureHeader = "X-Sepp-Signature"
+
+// WebhookForwarder POSTs received messages as JSON to a http endpoint,
+// so server-side integrations can consume signaling events without
+// holding the websocket themselves.
+type WebhookForwarder struct {
+	url        string
+	secret     []byte
+	types      map[string]bool
+	retries    int
+	backoff    time.Duration
+	httpClient *http.Client
+	logger     Logger
+}
+
+// WebhookOption defines the options interface of the WebhookForwarder.
+type WebhookOption func(*WebhookForwarder)
+
+// WithWebhookTypes restricts forwarding to the given message types.
+// By default all messages are forwarded.
+func WithWebhookTypes(types ...string) WebhookOption {
+	return func(w *WebhookForwarder) {
+		w.types = make(map[string]bool)
+		for _, t := range types {
+			w.types[t] = true
+		}
+	}
+}
+
+// WithWebhookRetries configures how often a failed delivery is retried.
+// The delay starts at backoff and doubles with every attempt.
+func WithWebhookRetries(retries int, backoff time.Duration) WebhookOption {
+	return func(w *WebhookForwarder) {
+		w.retries = retries
+		w.backoff = backoff
+	}
+}
+
+// WithWebhookHTTPClient sets the http client used for delivery.
+func WithWebhookHTTPClient(client *http.Client) WebhookOption {
+	return func(w *WebhookForwarder) {
+		w.httpClient = client
+	}
+}
+
+// NewWebhookForwarder returns a forwarder posting to url. If secret is
+// not empty, every request is signed with it, see
+// WebhookSignatureHeader.
+func NewWebhookForwarder(url, secret string, logger Logger,
+	options ...WebhookOption) *WebhookForwarder {
+	if logger == nil {
+		logger = &silentLogger{}
+	}
+	w := &WebhookForwarder{
+		url:        url,
+		secret:     []byte(secret),
+		retries:    3,
+		backoff:    500 * time.Millisecond,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+	for _, opt := range options {
+		opt(w)
+	}
+	return w
+}
+
+// Run forwards all messages received on ch until ch is closed or ctx
+// is done. Delivery failures are logged.
+func (w *WebhookForwarder) Run(ctx context.Context, ch <-chan MsgInterface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := w.Forward(ctx, msg); err != nil {
+				w.logger.Warn("Failed to forward %s [%s].", msg.GetType(), err)
+			}
+		}
+	}
+}
+
+// Forward posts a single message, retrying on network errors and
+// server side failures. Messages not matching the type filter are
+// skipped.
+func (w *WebhookForwarder) Forward(ctx context.Context, msg MsgInterface) error {
+	if w.types != nil && !w.types[msg.GetType()] {
+		return nil
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	delay := w.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctxError(ctx, "webhook retry")
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post delivers body once. It reports whether a failure is worth
+// retrying.
+func (w *WebhookForwarder) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(w.secret, body))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with %s", resp.Status)
+}
+
+// SignWebhook returns the hex encoded HMAC-SHA256 of body, as sent in
+// the WebhookSignatureHeader. Receivers use it to verify requests.
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
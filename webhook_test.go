@@ -0,0 +1,40 @@
+package gosepp
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookForwarderRetriesAndSigns(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got := r.Header.Get(WebhookSignatureHeader); got != "sha256="+SignWebhook([]byte("secret"), body) {
+			t.Errorf("invalid signature %q", got)
+		}
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	fwd := NewWebhookForwarder(srv.URL, "secret", nil,
+		WithWebhookTypes(MsgTypeChat),
+		WithWebhookRetries(2, time.Millisecond))
+
+	ctx := context.Background()
+	if err := fwd.Forward(ctx, &MsgRecording{MsgBase: MsgBase{Type: MsgTypeRecording}}); err != nil {
+		t.Fatalf("forward failed: %s", err)
+	}
+	if err := fwd.Forward(ctx, &MsgChat{MsgBase: MsgBase{Type: MsgTypeChat}}); err != nil {
+		t.Fatalf("forward failed: %s", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/eyeson-team/gosepp/v3/webtransport
+
+go 1.26.0
+
+// gosepp is developed along with this module.
+replace github.com/eyeson-team/gosepp/v3 => ../
+
+require (
+	github.com/eyeson-team/gosepp/v3 v3.0.0-00010101000000-000000000000
+	github.com/quic-go/quic-go v0.62.0
+	github.com/quic-go/webtransport-go v0.13.0
+)
+
+require (
+	github.com/dunglas/httpsfv v1.1.1 // indirect
+	github.com/gorilla/websocket v1.5.0 // indirect
+	github.com/quic-go/qpack v0.6.0 // indirect
+	golang.org/x/crypto v0.54.0 // indirect
+	golang.org/x/net v0.56.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+)
@@ -0,0 +1,103 @@
+// Package webtransport carries sepp signaling over WebTransport (HTTP/3),
+// for lower latency on lossy mobile networks, where QUIC avoids the
+// head-of-line blocking of TCP.
+//
+// It's a separate module, so the QUIC implementation is only a
+// dependency of applications using it. Messages are sent on a single
+// bidirectional stream with the framing of gosepp.NewStreamConn.
+// Clients register the transport for the Scheme:
+//
+//	webtransport.Register(nil)
+//	sepp, err := gosepp.NewGoSepp("sepp+wt://sig.example.com/call", token,
+//		nil, logger)
+//
+// Servers upgrade the requests of their HTTP/3 server with Accept and
+// serve the returned connection with gosepp.Listener.ServeConn.
+package webtransport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/eyeson-team/gosepp/v3"
+	"github.com/quic-go/webtransport-go"
+)
+
+// Scheme is the url scheme of WebTransport endpoints. They're dialed as
+// https urls.
+const Scheme = "sepp+wt"
+
+// Register makes WebTransport available for endpoints with the Scheme,
+// see NewTransport.
+func Register(tlsConfig *tls.Config) {
+	gosepp.RegisterTransport(Scheme, NewTransport(tlsConfig))
+}
+
+// NewTransport returns a transport establishing a WebTransport session
+// per connection, on a new QUIC connection. tlsConfig may be nil to use
+// the system roots. Endpoint urls of any scheme are dialed as https.
+func NewTransport(tlsConfig *tls.Config) gosepp.Transport {
+	return &transport{dialer: &webtransport.Transport{TLSClientConfig: tlsConfig}}
+}
+
+type transport struct {
+	dialer *webtransport.Transport
+}
+
+func (t *transport) Dial(ctx context.Context, u *url.URL,
+	header http.Header) (gosepp.Conn, error) {
+	endpoint := *u
+	endpoint.Scheme = "https"
+	rsp, session, err := t.dialer.Dial(ctx, endpoint.String(), header)
+	if err != nil {
+		if rsp != nil {
+			return nil, fmt.Errorf("webtransport session rejected with %s: %w",
+				rsp.Status, err)
+		}
+		return nil, err
+	}
+	stream, err := session.OpenStreamSync(ctx)
+	if err == nil {
+		// the stream is announced with its first write.
+		_, err = stream.Write(nil)
+	}
+	if err != nil {
+		session.CloseWithError(0, "")
+		return nil, err
+	}
+	return gosepp.NewStreamConn(&sessionStream{Stream: stream, session: session}), nil
+}
+
+// Accept upgrades the WebTransport request r with s and waits for the
+// signaling stream of the client, until ctx is done. The returned
+// connection is served with gosepp.Listener.ServeConn. The response
+// status is written on failure.
+func Accept(ctx context.Context, s *webtransport.Server, w http.ResponseWriter,
+	r *http.Request) (gosepp.Conn, error) {
+	session, err := s.Upgrade(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, err
+	}
+	stream, err := session.AcceptStream(ctx)
+	if err != nil {
+		session.CloseWithError(0, "")
+		return nil, err
+	}
+	return gosepp.NewStreamConn(&sessionStream{Stream: stream, session: session}), nil
+}
+
+// sessionStream is the signaling stream of a session. Closing it closes
+// the session, as it isn't used otherwise.
+type sessionStream struct {
+	*webtransport.Stream
+	session *webtransport.Session
+}
+
+func (s *sessionStream) Close() error {
+	s.Stream.Close()
+	return s.session.CloseWithError(0, "")
+}
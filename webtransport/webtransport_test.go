@@ -0,0 +1,158 @@
+package webtransport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eyeson-team/gosepp/v3"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/webtransport-go"
+)
+
+// testCert returns a self-signed certificate for 127.0.0.1 and a pool
+// trusting it.
+func testCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// serve starts a WebTransport server passing the connections of /call
+// to listener and returns its address.
+func serve(t *testing.T, listener *gosepp.Listener, cert tls.Certificate,
+	headers chan<- http.Header) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	mux := http.NewServeMux()
+	h3 := &http3.Server{
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{
+			Certificates: []tls.Certificate{cert}}),
+		Handler: mux,
+	}
+	webtransport.ConfigureHTTP3Server(h3)
+	s := &webtransport.Server{H3: h3}
+	mux.HandleFunc("/call", func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		c, err := Accept(ctx, s, w, r)
+		if err != nil {
+			t.Errorf("accept failed: %s", err)
+			return
+		}
+		listener.ServeConn(context.Background(), c)
+	})
+	go s.Serve(conn)
+	t.Cleanup(func() {
+		s.Close()
+		conn.Close()
+	})
+	return conn.LocalAddr().String()
+}
+
+func TestWebTransport(t *testing.T) {
+	cert, roots := testCert(t)
+	listener := gosepp.NewListener(nil)
+	defer listener.Close()
+	headers := make(chan http.Header, 1)
+	addr := serve(t, listener, cert, headers)
+
+	Register(&tls.Config{RootCAs: roots})
+	sepp, err := gosepp.NewGoSepp(fmt.Sprintf("%s://%s/call", Scheme, addr),
+		"token", nil, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	defer sepp.Stop()
+
+	var accepted *gosepp.GoSepp
+	select {
+	case accepted = <-listener.AcceptCh():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for connection")
+	}
+	defer accepted.Stop()
+	if connected := <-sepp.ConnectStatusCh(); !connected {
+		t.Fatalf("failed to connect")
+	}
+	if auth := (<-headers).Get("Authorization"); auth != "Bearer token" {
+		t.Fatalf("unexpected authorization %q", auth)
+	}
+
+	// messages pass both ways.
+	if err := sepp.SendMsg(gosepp.NewChatMsg("conf", "hello")); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case msg := <-accepted.RcvCh():
+		if chat, ok := msg.(*gosepp.MsgChat); !ok || chat.Data.Content != "hello" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for message")
+	}
+	if err := accepted.SendMsg(gosepp.NewChatMsg("conf", "welcome")); err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	select {
+	case msg := <-sepp.RcvCh():
+		if chat, ok := msg.(*gosepp.MsgChat); !ok || chat.Data.Content != "welcome" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for message")
+	}
+}
+
+func TestWebTransportUntrusted(t *testing.T) {
+	cert, _ := testCert(t)
+	listener := gosepp.NewListener(nil)
+	defer listener.Close()
+	addr := serve(t, listener, cert, make(chan http.Header, 1))
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s/call", Scheme, addr))
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := NewTransport(nil).Dial(ctx, u, nil); err == nil {
+		t.Fatalf("expected untrusted certificate to fail")
+	}
+}